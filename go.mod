@@ -23,7 +23,7 @@ require (
 	go.uber.org/goleak v0.10.0 // indirect
 	go.uber.org/zap v1.10.0
 	golang.org/x/crypto v0.0.0-20190923035154-9ee001bba392
-	golang.org/x/net v0.0.0-20190603091049-60506f45cf65 // indirect
+	golang.org/x/net v0.0.0-20190603091049-60506f45cf65
 	golang.org/x/text v0.3.2 // indirect
 	mellium.im/sasl v0.2.1 // indirect
 )
@@ -2,8 +2,10 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/playneta/go-sessions/src"
+	"github.com/playneta/go-sessions/src/services"
 	"github.com/urfave/cli"
 )
 
@@ -35,9 +37,137 @@ func main() {
 				src.Migrate(dir)
 			},
 		},
+		{
+			Name: "messages:prune",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "only count messages outside of retention policies",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				if err := src.Prune(ctx.Bool("dry-run")); err != nil {
+					return cli.NewExitError("error pruning messages: "+err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "messages:archive",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "days",
+					Usage: "archive messages older than days, archive.days when not set",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				if err := src.Archive(ctx.Int("days")); err != nil {
+					return cli.NewExitError("error archiving messages: "+err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "export:messages",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "conversation",
+					Usage: "conversation to export, every one when empty",
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Value: services.ExportNDJSON,
+					Usage: "ndjson, csv, text or html",
+				},
+				cli.StringFlag{
+					Name:  "since",
+					Usage: "RFC3339 time of the first exported message",
+				},
+				cli.StringFlag{
+					Name:  "until",
+					Usage: "RFC3339 time the export stops before",
+				},
+				cli.StringFlag{
+					Name:  "output",
+					Usage: "file to write transcript to, stdout when empty",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				req := services.ExportRequest{
+					Conversation: ctx.String("conversation"),
+					Format:       ctx.String("format"),
+				}
+
+				var err error
+				if req.Since, err = parseTime(ctx.String("since")); err != nil {
+					return err
+				}
+
+				if req.Until, err = parseTime(ctx.String("until")); err != nil {
+					return err
+				}
+
+				w := os.Stdout
+				if output := ctx.String("output"); output != "" {
+					if w, err = os.Create(output); err != nil {
+						return err
+					}
+					defer w.Close()
+				}
+
+				if err := src.Export(req, w); err != nil {
+					return cli.NewExitError("error exporting messages: "+err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "import:slack",
+			ArgsUsage: "<export.zip>",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "channel",
+					Usage: "channel to import, every one when not set",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				if ctx.NArg() != 1 {
+					return cli.NewExitError("path to slack export zip is required", 1)
+				}
+
+				if err := src.ImportSlack(ctx.Args().First(), ctx.StringSlice("channel")); err != nil {
+					return cli.NewExitError("error importing slack export: "+err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "users:reset-password",
+			ArgsUsage: "<email>",
+			Usage:     "print token the user sets new password with, imported users need one to sign in",
+			Action: func(ctx *cli.Context) error {
+				if ctx.NArg() != 1 {
+					return cli.NewExitError("email of the user is required", 1)
+				}
+
+				if err := src.IssuePasswordReset(ctx.Args().First()); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				return nil
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
 		panic(err)
 	}
 }
+
+// parseTime parses optional RFC3339 time flag
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE reactions (
+    id SERIAL PRIMARY KEY,
+    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id),
+    emoji character varying(32) NOT NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    UNIQUE (message_id, user_id, emoji)
+);
+
+CREATE INDEX reactions_message_id_idx ON reactions(message_id int4_ops);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE reactions;
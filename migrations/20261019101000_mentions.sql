@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE users ADD COLUMN role character varying(16) NOT NULL DEFAULT 'user';
+
+CREATE TABLE message_mentions (
+    id SERIAL PRIMARY KEY,
+    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id integer REFERENCES users(id),
+    kind character varying(8) NOT NULL DEFAULT 'user',
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX message_mentions_message_id_idx ON message_mentions(message_id int4_ops);
+CREATE INDEX message_mentions_user_id_idx ON message_mentions(user_id int4_ops);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE message_mentions;
+ALTER TABLE users DROP COLUMN role;
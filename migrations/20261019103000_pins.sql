@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE pins (
+    id SERIAL PRIMARY KEY,
+    message_id integer NOT NULL UNIQUE REFERENCES messages(id) ON DELETE CASCADE,
+    conversation character varying(64) NOT NULL,
+    user_id integer NOT NULL REFERENCES users(id),
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pins_conversation_idx ON pins(conversation);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE pins;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id),
+    message_id integer REFERENCES messages(id) ON DELETE CASCADE,
+    name character varying(255) NOT NULL,
+    content_type character varying(128) NOT NULL,
+    size bigint NOT NULL,
+    key character varying(255) NOT NULL UNIQUE,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX attachments_message_id_idx ON attachments(message_id int4_ops);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE attachments;
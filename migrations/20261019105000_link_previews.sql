@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE link_previews (
+    url text PRIMARY KEY,
+    title character varying(300) NOT NULL DEFAULT '',
+    description text NOT NULL DEFAULT '',
+    image text NOT NULL DEFAULT '',
+    site_name character varying(255) NOT NULL DEFAULT '',
+    failed boolean NOT NULL DEFAULT false,
+    fetched_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE message_previews (
+    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    url text NOT NULL REFERENCES link_previews(url) ON DELETE CASCADE,
+    position smallint NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, url)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE message_previews;
+DROP TABLE link_previews;
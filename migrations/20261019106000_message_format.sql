@@ -0,0 +1,7 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN format character varying(16) NOT NULL DEFAULT 'markdown';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE messages DROP COLUMN format;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN search tsvector;
+
+UPDATE messages SET search = to_tsvector('simple', text);
+
+-- +goose StatementBegin
+CREATE FUNCTION messages_search_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search := to_tsvector('simple', NEW.text);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER messages_search_update BEFORE INSERT OR UPDATE OF text ON messages
+    FOR EACH ROW EXECUTE PROCEDURE messages_search_update();
+
+CREATE INDEX messages_search_idx ON messages USING GIN (search);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_search_idx;
+DROP TRIGGER messages_search_update ON messages;
+DROP FUNCTION messages_search_update();
+ALTER TABLE messages DROP COLUMN search;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE scheduled_messages (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id integer REFERENCES users(id) ON DELETE CASCADE,
+    text text NOT NULL,
+    format character varying(16) NOT NULL DEFAULT 'markdown',
+    send_at timestamp without time zone NOT NULL,
+    status character varying(16) NOT NULL DEFAULT 'pending',
+    message_id integer REFERENCES messages(id) ON DELETE SET NULL,
+    error text NOT NULL DEFAULT '',
+    claimed_until timestamp without time zone,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX scheduled_messages_pending_idx ON scheduled_messages(send_at) WHERE status = 'pending';
+CREATE INDEX scheduled_messages_user_id_idx ON scheduled_messages(user_id int4_ops);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE scheduled_messages;
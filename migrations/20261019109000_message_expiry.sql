@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN expires_at timestamp without time zone;
+
+CREATE INDEX messages_expires_at_idx ON messages(expires_at) WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_expires_at_idx;
+ALTER TABLE messages DROP COLUMN expires_at;
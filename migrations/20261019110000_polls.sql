@@ -0,0 +1,35 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN type character varying(16) NOT NULL DEFAULT 'text';
+
+CREATE TABLE polls (
+    message_id integer PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+    multiple boolean NOT NULL DEFAULT false,
+    anonymous boolean NOT NULL DEFAULT false,
+    closes_at timestamp without time zone,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE poll_options (
+    id SERIAL PRIMARY KEY,
+    message_id integer NOT NULL REFERENCES polls(message_id) ON DELETE CASCADE,
+    position smallint NOT NULL,
+    text character varying(200) NOT NULL
+);
+
+CREATE INDEX poll_options_message_id_idx ON poll_options(message_id int4_ops);
+
+CREATE TABLE poll_votes (
+    message_id integer NOT NULL REFERENCES polls(message_id) ON DELETE CASCADE,
+    option_id integer NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, option_id, user_id)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
+ALTER TABLE messages DROP COLUMN type;
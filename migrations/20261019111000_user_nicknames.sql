@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE users ADD COLUMN nickname character varying(32);
+
+CREATE UNIQUE INDEX users_nickname_idx ON users(lower(nickname));
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX users_nickname_idx;
+
+ALTER TABLE users DROP COLUMN nickname;
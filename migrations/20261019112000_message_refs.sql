@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN ref_id integer REFERENCES messages(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN ref jsonb;
+
+CREATE INDEX messages_ref_id_idx ON messages(ref_id int4_ops) WHERE ref_id IS NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_ref_id_idx;
+
+ALTER TABLE messages DROP COLUMN ref;
+ALTER TABLE messages DROP COLUMN ref_id;
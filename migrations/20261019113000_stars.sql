@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE stars (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    UNIQUE (user_id, message_id)
+);
+
+CREATE INDEX stars_message_id_idx ON stars(message_id int4_ops);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE stars;
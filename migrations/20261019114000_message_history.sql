@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE INDEX messages_public_history_idx ON messages(created_at, id) WHERE receiver_id IS NULL;
+CREATE INDEX messages_private_history_idx ON messages(least(user_id, receiver_id), greatest(user_id, receiver_id), created_at, id)
+    WHERE receiver_id IS NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_private_history_idx;
+DROP INDEX messages_public_history_idx;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN client_msg_id character varying(64);
+
+CREATE UNIQUE INDEX messages_client_msg_id_idx ON messages(user_id, client_msg_id) WHERE client_msg_id IS NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_client_msg_id_idx;
+
+ALTER TABLE messages DROP COLUMN client_msg_id;
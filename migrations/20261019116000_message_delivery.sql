@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE messages ADD COLUMN delivered_at timestamp without time zone;
+
+-- Messages sent before delivery tracking are not queued again
+UPDATE messages SET delivered_at = created_at WHERE receiver_id IS NOT NULL;
+
+CREATE INDEX messages_undelivered_idx ON messages(receiver_id int4_ops, id int4_ops)
+    WHERE receiver_id IS NOT NULL AND delivered_at IS NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX messages_undelivered_idx;
+
+ALTER TABLE messages DROP COLUMN delivered_at;
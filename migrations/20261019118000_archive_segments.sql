@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE archive_segments (
+    id SERIAL PRIMARY KEY,
+    conversation character varying(64) NOT NULL,
+    month date NOT NULL,
+    key character varying(255) NOT NULL UNIQUE,
+    count integer NOT NULL,
+    min_id integer NOT NULL,
+    max_id integer NOT NULL,
+    first_at timestamp without time zone NOT NULL,
+    last_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX archive_segments_conversation_idx ON archive_segments(conversation, first_at, last_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE archive_segments;
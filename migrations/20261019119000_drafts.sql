@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE drafts (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    conversation character varying(64) NOT NULL,
+    text text NOT NULL,
+    updated_at timestamp without time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, conversation)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE drafts;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Only sha256 of reset token is stored, token itself is handed to the user once
+ALTER TABLE users ADD COLUMN reset_token character varying(64);
+ALTER TABLE users ADD COLUMN reset_expires_at timestamp without time zone;
+CREATE UNIQUE INDEX users_reset_token_idx ON users(reset_token);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX users_reset_token_idx;
+ALTER TABLE users DROP COLUMN reset_expires_at;
+ALTER TABLE users DROP COLUMN reset_token;
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"expvar"
 	"net/http"
 
 	"github.com/labstack/echo"
@@ -16,33 +17,66 @@ import (
 type (
 	// API structure represetns and handles api interaction
 	API struct {
-		logger         *zap.SugaredLogger
-		config         *viper.Viper
-		userRepo       repositories.User
-		accountService services.Account
-		echo           *echo.Echo
+		logger            *zap.SugaredLogger
+		config            *viper.Viper
+		userRepo          repositories.User
+		accountService    services.Account
+		reactionService   services.Reaction
+		mentionService    services.Mention
+		readService       services.Read
+		pinService        services.Pin
+		attachmentService services.Attachment
+		searchService     services.Search
+		scheduleService   services.Schedule
+		pollService       services.Poll
+		starService       services.Star
+		exportService     services.Export
+		draftService      services.Draft
+		echo              *echo.Echo
 	}
 
 	// Options represetns api options
 	Options struct {
 		fx.In
 
-		Logger         *zap.SugaredLogger
-		Config         *viper.Viper
-		UserRepo       repositories.User
-		AccountService services.Account
-		Lc             fx.Lifecycle
+		Logger            *zap.SugaredLogger
+		Config            *viper.Viper
+		UserRepo          repositories.User
+		AccountService    services.Account
+		ReactionService   services.Reaction
+		MentionService    services.Mention
+		ReadService       services.Read
+		PinService        services.Pin
+		AttachmentService services.Attachment
+		SearchService     services.Search
+		ScheduleService   services.Schedule
+		PollService       services.Poll
+		StarService       services.Star
+		ExportService     services.Export
+		DraftService      services.Draft
+		Lc                fx.Lifecycle
 	}
 )
 
 // New creates new instance of API and inject it into fx.Lifecycle
 func New(opts Options) *API {
 	a := &API{
-		logger:         opts.Logger,
-		config:         opts.Config,
-		userRepo:       opts.UserRepo,
-		accountService: opts.AccountService,
-		echo:           echo.New(),
+		logger:            opts.Logger,
+		config:            opts.Config,
+		userRepo:          opts.UserRepo,
+		accountService:    opts.AccountService,
+		reactionService:   opts.ReactionService,
+		mentionService:    opts.MentionService,
+		readService:       opts.ReadService,
+		pinService:        opts.PinService,
+		attachmentService: opts.AttachmentService,
+		searchService:     opts.SearchService,
+		scheduleService:   opts.ScheduleService,
+		pollService:       opts.PollService,
+		starService:       opts.StarService,
+		exportService:     opts.ExportService,
+		draftService:      opts.DraftService,
+		echo:              echo.New(),
 	}
 
 	a.echo.HidePort = true
@@ -51,14 +85,46 @@ func New(opts Options) *API {
 	// CORS
 	a.echo.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
 	}))
 
 	// Endpoint
 	a.echo.POST("/register", a.Register)
 	a.echo.POST("/sign-in", a.SignIn)
+	a.echo.POST("/password-reset", a.ResetPassword)
 
 	a.echo.GET("/profile", a.Profile, a.AuthMiddleware)
+	a.echo.GET("/messages", a.Messages, a.AuthMiddleware)
+	a.echo.GET("/messages/search", a.Search, a.AuthMiddleware)
+	a.echo.POST("/messages/:id/reactions", a.AddReaction, a.AuthMiddleware)
+	a.echo.DELETE("/messages/:id/reactions/:emoji", a.RemoveReaction, a.AuthMiddleware)
+	a.echo.GET("/mentions", a.Mentions, a.AuthMiddleware)
+	a.echo.GET("/read", a.ReadState, a.AuthMiddleware)
+	a.echo.GET("/read/:conversation", a.ConversationReadState, a.AuthMiddleware)
+	a.echo.POST("/messages/:id/pin", a.Pin, a.AuthMiddleware)
+	a.echo.DELETE("/messages/:id/pin", a.Unpin, a.AuthMiddleware)
+	a.echo.GET("/pins", a.Pins, a.AuthMiddleware)
+	a.echo.POST("/attachments", a.Upload, a.AuthMiddleware)
+	a.echo.GET("/attachments/:id", a.AttachmentURL, a.AuthMiddleware)
+	a.echo.GET("/attachments/:id/download", a.Download)
+	a.echo.POST("/polls", a.CreatePoll, a.AuthMiddleware)
+	a.echo.POST("/messages/:id/votes", a.Vote, a.AuthMiddleware)
+	a.echo.POST("/messages/:id/star", a.Star, a.AuthMiddleware)
+	a.echo.DELETE("/messages/:id/star", a.Unstar, a.AuthMiddleware)
+	a.echo.GET("/saved", a.Saved, a.AuthMiddleware)
+	a.echo.GET("/export", a.Export, a.AuthMiddleware)
+	a.echo.PUT("/drafts/:conversation", a.SaveDraft, a.AuthMiddleware)
+	a.echo.GET("/drafts/:conversation", a.Draft, a.AuthMiddleware)
+	a.echo.DELETE("/drafts/:conversation", a.DeleteDraft, a.AuthMiddleware)
+	a.echo.POST("/scheduled", a.CreateScheduled, a.AuthMiddleware)
+	a.echo.GET("/scheduled", a.ListScheduled, a.AuthMiddleware)
+	a.echo.PUT("/scheduled/:id", a.UpdateScheduled, a.AuthMiddleware)
+	a.echo.DELETE("/scheduled/:id", a.CancelScheduled, a.AuthMiddleware)
+
+	// Metrics include process command line so they are exposed only on demand
+	if opts.Config.GetBool("api.metrics") {
+		a.echo.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+	}
 
 	// Start & Stop server
 	opts.Lc.Append(fx.Hook{
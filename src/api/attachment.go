@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+// multipartOverhead is allowance for multipart boundaries and headers above file size
+const multipartOverhead = 1 << 20
+
+func (a *API) Upload(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, a.config.GetInt64("attachments.max_size")+multipartOverhead)
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer file.Close()
+
+	attachment, err := a.attachmentService.Upload(*user, header.Filename, header.Size, file)
+	if err != nil {
+		return attachmentError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, attachment)
+}
+
+func (a *API) AttachmentURL(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed attachment id")
+	}
+
+	url, expires, err := a.attachmentService.URL(*user, id)
+	if err != nil {
+		return attachmentError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, AttachmentURLResponse{
+		URL:       url,
+		ExpiresAt: expires,
+	})
+}
+
+// Download serves attachment by signed link, it does not require token
+// so links could be used directly in browser
+func (a *API) Download(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed attachment id")
+	}
+
+	expires, err := strconv.ParseInt(ctx.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, services.ErrLinkExpired.Error())
+	}
+
+	attachment, content, err := a.attachmentService.Open(id, expires, ctx.QueryParam("signature"))
+	if err != nil {
+		return attachmentError(err)
+	}
+	defer content.Close()
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	header.Set(echo.HeaderContentDisposition, "attachment; filename="+strconv.Quote(attachment.Name))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "private, max-age="+strconv.FormatInt(expires-time.Now().Unix(), 10))
+
+	return ctx.Stream(http.StatusOK, attachment.ContentType, content)
+}
+
+func attachmentError(err error) error {
+	switch err {
+	case services.ErrAttachmentNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrAttachmentTooLarge:
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	case services.ErrLinkExpired:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
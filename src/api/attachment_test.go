@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload(t *testing.T) {
+	t.Run("No file", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		err := suite.api.Upload(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "hello.txt")
+		require.NoError(t, err)
+		part.Write([]byte("hello"))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/attachments", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		suite.context.SetRequest(req)
+
+		attachment := &models.Attachment{Id: 5, UserId: 1, Name: "hello.txt", ContentType: "text/plain; charset=utf-8", Size: 5}
+		suite.attachmentService.EXPECT().Upload(*suite.user, "hello.txt", int64(5), gomock.Any()).Return(attachment, nil)
+
+		err = suite.api.Upload(suite.context)
+		require.NoError(t, err)
+
+		{
+			a := new(models.Attachment)
+			err := json.NewDecoder(suite.recorder.Body).Decode(a)
+			require.NoError(t, err)
+			require.Equal(t, attachment, a)
+		}
+	})
+}
+
+func TestAttachmentURL(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.attachmentService.EXPECT().URL(*suite.user, int64(5)).Return("", time.Time{}, services.ErrAttachmentNotFound)
+
+		err := suite.api.AttachmentURL(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		expires := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.attachmentService.EXPECT().URL(*suite.user, int64(5)).Return("/attachments/5/download?expires=1&signature=abc", expires, nil)
+
+		err := suite.api.AttachmentURL(suite.context)
+		require.NoError(t, err)
+
+		{
+			res := new(AttachmentURLResponse)
+			err := json.NewDecoder(suite.recorder.Body).Decode(res)
+			require.NoError(t, err)
+			require.Equal(t, "/attachments/5/download?expires=1&signature=abc", res.URL)
+			require.True(t, expires.Equal(res.ExpiresAt))
+		}
+	})
+}
+
+func TestDownload(t *testing.T) {
+	t.Run("Expired", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.context.QueryParams().Set("expires", "1")
+		suite.context.QueryParams().Set("signature", "abc")
+		suite.attachmentService.EXPECT().Open(int64(5), int64(1), "abc").Return(nil, nil, services.ErrLinkExpired)
+
+		err := suite.api.Download(suite.context)
+		require.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		defer suite.close()
+
+		attachment := &models.Attachment{Id: 5, Name: "page.html", ContentType: "text/html; charset=utf-8", Size: 5}
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.context.QueryParams().Set("expires", "1")
+		suite.context.QueryParams().Set("signature", "abc")
+		suite.attachmentService.EXPECT().Open(int64(5), int64(1), "abc").
+			Return(attachment, ioutil.NopCloser(bytes.NewBufferString("hello")), nil)
+
+		err := suite.api.Download(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, "hello", suite.recorder.Body.String())
+		require.Equal(t, "nosniff", suite.recorder.Header().Get("X-Content-Type-Options"))
+		require.Equal(t, `attachment; filename="page.html"`, suite.recorder.Header().Get(echo.HeaderContentDisposition))
+	})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) SaveDraft(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	var req DraftRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	draft, err := a.draftService.Save(*user, ctx.Param("conversation"), req.Text)
+	if err != nil {
+		return draftError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, draft)
+}
+
+func (a *API) Draft(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	draft, err := a.draftService.Find(*user, ctx.Param("conversation"))
+	if err != nil {
+		return draftError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, draft)
+}
+
+func (a *API) DeleteDraft(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	if err := a.draftService.Delete(*user, ctx.Param("conversation")); err != nil {
+		return draftError(err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func draftError(err error) error {
+	switch err {
+	case services.ErrConversationNotFound, services.ErrDraftNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrEmptyText, services.ErrMalformedDraft, services.ErrDraftTooLong:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
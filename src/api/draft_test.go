@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDraft(t *testing.T) {
+	t.Run("Too long", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPut, strings.NewReader(`{"text":"long"}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.draftService.EXPECT().Save(*suite.user, "public", "long").Return(nil, services.ErrDraftTooLong)
+
+		err := suite.api.SaveDraft(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Foreign conversation", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPut, strings.NewReader(`{"text":"hi"}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("dm:2:3")
+		suite.draftService.EXPECT().Save(*suite.user, "dm:2:3", "hi").Return(nil, services.ErrConversationNotFound)
+
+		err := suite.api.SaveDraft(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPut, strings.NewReader(`{"text":"hi"}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		draft := &models.Draft{Conversation: "dm:1:2", Text: "hi", UpdatedAt: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)}
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("dm:1:2")
+		suite.draftService.EXPECT().Save(*suite.user, "dm:1:2", "hi").Return(draft, nil)
+
+		err := suite.api.SaveDraft(suite.context)
+		require.NoError(t, err)
+
+		{
+			d := new(models.Draft)
+			err := json.NewDecoder(suite.recorder.Body).Decode(d)
+			require.NoError(t, err)
+			require.Equal(t, draft, d)
+		}
+	})
+}
+
+func TestDraft(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.draftService.EXPECT().Find(*suite.user, "public").Return(nil, services.ErrDraftNotFound)
+
+		err := suite.api.Draft(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.draftService.EXPECT().Find(*suite.user, "public").Return(&models.Draft{Conversation: "public", Text: "hi"}, nil)
+
+		err := suite.api.Draft(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, suite.recorder.Code)
+		require.Contains(t, suite.recorder.Body.String(), `"text":"hi"`)
+	})
+}
+
+func TestDeleteDraft(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.draftService.EXPECT().Delete(*suite.user, "public").Return(services.ErrDraftNotFound)
+
+		err := suite.api.DeleteDraft(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.draftService.EXPECT().Delete(*suite.user, "public").Return(nil)
+
+		err := suite.api.DeleteDraft(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, suite.recorder.Code)
+	})
+}
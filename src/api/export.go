@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+// Export streams transcript of conversation, admins may omit conversation to export everything
+func (a *API) Export(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	req := services.ExportRequest{
+		Conversation: ctx.QueryParam("conversation"),
+		Format:       ctx.QueryParam("format"),
+	}
+
+	if req.Format == "" {
+		req.Format = services.ExportNDJSON
+	}
+
+	format, ok := services.ExportFormats[req.Format]
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, services.ErrMalformedExportFormat.Error())
+	}
+
+	var err error
+	if req.Since, err = queryTime(ctx, "since"); err != nil {
+		return err
+	}
+
+	if req.Until, err = queryTime(ctx, "until"); err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=transcript.%s", format.Extension))
+
+	if err := a.exportService.Export(*user, req, res); err != nil {
+		// Transcript is cut short, status was already sent
+		if res.Committed {
+			a.logger.Errorf("error exporting messages: %v", err)
+			return nil
+		}
+
+		res.Header().Del(echo.HeaderContentDisposition)
+		return exportError(err)
+	}
+
+	return nil
+}
+
+func exportError(err error) error {
+	switch err {
+	case services.ErrMalformedExportFormat, services.ErrMalformedExportRange:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case services.ErrExportNotAllowed:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case services.ErrConversationNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	t.Run("Malformed format", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("format", "xml")
+
+		err := suite.api.Export(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Everything by user", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.exportService.EXPECT().Export(*suite.user, services.ExportRequest{Format: services.ExportNDJSON}, gomock.Any()).
+			Return(services.ErrExportNotAllowed)
+
+		err := suite.api.Export(suite.context)
+		require.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+		require.Empty(t, suite.recorder.Header().Get(echo.HeaderContentDisposition))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("conversation", models.PublicConversation)
+		suite.context.QueryParams().Set("format", services.ExportCSV)
+		suite.context.QueryParams().Set("since", "2026-10-01T00:00:00Z")
+		suite.exportService.EXPECT().Export(*suite.user, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(user models.User, req services.ExportRequest, w io.Writer) error {
+				require.Equal(t, models.PublicConversation, req.Conversation)
+				require.Equal(t, 2026, req.Since.Year())
+				require.True(t, req.Until.IsZero())
+
+				_, err := io.WriteString(w, "id\n")
+				return err
+			})
+
+		err := suite.api.Export(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, suite.recorder.Code)
+		require.Equal(t, "text/csv; charset=utf-8", suite.recorder.Header().Get(echo.HeaderContentType))
+		require.Equal(t, "attachment; filename=transcript.csv", suite.recorder.Header().Get(echo.HeaderContentDisposition))
+		require.Equal(t, "id\n", suite.recorder.Body.String())
+	})
+}
@@ -12,18 +12,31 @@ import (
 	"github.com/playneta/go-sessions/src/models"
 	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
 	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
 type suite struct {
-	gmock          *gomock.Controller
-	userRepo       *mock_repositories.MockUser
-	accountService *mock_services.MockAccount
-	user           *models.User
-	context        echo.Context
-	request        *http.Request
-	recorder       *httptest.ResponseRecorder
-	api            *API
+	gmock             *gomock.Controller
+	userRepo          *mock_repositories.MockUser
+	accountService    *mock_services.MockAccount
+	reactionService   *mock_services.MockReaction
+	mentionService    *mock_services.MockMention
+	readService       *mock_services.MockRead
+	pinService        *mock_services.MockPin
+	attachmentService *mock_services.MockAttachment
+	searchService     *mock_services.MockSearch
+	scheduleService   *mock_services.MockSchedule
+	pollService       *mock_services.MockPoll
+	starService       *mock_services.MockStar
+	exportService     *mock_services.MockExport
+	draftService      *mock_services.MockDraft
+	user              *models.User
+	context           echo.Context
+	request           *http.Request
+	recorder          *httptest.ResponseRecorder
+	api               *API
 }
 
 func newTestSuite(t *testing.T, method string, body io.Reader, headers map[string]string) *suite {
@@ -32,6 +45,17 @@ func newTestSuite(t *testing.T, method string, body io.Reader, headers map[strin
 
 	userRepo := mock_repositories.NewMockUser(ctrl)
 	accountService := mock_services.NewMockAccount(ctrl)
+	reactionService := mock_services.NewMockReaction(ctrl)
+	mentionService := mock_services.NewMockMention(ctrl)
+	readService := mock_services.NewMockRead(ctrl)
+	pinService := mock_services.NewMockPin(ctrl)
+	attachmentService := mock_services.NewMockAttachment(ctrl)
+	searchService := mock_services.NewMockSearch(ctrl)
+	scheduleService := mock_services.NewMockSchedule(ctrl)
+	pollService := mock_services.NewMockPoll(ctrl)
+	starService := mock_services.NewMockStar(ctrl)
+	exportService := mock_services.NewMockExport(ctrl)
+	draftService := mock_services.NewMockDraft(ctrl)
 
 	// Basic setup
 	e := echo.New()
@@ -48,19 +72,42 @@ func newTestSuite(t *testing.T, method string, body io.Reader, headers map[strin
 
 	// Creating instance of API
 	a := &API{
-		logger:         logger,
-		accountService: accountService,
-		userRepo:       userRepo,
+		logger:            logger,
+		config:            viper.New(),
+		accountService:    accountService,
+		reactionService:   reactionService,
+		mentionService:    mentionService,
+		readService:       readService,
+		pinService:        pinService,
+		attachmentService: attachmentService,
+		searchService:     searchService,
+		scheduleService:   scheduleService,
+		pollService:       pollService,
+		starService:       starService,
+		exportService:     exportService,
+		draftService:      draftService,
+		userRepo:          userRepo,
 	}
 
 	return &suite{
-		gmock:          ctrl,
-		userRepo:       userRepo,
-		accountService: accountService,
-		request:        req,
-		recorder:       rec,
-		context:        c,
-		api:            a,
+		gmock:             ctrl,
+		userRepo:          userRepo,
+		accountService:    accountService,
+		reactionService:   reactionService,
+		mentionService:    mentionService,
+		readService:       readService,
+		pinService:        pinService,
+		attachmentService: attachmentService,
+		searchService:     searchService,
+		scheduleService:   scheduleService,
+		pollService:       pollService,
+		starService:       starService,
+		exportService:     exportService,
+		draftService:      draftService,
+		request:           req,
+		recorder:          rec,
+		context:           c,
+		api:               a,
 	}
 }
 
@@ -83,3 +130,14 @@ func (s *suite) authorize() {
 func (s *suite) close() {
 	s.gmock.Finish()
 }
+
+// author returns other user as loaded with messages, along with secrets which must not be served
+func author() *models.User {
+	return &models.User{ID: 2, Email: "author@example.com", Password: "author_password_hash", Token: "author_token"}
+}
+
+// requireNoSecrets fails when response exposes password hash or session token of author
+func requireNoSecrets(t *testing.T, body string) {
+	require.NotContains(t, body, "author_password_hash")
+	require.NotContains(t, body, "author_token")
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+)
+
+// Messages returns page of conversation history, public one by default
+func (a *API) Messages(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	req := services.HistoryRequest{
+		Conversation: ctx.QueryParam("conversation"),
+		Limit:        defaultHistoryLimit,
+	}
+
+	if req.Conversation == "" {
+		req.Conversation = models.PublicConversation
+	}
+
+	if v := ctx.QueryParam("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed limit")
+		}
+
+		if l < maxHistoryLimit {
+			req.Limit = l
+		} else {
+			req.Limit = maxHistoryLimit
+		}
+	}
+
+	var err error
+	if req.Before, err = queryID(ctx, "before"); err != nil {
+		return err
+	}
+
+	if req.After, err = queryID(ctx, "after"); err != nil {
+		return err
+	}
+
+	page, err := a.accountService.Messages(*user, req)
+	if err != nil {
+		switch err {
+		case services.ErrConversationNotFound:
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, page)
+}
+
+// queryID parses optional id query parameter, zero when it is missing
+func queryID(ctx echo.Context, param string) (int64, error) {
+	v := ctx.QueryParam(param)
+	if v == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "malformed "+param)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages(t *testing.T) {
+	t.Run("Malformed cursor", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("before", "-1")
+
+		err := suite.api.Messages(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Foreign conversation", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("conversation", "dm:2:3")
+		suite.accountService.EXPECT().Messages(*suite.user, services.HistoryRequest{
+			Conversation: "dm:2:3",
+			Limit:        defaultHistoryLimit,
+		}).Return(nil, services.ErrConversationNotFound)
+
+		err := suite.api.Messages(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		page := &models.HistoryPage{
+			Messages: []models.Message{{Id: 8, Text: "hello"}},
+			Before:   8,
+			After:    8,
+		}
+		suite.context.QueryParams().Set("before", "9")
+		suite.context.QueryParams().Set("limit", "1000")
+		suite.accountService.EXPECT().Messages(*suite.user, services.HistoryRequest{
+			Conversation: models.PublicConversation,
+			Before:       9,
+			Limit:        maxHistoryLimit,
+		}).Return(page, nil)
+
+		err := suite.api.Messages(suite.context)
+		require.NoError(t, err)
+
+		{
+			p := new(models.HistoryPage)
+			err := json.NewDecoder(suite.recorder.Body).Decode(p)
+			require.NoError(t, err)
+			require.Equal(t, int64(8), p.Before)
+			require.Len(t, p.Messages, 1)
+			require.Equal(t, "hello", p.Messages[0].Text)
+		}
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		page := &models.HistoryPage{
+			Messages: []models.Message{{Id: 8, Text: "hello", User: author(), Receiver: author()}},
+		}
+		suite.context.QueryParams().Set("conversation", "dm:1:2")
+		suite.accountService.EXPECT().Messages(*suite.user, services.HistoryRequest{
+			Conversation: "dm:1:2",
+			Limit:        defaultHistoryLimit,
+		}).Return(page, nil)
+
+		err := suite.api.Messages(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
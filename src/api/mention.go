@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+const (
+	defaultMentionsLimit = 50
+	maxMentionsLimit     = 100
+)
+
+func (a *API) Mentions(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	limit := defaultMentionsLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed limit")
+		}
+
+		if l < maxMentionsLimit {
+			limit = l
+		} else {
+			limit = maxMentionsLimit
+		}
+	}
+
+	mentions, err := a.mentionService.List(*user, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, mentions)
+}
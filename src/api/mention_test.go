@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentions(t *testing.T) {
+	t.Run("Bad limit", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("limit", "-1")
+
+		err := suite.api.Mentions(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.mentionService.EXPECT().List(*suite.user, defaultMentionsLimit).Return(nil, errors.New("database error"))
+
+		err := suite.api.Mentions(suite.context)
+		require.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		mentions := []models.Mention{
+			{Id: 1, MessageId: 10, UserId: 1, Kind: models.MentionUser},
+			{Id: 2, MessageId: 11, Kind: models.MentionAll},
+		}
+
+		suite.context.QueryParams().Set("limit", "1000")
+		suite.mentionService.EXPECT().List(*suite.user, maxMentionsLimit).Return(mentions, nil)
+
+		err := suite.api.Mentions(suite.context)
+		require.NoError(t, err)
+
+		{
+			var m []models.Mention
+			err := json.NewDecoder(suite.recorder.Body).Decode(&m)
+			require.NoError(t, err)
+			require.Equal(t, mentions, m)
+		}
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		mentions := []models.Mention{
+			{Id: 1, MessageId: 10, UserId: 1, Kind: models.MentionUser, Message: &models.Message{Id: 10, User: author(), Receiver: suite.user}},
+		}
+		suite.mentionService.EXPECT().List(*suite.user, defaultMentionsLimit).Return(mentions, nil)
+
+		err := suite.api.Mentions(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
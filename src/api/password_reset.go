@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+// ResetPassword sets password with token issued by operator, user signs in afterwards
+func (a *API) ResetPassword(ctx echo.Context) error {
+	var req PasswordResetRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := a.accountService.ResetPassword(req.Token, req.Password)
+	if err != nil {
+		switch err {
+		case services.ErrPasswordToSmall, services.ErrResetTokenInvalid:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
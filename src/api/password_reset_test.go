@@ -0,0 +1,37 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPassword(t *testing.T) {
+	request := []byte(`{"token": "reset", "password": "123456"}`)
+
+	t.Run("Invalid token", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, bytes.NewBuffer(request), nil)
+		defer suite.close()
+
+		suite.accountService.EXPECT().ResetPassword("reset", "123456").Return(nil, services.ErrResetTokenInvalid)
+
+		err := suite.api.ResetPassword(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, bytes.NewBuffer(request), nil)
+		defer suite.close()
+
+		suite.accountService.EXPECT().ResetPassword("reset", "123456").Return(&models.User{ID: 1, Email: "user@example.com"}, nil)
+
+		err := suite.api.ResetPassword(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), `"password_reset":false`)
+	})
+}
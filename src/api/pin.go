@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) Pin(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	pin, err := a.pinService.Pin(*user, id)
+	if err != nil {
+		return pinError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, pin)
+}
+
+func (a *API) Unpin(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	if err := a.pinService.Unpin(*user, id); err != nil {
+		return pinError(err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *API) Pins(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	pins, err := a.pinService.List(*user, ctx.QueryParam("conversation"))
+	if err != nil {
+		return pinError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, pins)
+}
+
+func pinError(err error) error {
+	switch err {
+	case services.ErrMessageNotFound, services.ErrPinNotFound, services.ErrConversationNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrTooManyPins:
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case services.ErrPinForbidden:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPin(t *testing.T) {
+	t.Run("Too many pins", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pinService.EXPECT().Pin(*suite.user, int64(10)).Return(nil, services.ErrTooManyPins)
+
+		err := suite.api.Pin(suite.context)
+		require.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		pin := &models.Pin{Id: 1, MessageId: 10, Conversation: "public", UserId: 1}
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pinService.EXPECT().Pin(*suite.user, int64(10)).Return(pin, nil)
+
+		err := suite.api.Pin(suite.context)
+		require.NoError(t, err)
+
+		{
+			p := new(models.Pin)
+			err := json.NewDecoder(suite.recorder.Body).Decode(p)
+			require.NoError(t, err)
+			require.Equal(t, pin, p)
+		}
+	})
+}
+
+func TestUnpin(t *testing.T) {
+	t.Run("Forbidden", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pinService.EXPECT().Unpin(*suite.user, int64(10)).Return(services.ErrPinForbidden)
+
+		err := suite.api.Unpin(suite.context)
+		require.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pinService.EXPECT().Unpin(*suite.user, int64(10)).Return(nil)
+
+		err := suite.api.Unpin(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, suite.recorder.Code)
+	})
+}
+
+func TestPins(t *testing.T) {
+	t.Run("Foreign conversation", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("conversation", "dm:2:3")
+		suite.pinService.EXPECT().List(*suite.user, "dm:2:3").Return(nil, services.ErrConversationNotFound)
+
+		err := suite.api.Pins(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		pins := []models.Pin{{Id: 1, MessageId: 20, Conversation: "public", UserId: 2, User: author(), Message: &models.Message{Id: 20, User: author()}}}
+		suite.context.QueryParams().Set("conversation", "public")
+		suite.pinService.EXPECT().List(*suite.user, "public").Return(pins, nil)
+
+		err := suite.api.Pins(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) CreatePoll(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	var req PollRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	message, err := a.pollService.Create(*user, services.PollRequest{
+		To:        req.To,
+		Text:      req.Text,
+		Format:    req.Format,
+		Options:   req.Options,
+		Multiple:  req.Multiple,
+		Anonymous: req.Anonymous,
+		ClosesAt:  req.ClosesAt,
+	})
+	if err != nil {
+		return pollError(err)
+	}
+
+	return ctx.JSON(http.StatusCreated, message)
+}
+
+func (a *API) Vote(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	var req VoteRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	poll, err := a.pollService.Vote(*user, id, req.Options)
+	if err != nil {
+		return pollError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, poll)
+}
+
+func pollError(err error) error {
+	switch err {
+	case services.ErrMessageNotFound, services.ErrPollNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrPollClosed:
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case services.ErrMalformedPoll, services.ErrMalformedClosingAt, services.ErrMalformedVote,
+		services.ErrMentionNotAllowed, services.ErrMessageTooLong, services.ErrMalformedText, services.ErrMalformedFormat:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
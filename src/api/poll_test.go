@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePoll(t *testing.T) {
+	t.Run("Malformed", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"text":"?","options":["a"]}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.pollService.EXPECT().Create(*suite.user, services.PollRequest{Text: "?", Options: []string{"a"}}).
+			Return(nil, services.ErrMalformedPoll)
+
+		err := suite.api.CreatePoll(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"text":"Lunch?","options":["pizza","sushi"],"anonymous":true}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		message := &models.Message{
+			Id:   10,
+			Type: models.MessagePoll,
+			Text: "Lunch?",
+			Poll: &models.Poll{MessageId: 10, Anonymous: true, Options: []models.PollOption{{Id: 1, Text: "pizza"}, {Id: 2, Text: "sushi"}}},
+		}
+		suite.pollService.EXPECT().Create(*suite.user, services.PollRequest{
+			Text:      "Lunch?",
+			Options:   []string{"pizza", "sushi"},
+			Anonymous: true,
+		}).Return(message, nil)
+
+		err := suite.api.CreatePoll(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, suite.recorder.Code)
+
+		{
+			m := new(models.Message)
+			err := json.NewDecoder(suite.recorder.Body).Decode(m)
+			require.NoError(t, err)
+			require.Equal(t, models.MessagePoll, m.Type)
+			require.Len(t, m.Poll.Options, 2)
+		}
+	})
+}
+
+func TestVote(t *testing.T) {
+	t.Run("Closed", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"options":[1]}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pollService.EXPECT().Vote(*suite.user, int64(10), []int64{1}).Return(nil, services.ErrPollClosed)
+
+		err := suite.api.Vote(suite.context)
+		require.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"options":[1]}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		poll := &models.Poll{MessageId: 10, Voters: 1, Options: []models.PollOption{{Id: 1, Text: "pizza", Votes: 1, Me: true}}}
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.pollService.EXPECT().Vote(*suite.user, int64(10), []int64{1}).Return(poll, nil)
+
+		err := suite.api.Vote(suite.context)
+		require.NoError(t, err)
+
+		{
+			p := new(models.Poll)
+			err := json.NewDecoder(suite.recorder.Body).Decode(p)
+			require.NoError(t, err)
+			require.Equal(t, poll, p)
+		}
+	})
+}
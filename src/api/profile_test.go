@@ -25,6 +25,10 @@ func TestProfile(t *testing.T) {
 		checkUser := new(models.User)
 		err := json.NewDecoder(suite.recorder.Body).Decode(checkUser)
 		require.NoError(t, err)
-		assert.Equal(t, suite.user, checkUser)
+
+		// Password hash never leaves the server
+		expected := *suite.user
+		expected.Password = ""
+		assert.Equal(t, &expected, checkUser)
 	}
 }
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) AddReaction(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	var req ReactionRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	message, err := a.reactionService.Add(*user, id, req.Emoji)
+	if err != nil {
+		return reactionError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, message)
+}
+
+func (a *API) RemoveReaction(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	emoji, err := url.PathUnescape(ctx.Param("emoji"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	message, err := a.reactionService.Remove(*user, id, emoji)
+	if err != nil {
+		return reactionError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, message)
+}
+
+func reactionError(err error) error {
+	switch err {
+	case services.ErrMessageNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrMalformedEmoji:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddReaction(t *testing.T) {
+	request := []byte(`{"emoji": "👍"}`)
+
+	t.Run("Bad message id", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, bytes.NewBuffer(request), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("abc")
+
+		err := suite.api.AddReaction(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Message not found", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, bytes.NewBuffer(request), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.reactionService.EXPECT().Add(*suite.user, int64(10), "👍").Return(nil, services.ErrMessageNotFound)
+
+		err := suite.api.AddReaction(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, bytes.NewBuffer(request), nil)
+		suite.authorize()
+		defer suite.close()
+
+		message := &models.Message{
+			Id:        10,
+			Text:      "text",
+			User:      author(),
+			Reactions: []models.ReactionSummary{{Emoji: "👍", Count: 1, Me: true}},
+		}
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.reactionService.EXPECT().Add(*suite.user, int64(10), "👍").Return(message, nil)
+
+		err := suite.api.AddReaction(suite.context)
+		require.NoError(t, err)
+		requireNoSecrets(t, suite.recorder.Body.String())
+
+		{
+			m := new(models.Message)
+			err := json.NewDecoder(suite.recorder.Body).Decode(m)
+			require.NoError(t, err)
+			require.Equal(t, message.Reactions, m.Reactions)
+		}
+	})
+}
+
+func TestRemoveReaction(t *testing.T) {
+	t.Run("Malformed emoji", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id", "emoji")
+		suite.context.SetParamValues("10", "bad emoji")
+		suite.reactionService.EXPECT().Remove(*suite.user, int64(10), "bad emoji").Return(nil, services.ErrMalformedEmoji)
+
+		err := suite.api.RemoveReaction(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id", "emoji")
+		suite.context.SetParamValues("10", "%F0%9F%91%8D")
+		suite.reactionService.EXPECT().Remove(*suite.user, int64(10), "👍").Return(&models.Message{Id: 10}, nil)
+
+		err := suite.api.RemoveReaction(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, suite.recorder.Code)
+	})
+}
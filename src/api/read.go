@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) ReadState(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	states, err := a.readService.State(*user)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, states)
+}
+
+func (a *API) ConversationReadState(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	state, err := a.readService.ConversationState(*user, ctx.Param("conversation"))
+	if err != nil {
+		if err == services.ErrConversationNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, state)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadState(t *testing.T) {
+	suite := newTestSuite(t, http.MethodGet, nil, nil)
+	suite.authorize()
+	defer suite.close()
+
+	states := []models.ReadState{
+		{Conversation: "dm:1:2", LastReadId: 10, PartnerReadId: 12, Unread: 1},
+		{Conversation: "public", LastReadId: 15},
+	}
+	suite.readService.EXPECT().State(*suite.user).Return(states, nil)
+
+	err := suite.api.ReadState(suite.context)
+	require.NoError(t, err)
+
+	{
+		var s []models.ReadState
+		err := json.NewDecoder(suite.recorder.Body).Decode(&s)
+		require.NoError(t, err)
+		require.Equal(t, states, s)
+	}
+}
+
+func TestConversationReadState(t *testing.T) {
+	t.Run("Foreign conversation", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("dm:2:3")
+		suite.readService.EXPECT().ConversationState(*suite.user, "dm:2:3").Return(nil, services.ErrConversationNotFound)
+
+		err := suite.api.ConversationReadState(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		state := &models.ReadState{Conversation: "public", LastReadId: 15, Unread: 3}
+		suite.context.SetParamNames("conversation")
+		suite.context.SetParamValues("public")
+		suite.readService.EXPECT().ConversationState(*suite.user, "public").Return(state, nil)
+
+		err := suite.api.ConversationReadState(suite.context)
+		require.NoError(t, err)
+
+		{
+			s := new(models.ReadState)
+			err := json.NewDecoder(suite.recorder.Body).Decode(s)
+			require.NoError(t, err)
+			require.Equal(t, state, s)
+		}
+	})
+}
@@ -58,10 +58,12 @@ func TestRegister(t *testing.T) {
 		err := suite.api.Register(suite.context)
 		require.NoError(t, err)
 		{
-			u := new(models.User)
-			err := json.NewDecoder(suite.recorder.Body).Decode(u)
+			var u map[string]interface{}
+			err := json.NewDecoder(suite.recorder.Body).Decode(&u)
 			require.NoError(t, err)
-			require.Equal(t, u, user)
+			require.Equal(t, "user@example.com", u["email"])
+			require.NotContains(t, u, "password")
+			require.NotContains(t, u, "token")
 		}
 	})
 }
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+func (a *API) CreateScheduled(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	var req ScheduleRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	message, err := a.scheduleService.Create(*user, req.toService())
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return ctx.JSON(http.StatusCreated, message)
+}
+
+func (a *API) ListScheduled(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	messages, err := a.scheduleService.List(*user)
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, messages)
+}
+
+func (a *API) UpdateScheduled(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed scheduled message id")
+	}
+
+	var req ScheduleRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	message, err := a.scheduleService.Update(*user, id, req.toService())
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, message)
+}
+
+func (a *API) CancelScheduled(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed scheduled message id")
+	}
+
+	if err := a.scheduleService.Cancel(*user, id); err != nil {
+		return scheduleError(err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (r ScheduleRequest) toService() services.ScheduleRequest {
+	return services.ScheduleRequest{
+		To:     r.To,
+		Text:   r.Text,
+		Format: r.Format,
+		SendAt: r.SendAt,
+	}
+}
+
+func scheduleError(err error) error {
+	switch err {
+	case services.ErrScheduledNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case services.ErrTooManyScheduled:
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case services.ErrSendAtInPast, services.ErrSendAtTooFar, services.ErrReceiverNotFound,
+		services.ErrEmptyText, services.ErrMessageTooLong, services.ErrMalformedText, services.ErrMalformedFormat:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduled(t *testing.T) {
+	sendAt := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("In the past", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"text":"hi","send_at":"2030-01-01T10:00:00Z"}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.scheduleService.EXPECT().Create(*suite.user, services.ScheduleRequest{Text: "hi", SendAt: sendAt}).
+			Return(nil, services.ErrSendAtInPast)
+
+		err := suite.api.CreateScheduled(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, strings.NewReader(`{"to":"friend@example.com","text":"hi","send_at":"2030-01-01T10:00:00Z"}`), nil)
+		suite.authorize()
+		defer suite.close()
+
+		message := &models.ScheduledMessage{Id: 1, UserId: 1, ReceiverId: 2, Text: "hi", Status: models.ScheduledPending}
+		suite.scheduleService.EXPECT().Create(*suite.user, services.ScheduleRequest{To: "friend@example.com", Text: "hi", SendAt: sendAt}).
+			Return(message, nil)
+
+		err := suite.api.CreateScheduled(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, suite.recorder.Code)
+
+		{
+			m := new(models.ScheduledMessage)
+			err := json.NewDecoder(suite.recorder.Body).Decode(m)
+			require.NoError(t, err)
+			require.Equal(t, message.Id, m.Id)
+			require.Equal(t, models.ScheduledPending, m.Status)
+		}
+	})
+}
+
+func TestCancelScheduled(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.scheduleService.EXPECT().Cancel(*suite.user, int64(5)).Return(services.ErrScheduledNotFound)
+
+		err := suite.api.CancelScheduled(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("5")
+		suite.scheduleService.EXPECT().Cancel(*suite.user, int64(5)).Return(nil)
+
+		err := suite.api.CancelScheduled(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, suite.recorder.Code)
+	})
+}
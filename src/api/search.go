@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (a *API) Search(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	req := services.SearchRequest{
+		Query:        ctx.QueryParam("q"),
+		Sender:       ctx.QueryParam("from"),
+		Conversation: ctx.QueryParam("conversation"),
+		Limit:        defaultSearchLimit,
+	}
+
+	if v := ctx.QueryParam("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed limit")
+		}
+
+		if l < maxSearchLimit {
+			req.Limit = l
+		} else {
+			req.Limit = maxSearchLimit
+		}
+	}
+
+	if v := ctx.QueryParam("offset"); v != "" {
+		o, err := strconv.Atoi(v)
+		if err != nil || o < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed offset")
+		}
+
+		req.Offset = o
+	}
+
+	var err error
+	if req.Since, err = queryTime(ctx, "since"); err != nil {
+		return err
+	}
+
+	if req.Until, err = queryTime(ctx, "until"); err != nil {
+		return err
+	}
+
+	page, err := a.searchService.Search(*user, req)
+	if err != nil {
+		switch err {
+		case services.ErrMalformedQuery:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case services.ErrConversationNotFound:
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, page)
+}
+
+// queryTime parses optional RFC3339 time query parameter
+func queryTime(ctx echo.Context, param string) (time.Time, error) {
+	v := ctx.QueryParam(param)
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, echo.NewHTTPError(http.StatusBadRequest, "malformed "+param)
+	}
+
+	return t, nil
+}
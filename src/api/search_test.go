@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	t.Run("Malformed since", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("q", "hello")
+		suite.context.QueryParams().Set("since", "yesterday")
+
+		err := suite.api.Search(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Foreign conversation", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("q", "hello")
+		suite.context.QueryParams().Set("conversation", "dm:2:3")
+		suite.searchService.EXPECT().Search(*suite.user, services.SearchRequest{
+			Query:        "hello",
+			Conversation: "dm:2:3",
+			Limit:        defaultSearchLimit,
+		}).Return(nil, services.ErrConversationNotFound)
+
+		err := suite.api.Search(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		suite.context.QueryParams().Set("q", "hello")
+		suite.context.QueryParams().Set("from", "friend@example.com")
+		suite.context.QueryParams().Set("since", since.Format(time.RFC3339))
+		suite.context.QueryParams().Set("limit", "1000")
+		suite.context.QueryParams().Set("offset", "20")
+
+		page := &models.SearchPage{
+			Results: []models.SearchResult{
+				{Message: models.Message{Id: 10, Text: "hello world"}, Snippet: "<mark>hello</mark> world", Rank: 0.5},
+			},
+			NextOffset: 120,
+		}
+		suite.searchService.EXPECT().Search(*suite.user, services.SearchRequest{
+			Query:  "hello",
+			Sender: "friend@example.com",
+			Since:  since,
+			Limit:  maxSearchLimit,
+			Offset: 20,
+		}).Return(page, nil)
+
+		err := suite.api.Search(suite.context)
+		require.NoError(t, err)
+
+		{
+			p := new(models.SearchPage)
+			err := json.NewDecoder(suite.recorder.Body).Decode(p)
+			require.NoError(t, err)
+			require.Equal(t, 120, p.NextOffset)
+			require.Equal(t, "<mark>hello</mark> world", p.Results[0].Snippet)
+		}
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("q", "hello")
+		page := &models.SearchPage{
+			Results: []models.SearchResult{{Message: models.Message{Id: 10, Text: "hello", User: author()}}},
+		}
+		suite.searchService.EXPECT().Search(*suite.user, services.SearchRequest{Query: "hello", Limit: defaultSearchLimit}).Return(page, nil)
+
+		err := suite.api.Search(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
@@ -17,5 +17,5 @@ func (a *API) SignIn(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 	}
 
-	return ctx.JSON(200, user)
+	return ctx.JSON(200, SignInResponse{User: user, Token: user.Token})
 }
@@ -22,7 +22,7 @@ func TestSignIn(t *testing.T) {
 		ID:        1,
 		Email:     "user@example.com",
 		Password:  "password_hash",
-		Token:     "",
+		Token:     "session_token",
 		CreatedAt: ts,
 		UpdatedAt: ts,
 	}
@@ -58,10 +58,12 @@ func TestSignIn(t *testing.T) {
 		err := suite.api.SignIn(suite.context)
 		require.NoError(t, err)
 		{
-			u := new(models.User)
-			err := json.NewDecoder(suite.recorder.Body).Decode(u)
+			var u map[string]interface{}
+			err := json.NewDecoder(suite.recorder.Body).Decode(&u)
 			require.NoError(t, err)
-			require.Equal(t, u, user)
+			require.Equal(t, "user@example.com", u["email"])
+			require.Equal(t, "session_token", u["token"])
+			require.NotContains(t, u, "password")
 		}
 	})
 }
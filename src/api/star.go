@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+)
+
+const (
+	defaultSavedLimit = 50
+	maxSavedLimit     = 100
+)
+
+func (a *API) Star(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	star, err := a.starService.Star(*user, id)
+	if err != nil {
+		return starError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, star)
+}
+
+func (a *API) Unstar(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "malformed message id")
+	}
+
+	if err := a.starService.Unstar(*user, id); err != nil {
+		return starError(err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *API) Saved(ctx echo.Context) error {
+	user := ctx.Get("user").(*models.User)
+
+	limit := defaultSavedLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed limit")
+		}
+
+		if l < maxSavedLimit {
+			limit = l
+		} else {
+			limit = maxSavedLimit
+		}
+	}
+
+	var cursor int64
+	if v := ctx.QueryParam("cursor"); v != "" {
+		c, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || c < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "malformed cursor")
+		}
+
+		cursor = c
+	}
+
+	page, err := a.starService.Saved(*user, cursor, limit)
+	if err != nil {
+		return starError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, page)
+}
+
+func starError(err error) error {
+	switch err {
+	case services.ErrMessageNotFound, services.ErrStarNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStar(t *testing.T) {
+	t.Run("Foreign message", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.starService.EXPECT().Star(*suite.user, int64(10)).Return(nil, services.ErrMessageNotFound)
+
+		err := suite.api.Star(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		star := &models.Star{Id: 1, MessageId: 10, Conversation: "public"}
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.starService.EXPECT().Star(*suite.user, int64(10)).Return(star, nil)
+
+		err := suite.api.Star(suite.context)
+		require.NoError(t, err)
+
+		{
+			s := new(models.Star)
+			err := json.NewDecoder(suite.recorder.Body).Decode(s)
+			require.NoError(t, err)
+			require.Equal(t, star, s)
+		}
+	})
+
+	t.Run("Author", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodPost, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		star := &models.Star{Id: 1, MessageId: 10, Conversation: "public", Message: &models.Message{Id: 10, User: author()}}
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.starService.EXPECT().Star(*suite.user, int64(10)).Return(star, nil)
+
+		err := suite.api.Star(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
+
+func TestUnstar(t *testing.T) {
+	t.Run("Not starred", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.starService.EXPECT().Unstar(*suite.user, int64(10)).Return(services.ErrStarNotFound)
+
+		err := suite.api.Unstar(suite.context)
+		require.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodDelete, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.SetParamNames("id")
+		suite.context.SetParamValues("10")
+		suite.starService.EXPECT().Unstar(*suite.user, int64(10)).Return(nil)
+
+		err := suite.api.Unstar(suite.context)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, suite.recorder.Code)
+	})
+}
+
+func TestSaved(t *testing.T) {
+	t.Run("Malformed cursor", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		suite.context.QueryParams().Set("cursor", "abc")
+
+		err := suite.api.Saved(suite.context)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		page := &models.SavedPage{
+			Stars:      []models.Star{{Id: 8, MessageId: 20, Conversation: "public"}},
+			NextCursor: 8,
+		}
+		suite.context.QueryParams().Set("cursor", "9")
+		suite.context.QueryParams().Set("limit", "500")
+		suite.starService.EXPECT().Saved(*suite.user, int64(9), maxSavedLimit).Return(page, nil)
+
+		err := suite.api.Saved(suite.context)
+		require.NoError(t, err)
+
+		{
+			p := new(models.SavedPage)
+			err := json.NewDecoder(suite.recorder.Body).Decode(p)
+			require.NoError(t, err)
+			require.Equal(t, page, p)
+		}
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		suite := newTestSuite(t, http.MethodGet, nil, nil)
+		suite.authorize()
+		defer suite.close()
+
+		page := &models.SavedPage{
+			Stars: []models.Star{{Id: 8, MessageId: 20, Conversation: "public", Message: &models.Message{Id: 20, User: author()}}},
+		}
+		suite.starService.EXPECT().Saved(*suite.user, int64(0), defaultSavedLimit).Return(page, nil)
+
+		err := suite.api.Saved(suite.context)
+		require.NoError(t, err)
+		require.Contains(t, suite.recorder.Body.String(), "author@example.com")
+		requireNoSecrets(t, suite.recorder.Body.String())
+	})
+}
@@ -1,6 +1,57 @@
 package api
 
+import (
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+)
+
 type UserRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type PasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// SignInResponse is the only response carrying session token, it is sent to its owner
+type SignInResponse struct {
+	*models.User
+	Token string `json:"token"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+type AttachmentURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ScheduleRequest struct {
+	To     string    `json:"to"`
+	Text   string    `json:"text"`
+	Format string    `json:"format"`
+	SendAt time.Time `json:"send_at"`
+}
+
+type PollRequest struct {
+	To        string     `json:"to"`
+	Text      string     `json:"text"`
+	Format    string     `json:"format"`
+	Options   []string   `json:"options"`
+	Multiple  bool       `json:"multiple"`
+	Anonymous bool       `json:"anonymous"`
+	ClosesAt  *time.Time `json:"closes_at"`
+}
+
+type VoteRequest struct {
+	Options []int64 `json:"options"`
+}
+
+type DraftRequest struct {
+	Text string `json:"text"`
+}
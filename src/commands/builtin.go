@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playneta/go-sessions/src/services"
+)
+
+// shrug is escaped so markdown renders it literally
+const shrug = `¯\\\_(ツ)\_/¯`
+
+type (
+	meCommand    struct{}
+	shrugCommand struct{}
+	dmCommand    struct{}
+
+	whoCommand struct {
+		presence Presence
+	}
+
+	nickCommand struct {
+		accounts services.Account
+	}
+)
+
+// NewMe registers /me which sends action message
+func NewMe() Out {
+	return Out{Command: meCommand{}}
+}
+
+func (meCommand) Name() string  { return "me" }
+func (meCommand) Usage() string { return "/me <text> - describe what you are doing" }
+
+func (meCommand) Run(call Call) (*Result, error) {
+	if call.Args == "" {
+		return nil, ErrUsage
+	}
+
+	return &Result{Message: &services.MessageRequest{To: call.To, Text: call.Args, Action: true}}, nil
+}
+
+// NewShrug registers /shrug which appends shrug to message
+func NewShrug() Out {
+	return Out{Command: shrugCommand{}}
+}
+
+func (shrugCommand) Name() string  { return "shrug" }
+func (shrugCommand) Usage() string { return "/shrug [text] - send text with a shrug" }
+
+func (shrugCommand) Run(call Call) (*Result, error) {
+	text := shrug
+	if call.Args != "" {
+		text = call.Args + " " + shrug
+	}
+
+	return &Result{Message: &services.MessageRequest{To: call.To, Text: text}}, nil
+}
+
+// NewDM registers /dm which sends private message to user
+func NewDM() Out {
+	return Out{Command: dmCommand{}}
+}
+
+func (dmCommand) Name() string  { return "dm" }
+func (dmCommand) Usage() string { return "/dm <email> <text> - send private message" }
+
+func (dmCommand) Run(call Call) (*Result, error) {
+	fields := strings.SplitN(call.Args, " ", 2)
+	if len(fields) < 2 || strings.TrimSpace(fields[1]) == "" {
+		return nil, ErrUsage
+	}
+
+	return &Result{Message: &services.MessageRequest{To: fields[0], Text: strings.TrimSpace(fields[1])}}, nil
+}
+
+// NewWho registers /who which lists connected users
+func NewWho(presence Presence) Out {
+	return Out{Command: whoCommand{presence: presence}}
+}
+
+func (whoCommand) Name() string  { return "who" }
+func (whoCommand) Usage() string { return "/who - list online users" }
+
+func (c whoCommand) Run(call Call) (*Result, error) {
+	users := c.presence.Online()
+
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.Email
+		if user.Nickname != "" {
+			names[i] = fmt.Sprintf("%s (%s)", user.Nickname, user.Email)
+		}
+	}
+
+	return &Result{Reply: fmt.Sprintf("online (%d): %s", len(names), strings.Join(names, ", "))}, nil
+}
+
+// NewNick registers /nick which changes nickname of user
+func NewNick(accounts services.Account) Out {
+	return Out{Command: nickCommand{accounts: accounts}}
+}
+
+func (nickCommand) Name() string  { return "nick" }
+func (nickCommand) Usage() string { return "/nick [nickname] - change your nickname, empty resets it" }
+
+func (c nickCommand) Run(call Call) (*Result, error) {
+	user, err := c.accounts.Rename(call.User, call.Args)
+	switch err {
+	case nil:
+	case services.ErrMalformedNickname, services.ErrNicknameTaken:
+		return &Result{Reply: err.Error()}, nil
+	default:
+		return nil, err
+	}
+
+	if user.Nickname == "" {
+		return &Result{Reply: "your nickname is reset", User: user}, nil
+	}
+
+	return &Result{Reply: fmt.Sprintf("you are now known as %s", user.Nickname), User: user}, nil
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"go.uber.org/fx"
+)
+
+type (
+	// Command handles slash command typed by user instead of a message
+	Command interface {
+		// Name is the word after slash command is invoked with
+		Name() string
+		// Usage is a short description shown by /help
+		Usage() string
+		Run(call Call) (*Result, error)
+	}
+
+	// Call is a single invocation of command, To is the receiver of
+	// conversation command was typed in, empty for public conversation
+	Call struct {
+		User models.User
+		To   string
+		Args string
+	}
+
+	// Result of command, Reply is shown only to the caller as a system event
+	// while Message is sent on behalf of the caller as a regular message,
+	// User is set when command changed profile of the caller
+	Result struct {
+		Reply   string
+		Message *services.MessageRequest
+		User    *models.User
+	}
+
+	// Presence lists connected users, it is implemented by websocket hub
+	Presence interface {
+		Online() []models.User
+	}
+
+	// Out registers command constructor in commands group
+	Out struct {
+		fx.Out
+
+		Command Command `group:"commands"`
+	}
+
+	RegistryOptions struct {
+		fx.In
+
+		Commands []Command `group:"commands"`
+	}
+
+	// Registry routes slash commands to commands registered in fx group
+	Registry struct {
+		commands map[string]Command
+	}
+)
+
+var (
+	// ErrUsage is returned by command when its arguments are wrong,
+	// registry replies with command usage then
+	ErrUsage = errors.New("wrong command arguments")
+)
+
+func NewRegistry(opts RegistryOptions) *Registry {
+	registry := &Registry{
+		commands: make(map[string]Command),
+	}
+
+	for _, command := range opts.Commands {
+		registry.commands[command.Name()] = command
+	}
+
+	return registry
+}
+
+// Parse splits text into command name and arguments, text is a command
+// when it starts with a single slash, double slash escapes it
+func Parse(text string) (name, args string, ok bool) {
+	if !strings.HasPrefix(text, "/") || strings.HasPrefix(text, "//") {
+		return "", "", false
+	}
+
+	text = text[1:]
+	if i := strings.IndexAny(text, " \t\n"); i >= 0 {
+		return strings.ToLower(text[:i]), strings.TrimSpace(text[i+1:]), true
+	}
+
+	return strings.ToLower(text), "", true
+}
+
+// Run executes command by name, unknown commands and wrong usage are
+// answered with a reply instead of an error, /help is handled by registry itself
+func (r *Registry) Run(name string, call Call) (*Result, error) {
+	if name == "help" {
+		return &Result{Reply: r.help()}, nil
+	}
+
+	command, ok := r.commands[name]
+	if !ok {
+		return &Result{Reply: fmt.Sprintf("unknown command /%s, type /help to list commands or start message with // to send it as is", name)}, nil
+	}
+
+	result, err := command.Run(call)
+	if err == ErrUsage {
+		return &Result{Reply: fmt.Sprintf("usage: %s", command.Usage())}, nil
+	}
+
+	return result, err
+}
+
+func (r *Registry) help() string {
+	lines := []string{"/help - list commands"}
+	for _, command := range r.commands {
+		lines = append(lines, command.Usage())
+	}
+	sort.Strings(lines[1:])
+
+	return strings.Join(lines, "\n")
+}
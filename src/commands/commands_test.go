@@ -0,0 +1,133 @@
+package commands_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/commands"
+	mock_commands "github.com/playneta/go-sessions/src/commands/mocks"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for text, expected := range map[string][]string{
+		"/me waves":             {"me", "waves"},
+		"/DM a@b.c  hi there ":  {"dm", "a@b.c  hi there"},
+		"/who":                  {"who", ""},
+		"/shrug\nmultiline":     {"shrug", "multiline"},
+		"hello /me":             nil,
+		"//me is not a command": nil,
+		"":                      nil,
+	} {
+		name, args, ok := commands.Parse(text)
+		if expected == nil {
+			require.False(t, ok, text)
+			continue
+		}
+
+		require.True(t, ok, text)
+		require.Equal(t, expected[0], name, text)
+		require.Equal(t, expected[1], args, text)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	presence := mock_commands.NewMockPresence(ctrl)
+	accounts := mock_services.NewMockAccount(ctrl)
+
+	registry := commands.NewRegistry(commands.RegistryOptions{
+		Commands: []commands.Command{
+			commands.NewMe().Command,
+			commands.NewShrug().Command,
+			commands.NewDM().Command,
+			commands.NewWho(presence).Command,
+			commands.NewNick(accounts).Command,
+		},
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	t.Run("Help", func(t *testing.T) {
+		result, err := registry.Run("help", commands.Call{User: user})
+		require.NoError(t, err)
+		require.Contains(t, result.Reply, "/help")
+		require.Contains(t, result.Reply, "/dm <email> <text>")
+		require.Nil(t, result.Message)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		result, err := registry.Run("path/to/file", commands.Call{User: user})
+		require.NoError(t, err)
+		require.Contains(t, result.Reply, "unknown command /path/to/file")
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		result, err := registry.Run("dm", commands.Call{User: user, Args: "friend@example.com"})
+		require.NoError(t, err)
+		require.Equal(t, "usage: /dm <email> <text> - send private message", result.Reply)
+		require.Nil(t, result.Message)
+	})
+
+	t.Run("Me", func(t *testing.T) {
+		result, err := registry.Run("me", commands.Call{User: user, To: "friend@example.com", Args: "waves"})
+		require.NoError(t, err)
+		require.Equal(t, &services.MessageRequest{To: "friend@example.com", Text: "waves", Action: true}, result.Message)
+	})
+
+	t.Run("Shrug", func(t *testing.T) {
+		result, err := registry.Run("shrug", commands.Call{User: user, Args: "no idea"})
+		require.NoError(t, err)
+		require.Equal(t, `no idea ¯\\\_(ツ)\_/¯`, result.Message.Text)
+	})
+
+	t.Run("DM", func(t *testing.T) {
+		result, err := registry.Run("dm", commands.Call{User: user, Args: "friend@example.com hello there"})
+		require.NoError(t, err)
+		require.Equal(t, &services.MessageRequest{To: "friend@example.com", Text: "hello there"}, result.Message)
+	})
+
+	t.Run("Who", func(t *testing.T) {
+		presence.EXPECT().Online().Return([]models.User{user, {ID: 2, Email: "neo@example.com", Nickname: "neo"}})
+
+		result, err := registry.Run("who", commands.Call{User: user})
+		require.NoError(t, err)
+		require.Equal(t, "online (2): user@example.com, neo (neo@example.com)", result.Reply)
+	})
+
+	t.Run("Nick", func(t *testing.T) {
+		t.Run("Taken", func(t *testing.T) {
+			accounts.EXPECT().Rename(user, "neo").Return(nil, services.ErrNicknameTaken)
+
+			result, err := registry.Run("nick", commands.Call{User: user, Args: "neo"})
+			require.NoError(t, err)
+			require.Equal(t, services.ErrNicknameTaken.Error(), result.Reply)
+			require.Nil(t, result.User)
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			accounts.EXPECT().Rename(user, "neo").Return(nil, errors.New("database error"))
+
+			result, err := registry.Run("nick", commands.Call{User: user, Args: "neo"})
+			require.Nil(t, result)
+			require.Error(t, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			renamed := user
+			renamed.Nickname = "trinity"
+			accounts.EXPECT().Rename(user, "trinity").Return(&renamed, nil)
+
+			result, err := registry.Run("nick", commands.Call{User: user, Args: "trinity"})
+			require.NoError(t, err)
+			require.Equal(t, "you are now known as trinity", result.Reply)
+			require.Equal(t, &renamed, result.User)
+		})
+	})
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/commands/commands.go
+
+// Package mock_commands is a generated GoMock package.
+package mock_commands
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	commands "github.com/playneta/go-sessions/src/commands"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockCommand is a mock of Command interface
+type MockCommand struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommandMockRecorder
+}
+
+// MockCommandMockRecorder is the mock recorder for MockCommand
+type MockCommandMockRecorder struct {
+	mock *MockCommand
+}
+
+// NewMockCommand creates a new mock instance
+func NewMockCommand(ctrl *gomock.Controller) *MockCommand {
+	mock := &MockCommand{ctrl: ctrl}
+	mock.recorder = &MockCommandMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommand) EXPECT() *MockCommandMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method
+func (m *MockCommand) Name() string {
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name
+func (mr *MockCommandMockRecorder) Name() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockCommand)(nil).Name))
+}
+
+// Usage mocks base method
+func (m *MockCommand) Usage() string {
+	ret := m.ctrl.Call(m, "Usage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Usage indicates an expected call of Usage
+func (mr *MockCommandMockRecorder) Usage() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockCommand)(nil).Usage))
+}
+
+// Run mocks base method
+func (m *MockCommand) Run(call commands.Call) (*commands.Result, error) {
+	ret := m.ctrl.Call(m, "Run", call)
+	ret0, _ := ret[0].(*commands.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run
+func (mr *MockCommandMockRecorder) Run(call interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockCommand)(nil).Run), call)
+}
+
+// MockPresence is a mock of Presence interface
+type MockPresence struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceMockRecorder
+}
+
+// MockPresenceMockRecorder is the mock recorder for MockPresence
+type MockPresenceMockRecorder struct {
+	mock *MockPresence
+}
+
+// NewMockPresence creates a new mock instance
+func NewMockPresence(ctrl *gomock.Controller) *MockPresence {
+	mock := &MockPresence{ctrl: ctrl}
+	mock.recorder = &MockPresenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPresence) EXPECT() *MockPresenceMockRecorder {
+	return m.recorder
+}
+
+// Online mocks base method
+func (m *MockPresence) Online() []models.User {
+	ret := m.ctrl.Call(m, "Online")
+	ret0, _ := ret[0].([]models.User)
+	return ret0
+}
+
+// Online indicates an expected call of Online
+func (mr *MockPresenceMockRecorder) Online() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Online", reflect.TypeOf((*MockPresence)(nil).Online))
+}
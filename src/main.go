@@ -1,11 +1,16 @@
 package src
 
 import (
+	"archive/zip"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/playneta/go-sessions/src/api"
+	"github.com/playneta/go-sessions/src/commands"
 	"github.com/playneta/go-sessions/src/providers"
 	"github.com/playneta/go-sessions/src/repositories"
 	"github.com/playneta/go-sessions/src/services"
@@ -18,26 +23,190 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// application provides every service of the running application
+var application = fx.Provide(
+	providers.NewConfig,
+	providers.NewLogger,
+	providers.NewDB,
+	providers.NewBcryptHasher,
+	providers.NewBlobStore,
+	providers.NewUnfurler,
+	providers.NewMarkdownRenderer,
+	services.NewAccount,
+	services.NewReaction,
+	services.NewMention,
+	services.NewRead,
+	services.NewPin,
+	services.NewAttachment,
+	services.NewPreview,
+	services.NewSearch,
+	services.NewSchedule,
+	services.NewPoll,
+	services.NewStar,
+	services.NewDelivery,
+	services.NewExport,
+	services.NewArchive,
+	services.NewDraft,
+	repositories.NewUser,
+	repositories.NewMessage,
+	repositories.NewReaction,
+	repositories.NewMention,
+	repositories.NewReadMarker,
+	repositories.NewPin,
+	repositories.NewAttachment,
+	repositories.NewPreview,
+	repositories.NewScheduledMessage,
+	repositories.NewPoll,
+	repositories.NewStar,
+	repositories.NewArchive,
+	repositories.NewDraft,
+	ws.NewHub,
+	ws.NewNotifier,
+	ws.NewPresence,
+	commands.NewRegistry,
+	commands.NewMe,
+	commands.NewShrug,
+	commands.NewDM,
+	commands.NewWho,
+	commands.NewNick,
+)
+
 // Run starting main application running fx with providers and ivoke api.New
 func Run() {
+	app := fx.New(
+		application,
+
+		fx.Invoke(
+			api.New,
+			ws.New,
+			services.NewExpiry,
+			services.NewRetention,
+		),
+	)
+
+	app.Run()
+}
+
+// Prune deletes messages outside of retention policies once, dry run only counts them
+func Prune(dryRun bool) error {
+	var pruneErr error
 	app := fx.New(
 		fx.Provide(
 			providers.NewConfig,
 			providers.NewLogger,
 			providers.NewDB,
-			providers.NewBcryptHasher,
-			services.NewAccount,
+			providers.NewBlobStore,
 			repositories.NewUser,
 			repositories.NewMessage,
+			repositories.NewAttachment,
+			repositories.NewArchive,
+			services.NewArchive,
+			services.NewRetention,
 		),
+		fx.Invoke(func(retention services.Retention) {
+			_, pruneErr = retention.Prune(dryRun)
+		}),
+	)
 
-		fx.Invoke(
-			api.New,
-			ws.New,
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	return pruneErr
+}
+
+// Archive moves messages older than days, or than archive.days when it is zero, to blob store once
+func Archive(days int) error {
+	var archiveErr error
+	app := fx.New(
+		fx.Provide(
+			providers.NewConfig,
+			providers.NewLogger,
+			providers.NewDB,
+			providers.NewBlobStore,
+			repositories.NewUser,
+			repositories.NewMessage,
+			repositories.NewArchive,
+			services.NewArchive,
 		),
+		fx.Invoke(func(archive services.Archive, config *viper.Viper) {
+			if days == 0 {
+				days = config.GetInt("archive.days")
+			}
+
+			if days <= 0 {
+				archiveErr = errors.New("number of days to keep messages for is not set")
+				return
+			}
+
+			_, archiveErr = archive.Archive(time.Now().AddDate(0, 0, -days))
+		}),
 	)
 
-	app.Run()
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	return archiveErr
+}
+
+// Export writes transcript of conversation, of every one when it is empty, to w
+func Export(req services.ExportRequest, w io.Writer) error {
+	var exportErr error
+	app := fx.New(
+		fx.Provide(
+			providers.NewConfig,
+			providers.NewLogger,
+			providers.NewDB,
+			providers.NewMarkdownRenderer,
+			providers.NewBlobStore,
+			repositories.NewUser,
+			repositories.NewMessage,
+			repositories.NewArchive,
+			services.NewArchive,
+			services.NewExport,
+		),
+		fx.Invoke(func(export services.Export) {
+			exportErr = export.Transcript(req, w)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	return exportErr
+}
+
+// ImportSlack imports users and messages of listed channels, or all of them, from Slack export zip
+func ImportSlack(file string, channels []string) error {
+	var importErr error
+	app := fx.New(
+		fx.Provide(
+			providers.NewConfig,
+			providers.NewLogger,
+			providers.NewDB,
+			repositories.NewUser,
+			repositories.NewMessage,
+			services.NewSlackImport,
+		),
+		fx.Invoke(func(slack services.SlackImport) {
+			archive, err := zip.OpenReader(file)
+			if err != nil {
+				importErr = err
+				return
+			}
+			defer archive.Close()
+
+			_, importErr = slack.Import(&archive.Reader, channels)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	return importErr
 }
 
 func Migrate(dir string) {
@@ -80,3 +249,26 @@ func migrate(dir string, v *viper.Viper) error {
 
 	return nil
 }
+
+// IssuePasswordReset prints token the user with email sets new password with over POST /password-reset
+func IssuePasswordReset(email string) error {
+	var issueErr error
+	app := fx.New(
+		application,
+		fx.Invoke(func(account services.Account) {
+			token, err := account.IssuePasswordReset(email)
+			if err != nil {
+				issueErr = err
+				return
+			}
+
+			fmt.Println(token)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	return issueErr
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ArchiveSegment is compressed ndjson file of messages of one conversation created
+// within a month which were moved out of messages table into blob store
+type ArchiveSegment struct {
+	Id           int64     `json:"id"`
+	Conversation string    `json:"conversation"`
+	Month        time.Time `json:"month"`
+	Key          string    `json:"key"`
+	Count        int       `json:"count"`
+	MinId        int64     `json:"min_id"`
+	MaxId        int64     `json:"max_id"`
+	FirstAt      time.Time `json:"first_at"`
+	LastAt       time.Time `json:"last_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MessageKey is position of message in history ordered by creation time and id,
+// it locates messages which are not in messages table anymore
+type MessageKey struct {
+	CreatedAt time.Time
+	Id        int64
+}
+
+// Less tells whether position is older than the other one
+func (k MessageKey) Less(other MessageKey) bool {
+	if k.CreatedAt.Equal(other.CreatedAt) {
+		return k.Id < other.Id
+	}
+
+	return k.CreatedAt.Before(other.CreatedAt)
+}
+
+// Key returns position of message in history
+func (m Message) Key() MessageKey {
+	return MessageKey{CreatedAt: m.CreatedAt, Id: m.Id}
+}
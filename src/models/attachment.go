@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Attachment is a file uploaded by user, it belongs to nobody
+// until it is referenced by a sent message
+type Attachment struct {
+	Id          int64     `json:"id"`
+	UserId      int64     `json:"user_id"`
+	MessageId   int64     `json:"message_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PublicConversation is key of the public chat everyone can read
+const PublicConversation = "public"
+
+var ErrMalformedConversation = errors.New("malformed conversation")
+
+// DirectConversation returns key of private conversation between two users,
+// it is the same for both participants
+func DirectConversation(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+
+	return fmt.Sprintf("dm:%d:%d", a, b)
+}
+
+// ParseConversation returns participants of private conversation,
+// both are zero for public one
+func ParseConversation(key string) (int64, int64, error) {
+	if key == PublicConversation {
+		return 0, 0, nil
+	}
+
+	parts := strings.Split(key, ":")
+	if len(parts) != 3 || parts[0] != "dm" {
+		return 0, 0, ErrMalformedConversation
+	}
+
+	a, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || a <= 0 {
+		return 0, 0, ErrMalformedConversation
+	}
+
+	b, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || b < a {
+		return 0, 0, ErrMalformedConversation
+	}
+
+	// Ids written as "+2" or "002" parse too, only canonical keys are stored
+	if key != DirectConversation(a, b) {
+		return 0, 0, ErrMalformedConversation
+	}
+
+	return a, b, nil
+}
+
+// ConversationVisibleTo reports whether user participates in conversation
+func ConversationVisibleTo(key string, user User) bool {
+	a, b, err := ParseConversation(key)
+	if err != nil {
+		return false
+	}
+
+	return key == PublicConversation || a == user.ID || b == user.ID
+}
+
+// ConversationPartner returns the other participant of private conversation
+func ConversationPartner(key string, user User) int64 {
+	a, b, _ := ParseConversation(key)
+	if a == user.ID {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Draft is a message user started writing but has not sent yet, it is kept
+// per conversation so every device of user shows the same one
+type Draft struct {
+	tableName struct{} `sql:"drafts"`
+
+	UserId       int64     `json:"-" sql:",pk"`
+	Conversation string    `json:"conversation" sql:",pk"`
+	Text         string    `json:"text"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ExportQuery selects messages of conversation, of every conversation when it is empty,
+// created within [Since, Until), zero bounds are open, Batch is number of rows fetched at once
+type ExportQuery struct {
+	Conversation string
+	Since        time.Time
+	Until        time.Time
+	Batch        int
+}
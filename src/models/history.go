@@ -0,0 +1,26 @@
+package models
+
+// HistoryQuery selects page of conversation messages by keyset on (created_at, id),
+// Before and After are ids of messages bounding the page, zero means unbounded,
+// keys bound it instead of ids when cursor message was archived
+type HistoryQuery struct {
+	Conversation string
+	Before       int64
+	After        int64
+	BeforeKey    *MessageKey
+	AfterKey     *MessageKey
+	Limit        int
+}
+
+// Ascending tells whether page is taken from the oldest end of range
+func (q HistoryQuery) Ascending() bool {
+	return (q.After > 0 || q.AfterKey != nil) && q.Before == 0 && q.BeforeKey == nil
+}
+
+// HistoryPage is a page of conversation messages, oldest first,
+// cursors are omitted when there is nothing more in their direction
+type HistoryPage struct {
+	Messages []Message `json:"messages"`
+	Before   int64     `json:"before,omitempty"`
+	After    int64     `json:"after,omitempty"`
+}
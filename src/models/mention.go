@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	MentionUser = "user"
+	MentionHere = "here"
+	MentionAll  = "all"
+)
+
+// Mention is a reference to a user, or to everyone for @here and @all, inside message text
+type Mention struct {
+	tableName struct{} `sql:"message_mentions"`
+
+	Id        int64     `json:"id"`
+	MessageId int64     `json:"message_id"`
+	Message   *Message  `json:"message,omitempty"`
+	UserId    int64     `json:"user_id"`
+	User      *User     `json:"user,omitempty"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
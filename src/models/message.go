@@ -2,13 +2,95 @@ package models
 
 import "time"
 
+// Message types, poll messages use text as the question,
+// action messages are sent with /me and describe what author does
+const (
+	MessageText   = "text"
+	MessagePoll   = "poll"
+	MessageAction = "action"
+)
+
+// Message formats, markdown text is rendered into html, plain text is only escaped
+const (
+	FormatMarkdown = "markdown"
+	FormatPlain    = "plain"
+)
+
+// Delivery statuses of private messages shown to their authors
+const (
+	StatusSent      = "sent"
+	StatusDelivered = "delivered"
+	StatusRead      = "read"
+)
+
+// Reference kinds, forward repeats original message in another
+// conversation while quote replies to it in the same one
+const (
+	RefForward = "forward"
+	RefQuote   = "quote"
+)
+
 type Message struct {
-	Id         int64     `json:"id"`
-	UserId     int64     `json:"user_id"`
-	User       *User     `json:"user"`
-	ReceiverId int64     `json:"receiver_id"`
-	Receiver   *User     `json:"receiver"`
-	Text       string    `json:"text"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	// search vector is maintained by database and never read
+	tableName struct{} `sql:"messages" pg:",discard_unknown_columns"`
+
+	Id          int64             `json:"id"`
+	UserId      int64             `json:"user_id"`
+	User        *User             `json:"user"`
+	ReceiverId  int64             `json:"receiver_id"`
+	Receiver    *User             `json:"receiver"`
+	ClientMsgId string            `json:"client_msg_id,omitempty"`
+	Type        string            `json:"type"`
+	Text        string            `json:"text" sql:",notnull"`
+	Format      string            `json:"format"`
+	HTML        string            `json:"html" sql:"-"`
+	Attachments []Attachment      `json:"attachments" sql:"-"`
+	Mentions    []Mention         `json:"mentions" sql:"-"`
+	Reactions   []ReactionSummary `json:"reactions" sql:"-"`
+	Previews    []Preview         `json:"previews" sql:"-"`
+	Poll        *Poll             `json:"poll,omitempty" sql:"-"`
+	Starred     bool              `json:"starred" sql:"-"`
+	RefId       int64             `json:"ref_id,omitempty"`
+	Ref         *MessageRef       `json:"ref,omitempty" pg:"fk:-"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+	Status      string            `json:"status,omitempty" sql:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// MessageRef is a snapshot of forwarded or quoted message taken when
+// reference was made, it outlives edits and deletion of the original
+type MessageRef struct {
+	Kind      string    `json:"kind"`
+	From      string    `json:"from"`
+	Type      string    `json:"type"`
+	Text      string    `json:"text"`
+	Format    string    `json:"format"`
+	HTML      string    `json:"html,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VisibleTo reports whether user is allowed to see the message:
+// public messages are visible to everyone, private only to its participants
+func (m Message) VisibleTo(user User) bool {
+	if m.ReceiverId == 0 {
+		return true
+	}
+
+	return m.UserId == user.ID || m.ReceiverId == user.ID
+}
+
+// Expired reports whether self-destructing message is gone
+func (m Message) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// Conversation returns key of conversation message belongs to
+func (m Message) Conversation() string {
+	if m.ReceiverId == 0 {
+		return PublicConversation
+	}
+
+	return DirectConversation(m.UserId, m.ReceiverId)
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Pin struct {
+	Id           int64     `json:"id"`
+	MessageId    int64     `json:"message_id"`
+	Message      *Message  `json:"message,omitempty"`
+	Conversation string    `json:"conversation"`
+	UserId       int64     `json:"user_id"`
+	User         *User     `json:"user,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
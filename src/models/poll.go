@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Poll is payload of poll message, message text is the question
+type Poll struct {
+	tableName struct{} `sql:"polls"`
+
+	MessageId int64        `json:"message_id" sql:",pk"`
+	Multiple  bool         `json:"multiple"`
+	Anonymous bool         `json:"anonymous"`
+	ClosesAt  *time.Time   `json:"closes_at,omitempty"`
+	Options   []PollOption `json:"options" sql:"-"`
+	Voters    int          `json:"voters" sql:"-"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// PollOption is a choice of poll with its tally, voters are
+// listed only for polls which are not anonymous
+type PollOption struct {
+	tableName struct{} `sql:"poll_options"`
+
+	Id        int64    `json:"id"`
+	MessageId int64    `json:"-"`
+	Position  int      `json:"position" sql:",notnull"`
+	Text      string   `json:"text"`
+	Votes     int      `json:"votes" sql:"-"`
+	Me        bool     `json:"me" sql:"-"`
+	Voters    []string `json:"voters,omitempty" sql:"-"`
+}
+
+// PollVote is choice of user in poll
+type PollVote struct {
+	tableName struct{} `sql:"poll_votes"`
+
+	MessageId int64     `json:"message_id" sql:",pk"`
+	OptionId  int64     `json:"option_id" sql:",pk"`
+	UserId    int64     `json:"user_id" sql:",pk"`
+	User      *User     `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Closed reports whether poll no longer accepts votes
+func (p Poll) Closed(now time.Time) bool {
+	return p.ClosesAt != nil && !p.ClosesAt.After(now)
+}
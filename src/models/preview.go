@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Preview is metadata of a page linked in message text, previews are
+// cached by url and shared between all messages linking the same page
+type Preview struct {
+	tableName struct{} `sql:"link_previews"`
+
+	URL         string    `json:"url" sql:",pk"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"`
+	SiteName    string    `json:"site_name"`
+	Failed      bool      `json:"-"`
+	FetchedAt   time.Time `json:"-"`
+}
+
+// MessagePreview links message to preview of url found in its text
+type MessagePreview struct {
+	tableName struct{} `sql:"message_previews"`
+
+	MessageId int64  `sql:",pk"`
+	URL       string `sql:",pk"`
+	Position  int
+}
+
+// Empty reports whether page had nothing worth showing
+func (p Preview) Empty() bool {
+	return p.Title == "" && p.Description == ""
+}
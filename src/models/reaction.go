@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Reaction struct {
+	Id        int64     `json:"id"`
+	MessageId int64     `json:"message_id"`
+	UserId    int64     `json:"user_id"`
+	User      *User     `json:"user"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionSummary is aggregated reactions with the same emoji on a message
+// as seen by a particular user
+type ReactionSummary struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+	Me    bool   `json:"me"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ReadMarker is the last message user has read in conversation
+type ReadMarker struct {
+	tableName struct{} `sql:"read_markers"`
+
+	UserId       int64     `json:"user_id" sql:",pk"`
+	Conversation string    `json:"conversation" sql:",pk"`
+	MessageId    int64     `json:"message_id"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ReadState describes what is read in conversation by user and,
+// for private conversations, by the other participant
+type ReadState struct {
+	Conversation  string `json:"conversation"`
+	LastReadId    int64  `json:"last_read_id"`
+	PartnerReadId int64  `json:"partner_read_id"`
+	Unread        int    `json:"unread"`
+}
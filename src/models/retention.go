@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Retention scopes, public policy covers the shared conversation while
+// private one is applied to every direct conversation separately
+const (
+	RetentionPublic  = "public"
+	RetentionPrivate = "private"
+)
+
+// RetentionPolicy limits how long messages of a scope are kept, messages older than
+// MaxAge or beyond MaxCount newest ones are pruned, zero value disables a limit
+type RetentionPolicy struct {
+	Scope    string
+	MaxAge   time.Duration
+	MaxCount int
+}
+
+// Enabled tells whether policy limits anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxCount > 0
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	ScheduledPending = "pending"
+	ScheduledSent    = "sent"
+	ScheduledFailed  = "failed"
+)
+
+// ScheduledMessage is a message user wants to be sent at given time,
+// empty receiver means public message
+type ScheduledMessage struct {
+	Id           int64     `json:"id"`
+	UserId       int64     `json:"user_id"`
+	User         *User     `json:"-"`
+	ReceiverId   int64     `json:"receiver_id"`
+	Receiver     *User     `json:"receiver"`
+	Text         string    `json:"text" sql:",notnull"`
+	Format       string    `json:"format"`
+	SendAt       time.Time `json:"send_at"`
+	Status       string    `json:"status"`
+	MessageId    int64     `json:"message_id"`
+	Error        string    `json:"error" sql:",notnull"`
+	ClaimedUntil time.Time `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// SearchQuery describes full-text search over messages visible to user,
+// zero values of filters mean they are not applied
+type SearchQuery struct {
+	Query        string
+	SenderId     int64
+	Conversation string
+	Since        time.Time
+	Until        time.Time
+	Limit        int
+	Offset       int
+}
+
+// SearchResult is message matched by search, snippet is html
+// with matched words wrapped into <mark> tags
+type SearchResult struct {
+	Message Message `json:"message"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// SearchPage is a page of search results, next offset is omitted on the last page
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextOffset int            `json:"next_offset,omitempty"`
+}
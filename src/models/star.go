@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Star is a message user saved for later, it is visible only to that user
+type Star struct {
+	Id           int64     `json:"id"`
+	UserId       int64     `json:"-"`
+	MessageId    int64     `json:"message_id"`
+	Message      *Message  `json:"message,omitempty"`
+	Conversation string    `json:"conversation" sql:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SavedPage is a page of starred messages, newest first,
+// next cursor is omitted on the last page
+type SavedPage struct {
+	Stars      []Star `json:"stars"`
+	NextCursor int64  `json:"next_cursor,omitempty"`
+}
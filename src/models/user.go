@@ -2,11 +2,39 @@ package models
 
 import "time"
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// User is serialized along with messages of other people,
+// so password hash and session token are never part of it
 type User struct {
-	ID        int64     `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Token     string    `json:"token"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int64     `json:"id"`
+	Email         string    `json:"email"`
+	Nickname      string    `json:"nickname"`
+	Password      string    `json:"-"`
+	Token         string    `json:"-"`
+	Role          string    `json:"role"`
+	PasswordReset bool      `json:"password_reset"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	ResetToken     string     `json:"-"`
+	ResetExpiresAt *time.Time `json:"-"`
+}
+
+// IsModerator reports whether user is allowed to moderate conversations
+func (u User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+// Name is how user is shown to others, nickname when it is set
+func (u User) Name() string {
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+
+	return u.Email
 }
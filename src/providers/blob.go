@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// BlobStore is interface for storing binary objects like attachments by key
+	BlobStore interface {
+		Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+	}
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrMalformedKey = errors.New("malformed blob key")
+)
+
+// NewBlobStore creates blob store configured by blob.driver, local disk is used by default
+func NewBlobStore(config *viper.Viper) (BlobStore, error) {
+	config.SetDefault("blob.driver", "local")
+	config.SetDefault("blob.local.dir", "./data")
+	config.SetDefault("blob.s3.region", "us-east-1")
+
+	switch driver := config.GetString("blob.driver"); driver {
+	case "local":
+		return NewLocalBlobStore(config.GetString("blob.local.dir"))
+	case "s3":
+		return NewS3BlobStore(S3Options{
+			Endpoint:  config.GetString("blob.s3.endpoint"),
+			Bucket:    config.GetString("blob.s3.bucket"),
+			Region:    config.GetString("blob.s3.region"),
+			AccessKey: config.GetString("blob.s3.access_key"),
+			SecretKey: config.GetString("blob.s3.secret_key"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob store driver: %s", driver)
+	}
+}
@@ -0,0 +1,268 @@
+package providers
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Renderer turns message text into html safe to insert into page as is
+	Renderer interface {
+		Render(text string) string
+	}
+
+	// MarkdownRenderer renders subset of markdown: bold, italics, inline code,
+	// fenced code blocks, links and lists. Renderer never passes through
+	// html from text, every tag in output is produced by renderer itself
+	MarkdownRenderer struct{}
+)
+
+// maxLinkHref is the longest url of link, longer ones are left as text
+const maxLinkHref = 2048
+
+var (
+	unorderedItemRegex = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedItemRegex   = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	codeLanguageRegex  = regexp.MustCompile(`^[a-zA-Z0-9_+-]{1,32}$`)
+)
+
+// NewMarkdownRenderer creates markdown renderer
+func NewMarkdownRenderer() Renderer {
+	return MarkdownRenderer{}
+}
+
+// Render renders markdown text into sanitized html
+func (m MarkdownRenderer) Render(text string) string {
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	var (
+		out       strings.Builder
+		paragraph []string
+	)
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+
+		out.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				out.WriteString("<br>")
+			}
+			out.WriteString(renderInline(line, true))
+		}
+		out.WriteString("</p>")
+		paragraph = paragraph[:0]
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			i = renderCodeBlock(&out, lines, i)
+		case unorderedItemRegex.MatchString(line):
+			flush()
+			i = renderList(&out, lines, i, "ul", unorderedItemRegex)
+		case orderedItemRegex.MatchString(line):
+			flush()
+			i = renderList(&out, lines, i, "ol", orderedItemRegex)
+		case trimmed == "":
+			flush()
+			i++
+		default:
+			paragraph = append(paragraph, trimmed)
+			i++
+		}
+	}
+	flush()
+
+	return out.String()
+}
+
+// renderCodeBlock renders fenced block starting at line i and returns index of line after it,
+// unterminated block lasts till the end of text
+func renderCodeBlock(out *strings.Builder, lines []string, i int) int {
+	language := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "```"))
+
+	out.WriteString("<pre><code")
+	if codeLanguageRegex.MatchString(language) {
+		out.WriteString(` class="language-` + strings.ToLower(language) + `"`)
+	}
+	out.WriteString(">")
+
+	i++
+	for first := true; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "```" {
+			i++
+			break
+		}
+
+		if !first {
+			out.WriteString("\n")
+		}
+		out.WriteString(html.EscapeString(lines[i]))
+		first = false
+	}
+
+	out.WriteString("</code></pre>")
+	return i
+}
+
+// renderList renders consecutive list items starting at line i and returns index of line after them
+func renderList(out *strings.Builder, lines []string, i int, tag string, item *regexp.Regexp) int {
+	out.WriteString("<" + tag + ">")
+	for ; i < len(lines); i++ {
+		match := item.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+
+		out.WriteString("<li>" + renderInline(strings.TrimSpace(match[1]), true) + "</li>")
+	}
+	out.WriteString("</" + tag + ">")
+
+	return i
+}
+
+// renderInline renders emphasis, code spans and, if allowed, links of single line
+func renderInline(s string, links bool) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_[]()#+-.!", s[i+1]) >= 0:
+			out.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				out.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '[' && links:
+			if label, href, n, ok := parseLink(s[i:]); ok {
+				out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+				out.WriteString(renderInline(label, false))
+				out.WriteString("</a>")
+				i += n
+				continue
+			}
+
+		case (c == '*' || c == '_') && i+1 < len(s) && s[i+1] == c:
+			delim := s[i : i+2]
+			if end := strings.Index(s[i+2:], delim); end > 0 && emphasized(s, i, i+2+end, 2) {
+				out.WriteString("<strong>" + renderInline(s[i+2:i+2+end], links) + "</strong>")
+				i += end + 4
+				continue
+			}
+
+			// not a strong emphasis, both delimiters are text
+			out.WriteString(delim)
+			i += 2
+			continue
+
+		case c == '*' || c == '_':
+			if end := closingDelimiter(s[i+1:], c); end > 0 && emphasized(s, i, i+1+end, 1) {
+				out.WriteString("<em>" + renderInline(s[i+1:i+1+end], links) + "</em>")
+				i += end + 2
+				continue
+			}
+		}
+
+		out.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+
+	return out.String()
+}
+
+// emphasized checks that delimiters at start and end surround text without spaces
+// next to them, underscores inside words like snake_case are not emphasis
+func emphasized(s string, start, end, size int) bool {
+	inner := s[start+size : end]
+	if strings.TrimSpace(inner) != inner {
+		return false
+	}
+
+	if s[start] != '_' {
+		return true
+	}
+
+	before := start == 0 || !isWordByte(s[start-1])
+	after := end+size >= len(s) || !isWordByte(s[end+size])
+	return before && after
+}
+
+// closingDelimiter finds single delimiter skipping doubled ones
+func closingDelimiter(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == c {
+			i++
+			continue
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// parseLink parses [label](url) at the beginning of s, only http, https
+// and mailto links are accepted, scans stop at the next bracket and at maxLinkHref
+// bytes of url so text full of brackets renders in linear time
+func parseLink(s string) (label, href string, n int, ok bool) {
+	labelEnd := strings.IndexAny(s[1:], "[]") + 1
+	if labelEnd <= 1 || s[labelEnd] != ']' || labelEnd+1 >= len(s) || s[labelEnd+1] != '(' {
+		return "", "", 0, false
+	}
+
+	rest := s[labelEnd+2:]
+	if len(rest) > maxLinkHref {
+		rest = rest[:maxLinkHref+1]
+	}
+
+	// Bracket can not be part of url, it starts next link
+	hrefEnd := strings.IndexAny(rest, ") \t[")
+	if hrefEnd <= 0 || rest[hrefEnd] != ')' {
+		return "", "", 0, false
+	}
+
+	label = s[1:labelEnd]
+	href = rest[:hrefEnd]
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", "", 0, false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", "", 0, false
+		}
+	case "mailto":
+	default:
+		return "", "", 0, false
+	}
+
+	return label, u.String(), labelEnd + 2 + hrefEnd + 1, true
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
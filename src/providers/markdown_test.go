@@ -0,0 +1,78 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownRenderer(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	for name, tc := range map[string]struct {
+		text string
+		html string
+	}{
+		"Plain":      {"hello", "<p>hello</p>"},
+		"Line break": {"hello\r\nworld", "<p>hello<br>world</p>"},
+		"Paragraphs": {"hello\n\n  world  ", "<p>hello</p><p>world</p>"},
+		"Bold":       {"**bold** and __bold__", "<p><strong>bold</strong> and <strong>bold</strong></p>"},
+		"Italics":    {"*italic* and _italic_", "<p><em>italic</em> and <em>italic</em></p>"},
+		"Nested":     {"*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		"Snake case": {"snake_case_name", "<p>snake_case_name</p>"},
+		"Spaces":     {"2 * 3 * 4", "<p>2 * 3 * 4</p>"},
+		"Unclosed":   {"**bold", "<p>**bold</p>"},
+		"Escaped":    {`\*not italic\*`, "<p>*not italic*</p>"},
+		"Code":       {"`a <b> *c*`", "<p><code>a &lt;b&gt; *c*</code></p>"},
+		"Code block": {
+			"```go\nfunc main() {\n\t<b>\n}\n```\nafter",
+			"<pre><code class=\"language-go\">func main() {\n\t&lt;b&gt;\n}</code></pre><p>after</p>",
+		},
+		"Code block language": {"```\" onclick=\"x\nx\n```", "<pre><code>x</code></pre>"},
+		"Unterminated block":  {"```\nx", "<pre><code>x</code></pre>"},
+		"Unordered list":      {"list:\n- one\n* **two**\n\nend", "<p>list:</p><ul><li>one</li><li><strong>two</strong></li></ul><p>end</p>"},
+		"Ordered list":        {"1. one\n2) two", "<ol><li>one</li><li>two</li></ol>"},
+		"Link": {
+			"[site *x*](https://example.com/a?b=c&d=e)",
+			`<p><a href="https://example.com/a?b=c&amp;d=e" rel="nofollow noopener noreferrer" target="_blank">site <em>x</em></a></p>`,
+		},
+		"Mailto": {
+			"[mail](mailto:user@example.com)",
+			`<p><a href="mailto:user@example.com" rel="nofollow noopener noreferrer" target="_blank">mail</a></p>`,
+		},
+		"Nested link": {"[[a](https://a.com)](https://b.com)", `<p>[<a href="https://a.com" rel="nofollow noopener noreferrer" target="_blank">a</a>](https://b.com)</p>`},
+		"Script":      {"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		"Javascript":  {"[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>"},
+		"Data":        {"[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>[x](data:text/html;base64,PHNjcmlwdD4=)</p>"},
+		"Relative":    {"[x](//evil.com)", "<p>[x](//evil.com)</p>"},
+		"Attribute": {
+			`[x](https://a.com/"onmouseover="alert(1))`,
+			`<p><a href="https://a.com/%22onmouseover=%22alert%281" rel="nofollow noopener noreferrer" target="_blank">x</a>)</p>`,
+		},
+		"Image tag": {`<img src=x onerror=alert(1)>`, "<p>&lt;img src=x onerror=alert(1)&gt;</p>"},
+		"Quotes":    {`"a" & 'b'`, "<p>&#34;a&#34; &amp; &#39;b&#39;</p>"},
+		"Unicode":   {"**привет** _мир_", "<p><strong>привет</strong> <em>мир</em></p>"},
+	} {
+		require.Equal(t, tc.html, renderer.Render(tc.text), name)
+	}
+}
+
+func TestMarkdownRendererBrackets(t *testing.T) {
+	renderer := NewMarkdownRenderer()
+
+	long := "[x](https://example.com/" + strings.Repeat("a", maxLinkHref) + ")"
+	require.NotContains(t, renderer.Render(long), "<a ")
+
+	// unmatched brackets must not make rendering quadratic
+	for _, text := range []string{
+		strings.Repeat("[", 1<<20),
+		strings.Repeat("[a", 1<<19) + "](",
+		strings.Repeat("[a](", 1<<18),
+	} {
+		start := time.Now()
+		renderer.Render(text)
+		require.True(t, time.Since(start) < time.Second, "rendering took %v", time.Since(start))
+	}
+}
@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/providers/unfurl.go
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	providers "github.com/playneta/go-sessions/src/providers"
+	reflect "reflect"
+)
+
+// MockUnfurler is a mock of Unfurler interface
+type MockUnfurler struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnfurlerMockRecorder
+}
+
+// MockUnfurlerMockRecorder is the mock recorder for MockUnfurler
+type MockUnfurlerMockRecorder struct {
+	mock *MockUnfurler
+}
+
+// NewMockUnfurler creates a new mock instance
+func NewMockUnfurler(ctrl *gomock.Controller) *MockUnfurler {
+	mock := &MockUnfurler{ctrl: ctrl}
+	mock.recorder = &MockUnfurlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUnfurler) EXPECT() *MockUnfurlerMockRecorder {
+	return m.recorder
+}
+
+// Unfurl mocks base method
+func (m *MockUnfurler) Unfurl(ctx context.Context, link string) (*providers.LinkMeta, error) {
+	ret := m.ctrl.Call(m, "Unfurl", ctx, link)
+	ret0, _ := ret[0].(*providers.LinkMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfurl indicates an expected call of Unfurl
+func (mr *MockUnfurlerMockRecorder) Unfurl(ctx, link interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfurl", reflect.TypeOf((*MockUnfurler)(nil).Unfurl), ctx, link)
+}
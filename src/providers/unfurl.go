@@ -0,0 +1,278 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/html"
+)
+
+type (
+	// Unfurler fetches OpenGraph and Twitter card metadata of web pages
+	Unfurler interface {
+		Unfurl(ctx context.Context, link string) (*LinkMeta, error)
+	}
+
+	// LinkMeta is metadata describing web page
+	LinkMeta struct {
+		Title       string
+		Description string
+		Image       string
+		SiteName    string
+	}
+
+	httpUnfurler struct {
+		client  *http.Client
+		maxSize int64
+	}
+)
+
+const (
+	maxRedirects   = 3
+	maxTitle       = 300
+	maxDescription = 1000
+	maxSiteName    = 255
+)
+
+var (
+	ErrForbiddenAddress = errors.New("address is not allowed")
+	ErrNotHTML          = errors.New("page is not html")
+
+	// blockedNetworks are never fetched so users could not make server
+	// reach internal services by posting links to them
+	blockedNetworks = parseNetworks(
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+		"::/128",
+		"::1/128",
+		"64:ff9b::/96",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	)
+)
+
+// NewUnfurler creates unfurler limited by previews.timeout and previews.max_size
+func NewUnfurler(config *viper.Viper) Unfurler {
+	config.SetDefault("previews.timeout", 5*time.Second)
+	config.SetDefault("previews.max_size", 1<<20)
+
+	return newHTTPUnfurler(
+		config.GetDuration("previews.timeout"),
+		config.GetInt64("previews.max_size"),
+		PublicIP,
+	)
+}
+
+// newHTTPUnfurler creates unfurler connecting only to addresses accepted by allow,
+// check is done on resolved address right before connecting so DNS rebinding
+// and redirects to internal hosts are blocked as well
+func newHTTPUnfurler(timeout time.Duration, maxSize int64, allow func(ip net.IP) bool) *httpUnfurler {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return ErrForbiddenAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       time.Minute,
+	}
+
+	return &httpUnfurler{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("too many redirects")
+				}
+
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return ErrForbiddenAddress
+				}
+
+				return nil
+			},
+		},
+		maxSize: maxSize,
+	}
+}
+
+// PublicIP reports whether ip belongs to public internet
+func PublicIP(ip net.IP) bool {
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (u *httpUnfurler) Unfurl(ctx context.Context, link string) (*LinkMeta, error) {
+	target, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, ErrForbiddenAddress
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "go-sessions link preview")
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if contentType != "text/html" && contentType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	meta := parseMeta(io.LimitReader(res.Body, u.maxSize))
+
+	if meta.Image != "" {
+		image, err := res.Request.URL.Parse(meta.Image)
+		if err != nil || (image.Scheme != "http" && image.Scheme != "https") {
+			meta.Image = ""
+		} else {
+			meta.Image = image.String()
+		}
+	}
+
+	return meta, nil
+}
+
+// parseMeta reads head of html document, OpenGraph properties
+// win over Twitter cards which win over plain html tags
+func parseMeta(r io.Reader) *LinkMeta {
+	props := make(map[string]string)
+	var title string
+
+	tokenizer := html.NewTokenizer(r)
+	for done := false; !done; {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			done = true
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "body":
+				done = true
+			case "title":
+				if tokenizer.Next() == html.TextToken && title == "" {
+					title = string(tokenizer.Text())
+				}
+			case "meta":
+				var key, content string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = tokenizer.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						key = strings.ToLower(string(v))
+					case "content":
+						content = string(v)
+					}
+				}
+
+				if _, ok := props[key]; key != "" && !ok {
+					props[key] = content
+				}
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				done = true
+			}
+		}
+	}
+
+	first := func(values ...string) string {
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	return &LinkMeta{
+		Title:       truncate(first(props["og:title"], props["twitter:title"], title), maxTitle),
+		Description: truncate(first(props["og:description"], props["twitter:description"], props["description"]), maxDescription),
+		Image:       first(props["og:image"], props["og:image:url"], props["twitter:image"], props["twitter:image:src"]),
+		SiteName:    truncate(first(props["og:site_name"]), maxSiteName),
+	}
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+
+	return s[:limit]
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+
+	return networks
+}
@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func allowAll(ip net.IP) bool {
+	return true
+}
+
+func TestPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.20.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"::ffff:10.0.0.1": false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+	} {
+		require.Equal(t, public, PublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestUnfurl(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/og", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head>
+			<title>Plain title</title>
+			<meta property="og:title" content="OpenGraph &amp; title">
+			<meta name="twitter:title" content="Twitter title">
+			<meta name="description" content="Plain description">
+			<meta property="og:image" content="/image.png">
+			<meta property="og:site_name" content="Example">
+		</head><body><meta property="og:description" content="ignored"></body></html>`)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Plain title</title>
+			<meta name="twitter:image" content="javascript:alert(1)"></head></html>`)
+	})
+	mux.HandleFunc("/long", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><meta property="og:site_name" content="`+strings.Repeat("s", 300)+`"></head></html>`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/og", http.StatusFound)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head>"+strings.Repeat("<!-- padding -->", 1000))
+		fmt.Fprint(w, `<title>Too far</title></head></html>`)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	unfurler := newHTTPUnfurler(100*time.Millisecond, 4096, allowAll)
+	ctx := context.Background()
+
+	t.Run("OpenGraph", func(t *testing.T) {
+		meta, err := unfurler.Unfurl(ctx, server.URL+"/redirect")
+		require.NoError(t, err)
+		require.Equal(t, &LinkMeta{
+			Title:       "OpenGraph & title",
+			Description: "Plain description",
+			Image:       server.URL + "/image.png",
+			SiteName:    "Example",
+		}, meta)
+	})
+
+	t.Run("Plain html", func(t *testing.T) {
+		meta, err := unfurler.Unfurl(ctx, server.URL+"/plain")
+		require.NoError(t, err)
+		require.Equal(t, &LinkMeta{Title: "Plain title"}, meta)
+	})
+
+	t.Run("Long site name", func(t *testing.T) {
+		meta, err := unfurler.Unfurl(ctx, server.URL+"/long")
+		require.NoError(t, err)
+		require.Len(t, meta.SiteName, maxSiteName)
+	})
+
+	t.Run("Size limit", func(t *testing.T) {
+		meta, err := unfurler.Unfurl(ctx, server.URL+"/large")
+		require.NoError(t, err)
+		require.Equal(t, "", meta.Title)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		_, err := unfurler.Unfurl(ctx, server.URL+"/slow")
+		require.Error(t, err)
+	})
+
+	t.Run("Not html", func(t *testing.T) {
+		_, err := unfurler.Unfurl(ctx, server.URL+"/image.png")
+		require.Equal(t, ErrNotHTML, err)
+	})
+
+	t.Run("Scheme", func(t *testing.T) {
+		_, err := unfurler.Unfurl(ctx, "file:///etc/passwd")
+		require.Equal(t, ErrForbiddenAddress, err)
+	})
+
+	t.Run("Private address", func(t *testing.T) {
+		_, err := newHTTPUnfurler(time.Second, 4096, PublicIP).Unfurl(ctx, server.URL+"/og")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), ErrForbiddenAddress.Error())
+	})
+
+	t.Run("Redirect to private address", func(t *testing.T) {
+		public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, server.URL+"/og", http.StatusFound)
+		}))
+		defer public.Close()
+
+		// only connection to the first server is allowed
+		dials := 0
+		u := newHTTPUnfurler(time.Second, 4096, func(ip net.IP) bool {
+			dials++
+			return dials == 1
+		})
+
+		_, err := u.Unfurl(ctx, public.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), ErrForbiddenAddress.Error())
+	})
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Archive interface {
+		Create(segment *models.ArchiveSegment, ids []int64) (bool, error)
+		Segments(conversation string, from, to time.Time) ([]models.ArchiveSegment, error)
+		Containing(conversation string, id int64) ([]models.ArchiveSegment, error)
+		Prunable(scope string) ([]models.ArchiveSegment, error)
+		Replace(key string, segment *models.ArchiveSegment) (bool, error)
+		Delete(segment models.ArchiveSegment) (bool, error)
+	}
+
+	archiveRepository struct {
+		db *pg.DB
+	}
+)
+
+// ErrArchiveChanged is returned by Create when messages of segment were deleted or
+// got related rows after they were read, nothing is recorded and they are kept
+var ErrArchiveChanged = errors.New("archived messages changed meanwhile")
+
+func NewArchive(db *pg.DB) Archive {
+	return &archiveRepository{
+		db: db,
+	}
+}
+
+// Create records segment and deletes archived messages, false is returned without
+// deleting anything when segment with the same key was recorded by another instance,
+// ErrArchiveChanged when any of messages can not be archived anymore
+func (a *archiveRepository) Create(segment *models.ArchiveSegment, ids []int64) (bool, error) {
+	created := false
+	err := a.db.RunInTransaction(func(tx *pg.Tx) error {
+		res, err := tx.Model(segment).OnConflict("(key) DO NOTHING").Insert()
+		if err != nil {
+			return err
+		}
+
+		if res.RowsAffected() == 0 {
+			return nil
+		}
+
+		// Locked messages can not get new reactions, pins and others until commit,
+		// those added since they were read keep them out of the segment
+		if _, err := tx.Exec(`SELECT id FROM messages WHERE id IN (?) FOR UPDATE`, pg.In(ids)); err != nil {
+			return err
+		}
+
+		res, err = tx.Exec(`DELETE FROM messages AS held WHERE held.id IN (?) AND NOT `+heldBackCondition, pg.In(ids))
+		if err != nil {
+			return err
+		}
+
+		if res.RowsAffected() != len(ids) {
+			return ErrArchiveChanged
+		}
+
+		created = true
+		return nil
+	})
+
+	return created, err
+}
+
+// Segments returns segments of conversation, of all of them when it is empty, holding
+// messages created within [from, to], zero bounds are open, ordered by their first message
+func (a *archiveRepository) Segments(conversation string, from, to time.Time) ([]models.ArchiveSegment, error) {
+	segments := make([]models.ArchiveSegment, 0)
+	q := a.db.Model(&segments)
+
+	if conversation != "" {
+		q = q.Where("conversation=?", conversation)
+	}
+
+	if !from.IsZero() {
+		q = q.Where("last_at>=?", from)
+	}
+
+	if !to.IsZero() {
+		q = q.Where("first_at<=?", to)
+	}
+
+	if err := q.Order("first_at", "id").Select(); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// Containing returns segments of conversation which may hold message with id
+func (a *archiveRepository) Containing(conversation string, id int64) ([]models.ArchiveSegment, error) {
+	segments := make([]models.ArchiveSegment, 0)
+	if err := a.db.Model(&segments).
+		Where("conversation=?", conversation).
+		Where("min_id<=? and max_id>=?", id, id).
+		Order("first_at", "id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// Prunable returns segments of retention scope grouped by conversation, newest first
+func (a *archiveRepository) Prunable(scope string) ([]models.ArchiveSegment, error) {
+	segments := make([]models.ArchiveSegment, 0)
+	q := a.db.Model(&segments)
+
+	if scope == models.RetentionPublic {
+		q = q.Where("conversation=?", models.PublicConversation)
+	} else {
+		q = q.Where("conversation<>?", models.PublicConversation)
+	}
+
+	if err := q.Order("conversation", "last_at DESC", "id DESC").Select(); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// Replace points record of segment stored under key to its rewritten version, false
+// is returned when another instance rewrote or deleted the segment first
+func (a *archiveRepository) Replace(key string, segment *models.ArchiveSegment) (bool, error) {
+	res, err := a.db.Model(segment).
+		Column("key", "count", "min_id", "max_id", "first_at", "last_at").
+		WherePK().
+		Where("key=?", key).
+		Update()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// Delete removes record of segment, false is returned when another instance
+// rewrote or deleted the segment first
+func (a *archiveRepository) Delete(segment models.ArchiveSegment) (bool, error) {
+	res, err := a.db.Exec(`DELETE FROM archive_segments WHERE id=? AND key=?`, segment.Id, segment.Key)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	first := createMessage(t, db, alice, nil, "first", january.Add(time.Hour))
+	second := createMessage(t, db, alice, nil, "second", january.Add(2*time.Hour))
+	kept := createMessage(t, db, alice, nil, "kept", january.AddDate(0, 1, 0))
+
+	repo := NewArchive(db)
+	segment := models.ArchiveSegment{
+		Conversation: models.PublicConversation,
+		Month:        january,
+		Key:          "archive/public/2026-01/1-2.ndjson.gz",
+		Count:        2,
+		MinId:        first.Id,
+		MaxId:        second.Id,
+		FirstAt:      first.CreatedAt,
+		LastAt:       second.CreatedAt,
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		created, err := repo.Create(&segment, []int64{first.Id, second.Id})
+		require.NoError(t, err)
+		require.True(t, created)
+
+		count, err := db.Model((*models.Message)(nil)).Count()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("Created by another instance", func(t *testing.T) {
+		duplicate := segment
+		duplicate.Id = 0
+
+		created, err := repo.Create(&duplicate, []int64{kept.Id})
+		require.NoError(t, err)
+		require.False(t, created)
+
+		found, err := NewMessage(db).Find(kept.Id)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+	})
+
+	t.Run("Changed meanwhile", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE messages SET expires_at=now() + interval '1 hour' WHERE id=?`, kept.Id)
+		require.NoError(t, err)
+
+		changed := segment
+		changed.Id, changed.Key = 0, "archive/public/2026-02/3-3.ndjson.gz"
+
+		created, err := repo.Create(&changed, []int64{kept.Id})
+		require.Equal(t, ErrArchiveChanged, err)
+		require.False(t, created)
+
+		found, err := NewMessage(db).Find(kept.Id)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+
+		_, err = db.Exec(`UPDATE messages SET expires_at=NULL WHERE id=?`, kept.Id)
+		require.NoError(t, err)
+	})
+
+	t.Run("Segments", func(t *testing.T) {
+		segments, err := repo.Segments(models.PublicConversation, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		require.Equal(t, segment.Key, segments[0].Key)
+
+		segments, err = repo.Segments("", january.AddDate(0, 0, 1), time.Time{})
+		require.NoError(t, err)
+		require.Empty(t, segments)
+
+		segments, err = repo.Segments("dm:1:2", time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Empty(t, segments)
+	})
+
+	t.Run("Containing", func(t *testing.T) {
+		segments, err := repo.Containing(models.PublicConversation, second.Id)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+
+		segments, err = repo.Containing(models.PublicConversation, kept.Id)
+		require.NoError(t, err)
+		require.Empty(t, segments)
+	})
+
+	t.Run("Prunable", func(t *testing.T) {
+		segments, err := repo.Prunable(models.RetentionPublic)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+
+		segments, err = repo.Prunable(models.RetentionPrivate)
+		require.NoError(t, err)
+		require.Empty(t, segments)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		rewritten := segment
+		rewritten.Key = "archive/public/2026-01/2-2.ndjson.gz"
+		rewritten.Count = 1
+		rewritten.MinId = second.Id
+
+		replaced, err := repo.Replace(segment.Key, &rewritten)
+		require.NoError(t, err)
+		require.True(t, replaced)
+
+		// segment was rewritten already
+		replaced, err = repo.Replace(segment.Key, &rewritten)
+		require.NoError(t, err)
+		require.False(t, replaced)
+
+		segment = rewritten
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		deleted, err := repo.Delete(segment)
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		deleted, err = repo.Delete(segment)
+		require.NoError(t, err)
+		require.False(t, deleted)
+	})
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Attachment interface {
+		Create(attachment *models.Attachment) error
+		Find(id int64) (*models.Attachment, error)
+		Unlinked(user models.User, ids []int64) ([]models.Attachment, error)
+		ForMessages(messageIDs []int64) (map[int64][]models.Attachment, error)
+	}
+
+	attachmentRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewAttachment(db *pg.DB) Attachment {
+	return &attachmentRepository{
+		db: db,
+	}
+}
+
+func (a *attachmentRepository) Create(attachment *models.Attachment) error {
+	_, err := a.db.Model(attachment).Insert()
+	return err
+}
+
+func (a *attachmentRepository) Find(id int64) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := a.db.Model(&attachment).Where("id=?", id).First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// Unlinked returns attachments of user not yet referenced by any message
+func (a *attachmentRepository) Unlinked(user models.User, ids []int64) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+
+	if err := a.db.Model(&attachments).
+		Where("id IN (?) and user_id=? and message_id IS NULL", pg.In(ids), user.ID).
+		Order("id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *attachmentRepository) ForMessages(messageIDs []int64) (map[int64][]models.Attachment, error) {
+	attachments := make(map[int64][]models.Attachment)
+	if len(messageIDs) == 0 {
+		return attachments, nil
+	}
+
+	var rows []models.Attachment
+	if err := a.db.Model(&rows).
+		Where("message_id IN (?)", pg.In(messageIDs)).
+		Order("id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range rows {
+		attachments[attachment.MessageId] = append(attachments[attachment.MessageId], attachment)
+	}
+
+	return attachments, nil
+}
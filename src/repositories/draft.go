@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Draft interface {
+		Save(draft *models.Draft) error
+		Find(userID int64, conversation string) (*models.Draft, error)
+		Delete(userID int64, conversation string) (bool, error)
+	}
+
+	draftRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewDraft(db *pg.DB) Draft {
+	return &draftRepository{
+		db: db,
+	}
+}
+
+// Save stores draft replacing the one user had in conversation
+func (d *draftRepository) Save(draft *models.Draft) error {
+	_, err := d.db.Model(draft).
+		OnConflict("(user_id, conversation) DO UPDATE").
+		Set("text=EXCLUDED.text, updated_at=EXCLUDED.updated_at").
+		Insert()
+
+	return err
+}
+
+func (d *draftRepository) Find(userID int64, conversation string) (*models.Draft, error) {
+	var draft models.Draft
+	if err := d.db.Model(&draft).
+		Where("user_id=? and conversation=?", userID, conversation).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &draft, nil
+}
+
+// Delete removes draft and reports whether user had one in conversation
+func (d *draftRepository) Delete(userID int64, conversation string) (bool, error) {
+	res, err := d.db.Model((*models.Draft)(nil)).
+		Where("user_id=? and conversation=?", userID, conversation).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
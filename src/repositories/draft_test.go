@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraft(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	repo := NewDraft(db)
+
+	draft, err := repo.Find(alice.ID, models.PublicConversation)
+	require.NoError(t, err)
+	require.Nil(t, draft)
+
+	require.NoError(t, repo.Save(&models.Draft{UserId: alice.ID, Conversation: models.PublicConversation, Text: "first", UpdatedAt: time.Now()}))
+	require.NoError(t, repo.Save(&models.Draft{UserId: alice.ID, Conversation: models.PublicConversation, Text: "second", UpdatedAt: time.Now()}))
+
+	draft, err = repo.Find(alice.ID, models.PublicConversation)
+	require.NoError(t, err)
+	require.Equal(t, "second", draft.Text)
+
+	deleted, err := repo.Delete(alice.ID, models.PublicConversation)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	deleted, err = repo.Delete(alice.ID, models.PublicConversation)
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/pressly/goose"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+// testDB connects to database at TEST_DB_URL, migrates it and empties tables,
+// repository tests are skipped when it is not set since they need real postgres
+func testDB(t *testing.T) *pg.DB {
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+
+	conn, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, goose.Up(conn, "../../migrations"))
+
+	opts, err := pg.ParseURL(url)
+	require.NoError(t, err)
+
+	db := pg.Connect(opts)
+	_, err = db.Exec("TRUNCATE users, messages, archive_segments RESTART IDENTITY CASCADE")
+	require.NoError(t, err)
+
+	return db
+}
+
+func createUser(t *testing.T, db *pg.DB, email string) *models.User {
+	user, err := NewUser(db).Create(email, "password_hash")
+	require.NoError(t, err)
+
+	return user
+}
+
+// createMessage stores message as is, unlike repository it keeps given creation time
+func createMessage(t *testing.T, db *pg.DB, from, to *models.User, text string, createdAt time.Time) *models.Message {
+	message := &models.Message{
+		UserId:    from.ID,
+		Text:      text,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	if to != nil {
+		message.ReceiverId = to.ID
+	}
+
+	_, err := db.Model(message).Insert()
+	require.NoError(t, err)
+
+	return message
+}
+
+func texts(messages []models.Message) []string {
+	result := make([]string, len(messages))
+	for i, message := range messages {
+		result[i] = message.Text
+	}
+
+	return result
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Mention interface {
+		ForMessages(messageIDs []int64) (map[int64][]models.Mention, error)
+		ForUser(user models.User, limit int) ([]models.Mention, error)
+	}
+
+	mentionRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewMention(db *pg.DB) Mention {
+	return &mentionRepository{
+		db: db,
+	}
+}
+
+func (m *mentionRepository) ForMessages(messageIDs []int64) (map[int64][]models.Mention, error) {
+	mentions := make(map[int64][]models.Mention)
+	if len(messageIDs) == 0 {
+		return mentions, nil
+	}
+
+	var rows []models.Mention
+	if err := m.db.Model(&rows).
+		Column("mention.*").
+		Relation("User").
+		Where("mention.message_id IN (?)", pg.In(messageIDs)).
+		Order("mention.id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	for _, mention := range rows {
+		mentions[mention.MessageId] = append(mentions[mention.MessageId], mention)
+	}
+
+	return mentions, nil
+}
+
+// ForUser returns latest mentions of user by other people, including @all
+func (m *mentionRepository) ForUser(user models.User, limit int) ([]models.Mention, error) {
+	var mentions []models.Mention
+	if err := m.db.Model(&mentions).
+		Column("mention.*").
+		Relation("Message").
+		Relation("Message.User").
+		Relation("Message.Receiver").
+		Where("(mention.user_id=? or mention.kind=?) and message.user_id<>?", user.ID, models.MentionAll, user.ID).
+		Where(notExpiredCondition, time.Now()).
+		Order("mention.id desc").
+		Limit(limit).Select(); err != nil {
+		if err == pg.ErrNoRows {
+			return []models.Mention{}, nil
+		}
+
+		return nil, err
+	}
+
+	return mentions, nil
+}
@@ -1,22 +1,84 @@
 package repositories
 
 import (
+	"errors"
+	"html"
+	"strings"
+	"time"
+
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 	"github.com/playneta/go-sessions/src/models"
 )
 
 type (
 	Message interface {
 		Create(message *models.Message) error
+		Find(id int64) (*models.Message, error)
+		FindByClientID(user models.User, clientMsgID string) (*models.Message, error)
 		LastPublicMessages(limit int) ([]models.Message, error)
-		LastPrivateMessages(user models.User, limit int) ([]models.Message, error)
+		LastPrivateMessages(user models.User, conversations, limit int) ([]models.Message, error)
+		History(query models.HistoryQuery) ([]models.Message, error)
+		Missed(user models.User, lastID int64, limit int) ([]models.Message, error)
+		Undelivered(user models.User, limit int) ([]models.Message, error)
+		MarkDelivered(ids []int64, at time.Time) ([]int64, error)
+		Search(user models.User, query models.SearchQuery) ([]models.SearchResult, error)
+		Expired(now time.Time, limit int) ([]models.Message, error)
+		Prunable(policy models.RetentionPolicy, now time.Time, limit int) ([]int64, error)
+		CountPrunable(policy models.RetentionPolicy, now time.Time) (int, error)
+		Delete(ids []int64) ([]int64, error)
+		Export(query models.ExportQuery, fn func(messages []models.Message) error) error
+		Import(messages []models.Message) (int, error)
+		Archivable(before time.Time, limit int) ([]models.Message, error)
+		Count(conversation string) (int, error)
 	}
 
 	messageRepository struct {
 		db *pg.DB
 	}
+
+	// exportRow is message joined with its participants read from export cursor
+	exportRow struct {
+		Id               int64
+		UserId           int64
+		ReceiverId       int64
+		Type             string
+		Text             string
+		Format           string
+		CreatedAt        time.Time
+		SenderEmail      string
+		SenderNickname   string
+		ReceiverEmail    string
+		ReceiverNickname string
+	}
+)
+
+// conversationExpr computes conversation key of message row,
+// it must match models.Message.Conversation
+const conversationExpr = `CASE WHEN message.receiver_id IS NULL THEN 'public' ` +
+	`ELSE 'dm:' || least(message.user_id, message.receiver_id) || ':' || greatest(message.user_id, message.receiver_id) END`
+
+// notExpiredCondition hides self-destructing messages once they expire,
+// even if reaper has not deleted them yet
+const notExpiredCondition = "(message.expires_at IS NULL OR message.expires_at > ?)"
+
+// Search highlights are marked with control characters which are replaced
+// with tags after the snippet is escaped, so message text never becomes html
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+	headlineOpts   = "'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=30, MinWords=10'"
 )
 
+// ErrDuplicateMessage is returned by Create when user already sent message with the same client id
+var ErrDuplicateMessage = errors.New("message with this client id already exists")
+
+// ErrAttachmentTaken is returned by Create when concurrent send linked one of attachments first
+var ErrAttachmentTaken = errors.New("attachment is already linked to another message")
+
+// clientMsgIDIndex is unique index dropping repeated sends of the same client message
+const clientMsgIDIndex = "messages_client_msg_id_idx"
+
 func NewMessage(db *pg.DB) Message {
 	return &messageRepository{
 		db: db,
@@ -24,7 +86,52 @@ func NewMessage(db *pg.DB) Message {
 }
 
 func (m *messageRepository) Create(message *models.Message) error {
-	if _, err := m.db.Model(message).Relation("User").Insert(); err != nil {
+	if err := m.db.RunInTransaction(func(tx *pg.Tx) error {
+		if _, err := tx.Model(message).Insert(); err != nil {
+			return err
+		}
+
+		if len(message.Attachments) > 0 {
+			ids := make([]int64, len(message.Attachments))
+			for i := range message.Attachments {
+				message.Attachments[i].MessageId = message.Id
+				ids[i] = message.Attachments[i].Id
+			}
+
+			res, err := tx.Model((*models.Attachment)(nil)).
+				Set("message_id=?", message.Id).
+				Where("id IN (?) and message_id IS NULL", pg.In(ids)).
+				Update()
+			if err != nil {
+				return err
+			}
+
+			if res.RowsAffected() != len(ids) {
+				return ErrAttachmentTaken
+			}
+		}
+
+		if message.Poll != nil {
+			if err := createPoll(tx, message); err != nil {
+				return err
+			}
+		}
+
+		if len(message.Mentions) == 0 {
+			return nil
+		}
+
+		for i := range message.Mentions {
+			message.Mentions[i].MessageId = message.Id
+		}
+
+		_, err := tx.Model(&message.Mentions).Insert()
+		return err
+	}); err != nil {
+		if pgErr, ok := err.(pg.Error); ok && pgErr.IntegrityViolation() && pgErr.Field('n') == clientMsgIDIndex {
+			return ErrDuplicateMessage
+		}
+
 		return err
 	}
 
@@ -38,11 +145,48 @@ func (m *messageRepository) Create(message *models.Message) error {
 	return nil
 }
 
+func (m *messageRepository) Find(id int64) (*models.Message, error) {
+	var message models.Message
+	if err := m.db.Model(&message).
+		Column("message.*").
+		Relation("User").Relation("Receiver").
+		Where("message.id=?", id).
+		Where(notExpiredCondition, time.Now()).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// FindByClientID returns message user sent with client id
+func (m *messageRepository) FindByClientID(user models.User, clientMsgID string) (*models.Message, error) {
+	var message models.Message
+	if err := m.db.Model(&message).
+		Column("message.*").
+		Relation("User").Relation("Receiver").
+		Where("message.user_id=? and message.client_msg_id=?", user.ID, clientMsgID).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &message, nil
+}
+
 func (m *messageRepository) LastPublicMessages(limit int) ([]models.Message, error) {
 	var messages []models.Message
 	if err := m.db.Model(&messages).
 		Column("message.*").
 		Where("receiver_id IS NULL").
+		Where(notExpiredCondition, time.Now()).
 		Order("id desc").
 		Relation("Receiver").
 		Relation("User").
@@ -57,21 +201,498 @@ func (m *messageRepository) LastPublicMessages(limit int) ([]models.Message, err
 	return messages, nil
 }
 
-func (m *messageRepository) LastPrivateMessages(user models.User, limit int) ([]models.Message, error) {
+// LastPrivateMessages returns last messages of private conversations of user,
+// sent and received alike, at most limit of each of the most recently active conversations
+func (m *messageRepository) LastPrivateMessages(user models.User, conversations, limit int) ([]models.Message, error) {
+	var ids []int64
+	if _, err := m.db.Query(&ids, `
+		WITH private AS (
+			SELECT message.id, message.created_at,
+				least(message.user_id, message.receiver_id) AS a,
+				greatest(message.user_id, message.receiver_id) AS b
+			FROM messages AS message
+			WHERE message.receiver_id IS NOT NULL
+				AND (message.user_id=?0 OR message.receiver_id=?0)
+				AND (message.expires_at IS NULL OR message.expires_at > ?1)
+		), active AS (
+			SELECT a, b FROM private GROUP BY a, b ORDER BY max(created_at) DESC LIMIT ?2
+		)
+		SELECT id FROM (
+			SELECT private.id, row_number() OVER (PARTITION BY private.a, private.b ORDER BY private.created_at DESC, private.id DESC) AS n
+			FROM private JOIN active ON active.a=private.a AND active.b=private.b
+		) AS ranked
+		WHERE n <= ?3`, user.ID, time.Now(), conversations, limit); err != nil {
+		return nil, err
+	}
+
+	messages := []models.Message{}
+	if len(ids) == 0 {
+		return messages, nil
+	}
+
+	if err := m.db.Model(&messages).
+		Column("message.*").
+		Where("message.id IN (?)", pg.In(ids)).
+		Order("message.created_at desc", "message.id desc").
+		Relation("Receiver").
+		Relation("User").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// History returns messages of conversation bounded by query cursors, when only
+// After is set messages are the oldest ones after it, otherwise the newest ones
+// before Before, either way they are ordered oldest first
+func (m *messageRepository) History(query models.HistoryQuery) ([]models.Message, error) {
+	var messages []models.Message
+	q, err := inConversation(m.db.Model(&messages).
+		Column("message.*").
+		Relation("Receiver").
+		Relation("User").
+		Where(notExpiredCondition, time.Now()), query.Conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.BeforeKey != nil {
+		q = q.Where("(message.created_at, message.id) < (?, ?)", query.BeforeKey.CreatedAt, query.BeforeKey.Id)
+	} else if query.Before > 0 {
+		q = q.Where("(message.created_at, message.id) < (SELECT created_at, id FROM messages WHERE id=?)", query.Before)
+	}
+
+	if query.AfterKey != nil {
+		q = q.Where("(message.created_at, message.id) > (?, ?)", query.AfterKey.CreatedAt, query.AfterKey.Id)
+	} else if query.After > 0 {
+		q = q.Where("(message.created_at, message.id) > (SELECT created_at, id FROM messages WHERE id=?)", query.After)
+	}
+
+	ascending := query.Ascending()
+	if ascending {
+		q = q.Order("message.created_at", "message.id")
+	} else {
+		q = q.Order("message.created_at desc", "message.id desc")
+	}
+
+	if err := q.Limit(query.Limit).Select(); err != nil {
+		return nil, err
+	}
+
+	if !ascending {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return messages, nil
+}
+
+// Missed returns messages visible to user posted after message with lastID,
+// when there are more than limit of them only the newest are returned, oldest first
+func (m *messageRepository) Missed(user models.User, lastID int64, limit int) ([]models.Message, error) {
 	var messages []models.Message
 	if err := m.db.Model(&messages).
 		Column("message.*").
-		Where("user_id=? and (receiver_id>0 or receiver_id=?)", user.ID, user.ID).
-		Order("id desc").
 		Relation("Receiver").
 		Relation("User").
+		Where("message.id>?", lastID).
+		Where("(message.receiver_id IS NULL OR message.user_id=? OR message.receiver_id=?)", user.ID, user.ID).
+		Where(notExpiredCondition, time.Now()).
+		Order("message.id desc").
 		Limit(limit).Select(); err != nil {
-		if err == pg.ErrNoRows {
-			return []models.Message{}, nil
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, nil
+}
+
+// Undelivered returns private messages sent to user which were not pushed
+// to any of user devices yet, oldest first
+func (m *messageRepository) Undelivered(user models.User, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	if err := m.db.Model(&messages).
+		Column("message.*").
+		Relation("Receiver").
+		Relation("User").
+		Where("message.receiver_id=? and message.delivered_at IS NULL", user.ID).
+		Where(notExpiredCondition, time.Now()).
+		Order("message.id").
+		Limit(limit).Select(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// MarkDelivered sets delivery time of messages and returns ids of those which
+// were not delivered before, so concurrent devices report delivery only once
+func (m *messageRepository) MarkDelivered(ids []int64, at time.Time) ([]int64, error) {
+	var delivered []int64
+	if len(ids) == 0 {
+		return delivered, nil
+	}
+
+	if _, err := m.db.Query(&delivered, `UPDATE messages SET delivered_at=? WHERE id IN (?) AND delivered_at IS NULL RETURNING id`, at, pg.In(ids)); err != nil {
+		return nil, err
+	}
+
+	return delivered, nil
+}
+
+// Search finds messages visible to user matching the query, best matches first
+func (m *messageRepository) Search(user models.User, query models.SearchQuery) ([]models.SearchResult, error) {
+	conditions := []string{
+		"message.search @@ q",
+		"(message.receiver_id IS NULL OR message.user_id = ? OR message.receiver_id = ?)",
+		notExpiredCondition,
+	}
+	params := []interface{}{query.Query, user.ID, user.ID, time.Now()}
+
+	if query.SenderId != 0 {
+		conditions = append(conditions, "message.user_id = ?")
+		params = append(params, query.SenderId)
+	}
+
+	if query.Conversation != "" {
+		a, b, err := models.ParseConversation(query.Conversation)
+		if err != nil {
+			return nil, err
+		}
+
+		if query.Conversation == models.PublicConversation {
+			conditions = append(conditions, "message.receiver_id IS NULL")
+		} else {
+			conditions = append(conditions, "((message.user_id = ? AND message.receiver_id = ?) OR (message.user_id = ? AND message.receiver_id = ?))")
+			params = append(params, a, b, b, a)
+		}
+	}
+
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "message.created_at >= ?")
+		params = append(params, query.Since)
+	}
+
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "message.created_at < ?")
+		params = append(params, query.Until)
+	}
+
+	params = append(params, query.Limit, query.Offset)
+
+	var rows []struct {
+		Id      int64
+		Snippet string
+		Rank    float64
+	}
+	if _, err := m.db.Query(&rows, `
+		SELECT message.id, ts_headline('simple', message.text, q, `+headlineOpts+`) AS snippet, ts_rank(message.search, q) AS rank
+		FROM messages AS message, plainto_tsquery('simple', ?) AS q
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY rank DESC, message.id DESC
+		LIMIT ? OFFSET ?`, params...); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.SearchResult, 0, len(rows))
+	if len(rows) == 0 {
+		return results, nil
+	}
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.Id
+	}
+
+	var messages []models.Message
+	if err := m.db.Model(&messages).
+		Column("message.*").
+		Relation("User").Relation("Receiver").
+		Where("message.id IN (?)", pg.In(ids)).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.Id] = message
+	}
+
+	for _, row := range rows {
+		message, ok := byID[row.Id]
+		if !ok {
+			continue
+		}
+
+		results = append(results, models.SearchResult{
+			Message: message,
+			Snippet: highlight(row.Snippet),
+			Rank:    row.Rank,
+		})
+	}
+
+	return results, nil
+}
+
+// Expired returns expired messages which are not deleted yet
+func (m *messageRepository) Expired(now time.Time, limit int) ([]models.Message, error) {
+	messages := make([]models.Message, 0)
+	if err := m.db.Model(&messages).
+		Column("message.*").
+		Relation("User").Relation("Receiver").
+		Where("message.expires_at <= ?", now).
+		Order("message.expires_at").
+		Limit(limit).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Prunable returns ids of messages outside of retention policy, oldest first
+func (m *messageRepository) Prunable(policy models.RetentionPolicy, now time.Time, limit int) ([]int64, error) {
+	query, params := prunableQuery(policy, now)
+
+	ids := make([]int64, 0)
+	if _, err := m.db.Query(&ids, query+` ORDER BY id LIMIT ?`, append(params, limit)...); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// CountPrunable returns number of messages outside of retention policy
+func (m *messageRepository) CountPrunable(policy models.RetentionPolicy, now time.Time) (int, error) {
+	query, params := prunableQuery(policy, now)
+
+	var count int
+	if _, err := m.db.QueryOne(pg.Scan(&count), `SELECT count(*) FROM (`+query+`) AS prunable`, params...); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// prunableQuery selects ids of messages outside of retention policy, messages of
+// scope are ranked newest first within their conversation so count limit is kept
+// per conversation, policy must be enabled
+func prunableQuery(policy models.RetentionPolicy, now time.Time) (string, []interface{}) {
+	scope, partition := "receiver_id IS NULL", ""
+	if policy.Scope == models.RetentionPrivate {
+		scope, partition = "receiver_id IS NOT NULL", "PARTITION BY least(user_id, receiver_id), greatest(user_id, receiver_id) "
+	}
+
+	var (
+		conditions []string
+		params     []interface{}
+	)
+
+	if policy.MaxAge > 0 {
+		conditions = append(conditions, "created_at < ?")
+		params = append(params, now.Add(-policy.MaxAge))
+	}
+
+	if policy.MaxCount > 0 {
+		conditions = append(conditions, "position > ?")
+		params = append(params, policy.MaxCount)
+	}
+
+	query := `SELECT id FROM (
+		SELECT id, created_at, row_number() OVER (` + partition + `ORDER BY created_at DESC, id DESC) AS position
+		FROM messages WHERE ` + scope + `
+	) AS ranked WHERE ` + strings.Join(conditions, " OR ")
+
+	return query, params
+}
+
+// Delete deletes messages and returns ids of those which were actually deleted by this call
+func (m *messageRepository) Delete(ids []int64) ([]int64, error) {
+	deleted := make([]int64, 0, len(ids))
+	if len(ids) == 0 {
+		return deleted, nil
+	}
+
+	if _, err := m.db.Query(&deleted, `DELETE FROM messages WHERE id IN (?) RETURNING id`, pg.In(ids)); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// Export passes messages matching query to fn in batches, oldest first, rows are read
+// with server side cursor so the whole history is never loaded into memory at once
+func (m *messageRepository) Export(query models.ExportQuery, fn func(messages []models.Message) error) error {
+	conditions := []string{notExpiredCondition}
+	params := []interface{}{time.Now()}
+
+	if query.Conversation != "" {
+		a, b, err := models.ParseConversation(query.Conversation)
+		if err != nil {
+			return err
 		}
 
+		if query.Conversation == models.PublicConversation {
+			conditions = append(conditions, "message.receiver_id IS NULL")
+		} else {
+			conditions = append(conditions, "message.receiver_id IS NOT NULL",
+				"least(message.user_id, message.receiver_id)=?", "greatest(message.user_id, message.receiver_id)=?")
+			params = append(params, a, b)
+		}
+	}
+
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "message.created_at >= ?")
+		params = append(params, query.Since)
+	}
+
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "message.created_at < ?")
+		params = append(params, query.Until)
+	}
+
+	// Cursor lives until the end of transaction
+	return m.db.RunInTransaction(func(tx *pg.Tx) error {
+		if _, err := tx.Exec(`DECLARE export_messages NO SCROLL CURSOR FOR
+			SELECT message.id, message.user_id, message.receiver_id, message.type, message.text, message.format, message.created_at,
+				sender.email AS sender_email, sender.nickname AS sender_nickname,
+				receiver.email AS receiver_email, receiver.nickname AS receiver_nickname
+			FROM messages AS message
+			JOIN users AS sender ON sender.id = message.user_id
+			LEFT JOIN users AS receiver ON receiver.id = message.receiver_id
+			WHERE `+strings.Join(conditions, " AND ")+`
+			ORDER BY message.created_at, message.id`, params...); err != nil {
+			return err
+		}
+
+		for {
+			var rows []exportRow
+			if _, err := tx.Query(&rows, `FETCH ? FROM export_messages`, query.Batch); err != nil {
+				return err
+			}
+
+			if len(rows) == 0 {
+				return nil
+			}
+
+			messages := make([]models.Message, len(rows))
+			for i, row := range rows {
+				messages[i] = row.message()
+			}
+
+			if err := fn(messages); err != nil {
+				return err
+			}
+
+			if len(rows) < query.Batch {
+				return nil
+			}
+		}
+	})
+}
+
+// Import inserts messages keeping their creation time, messages with client id
+// their author already used are skipped, number of inserted messages is returned
+func (m *messageRepository) Import(messages []models.Message) (int, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	res, err := m.db.Model(&messages).OnConflict("DO NOTHING").Insert()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// heldBackCondition matches messages archival must not move, they have related
+// rows which are not archived and would be deleted along with them, or are about
+// to be deleted anyway, link previews are only a cache and are unfurled again
+const heldBackCondition = `(held.expires_at IS NOT NULL
+	OR EXISTS (SELECT 1 FROM attachments WHERE attachments.message_id = held.id)
+	OR EXISTS (SELECT 1 FROM polls WHERE polls.message_id = held.id)
+	OR EXISTS (SELECT 1 FROM pins WHERE pins.message_id = held.id)
+	OR EXISTS (SELECT 1 FROM stars WHERE stars.message_id = held.id)
+	OR EXISTS (SELECT 1 FROM reactions WHERE reactions.message_id = held.id)
+	OR EXISTS (SELECT 1 FROM message_mentions WHERE message_mentions.message_id = held.id))`
+
+// Archivable returns messages created before given time which can be archived, oldest first,
+// messages held back stop archival of their conversation so archived messages are always
+// older than those left in the table
+func (m *messageRepository) Archivable(before time.Time, limit int) ([]models.Message, error) {
+	messages := make([]models.Message, 0)
+	if _, err := m.db.Query(&messages, `WITH watermarks AS (
+			SELECT `+strings.Replace(conversationExpr, "message.", "held.", -1)+` AS conversation, min(held.created_at) AS created_at
+			FROM messages AS held
+			WHERE held.created_at < ?0 AND `+heldBackCondition+`
+			GROUP BY 1
+		)
+		SELECT message.* FROM messages AS message
+		LEFT JOIN watermarks ON watermarks.conversation = `+conversationExpr+`
+		WHERE message.created_at < ?0 AND (watermarks.created_at IS NULL OR message.created_at < watermarks.created_at)
+		ORDER BY message.created_at, message.id
+		LIMIT ?1`, before, limit); err != nil {
 		return nil, err
 	}
 
 	return messages, nil
 }
+
+// Count returns number of messages of conversation left in the table
+func (m *messageRepository) Count(conversation string) (int, error) {
+	q, err := inConversation(m.db.Model((*models.Message)(nil)), conversation)
+	if err != nil {
+		return 0, err
+	}
+
+	return q.Count()
+}
+
+// inConversation limits query of messages to conversation the way history indexes are built
+func inConversation(q *orm.Query, conversation string) (*orm.Query, error) {
+	a, b, err := models.ParseConversation(conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	if conversation == models.PublicConversation {
+		return q.Where("message.receiver_id IS NULL"), nil
+	}
+
+	return q.Where("message.receiver_id IS NOT NULL").
+		Where("least(message.user_id, message.receiver_id)=?", a).
+		Where("greatest(message.user_id, message.receiver_id)=?", b), nil
+}
+
+func (r exportRow) message() models.Message {
+	message := models.Message{
+		Id:         r.Id,
+		UserId:     r.UserId,
+		ReceiverId: r.ReceiverId,
+		User:       &models.User{ID: r.UserId, Email: r.SenderEmail, Nickname: r.SenderNickname},
+		Type:       r.Type,
+		Text:       r.Text,
+		Format:     r.Format,
+		CreatedAt:  r.CreatedAt,
+	}
+
+	if r.ReceiverId != 0 {
+		message.Receiver = &models.User{ID: r.ReceiverId, Email: r.ReceiverEmail, Nickname: r.ReceiverNickname}
+	}
+
+	return message
+}
+
+// highlight escapes snippet and turns highlight markers into tags
+func highlight(snippet string) string {
+	return strings.NewReplacer(
+		highlightStart, "<mark>",
+		highlightStop, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
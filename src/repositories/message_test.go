@@ -0,0 +1,406 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastPrivateMessages(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	ts := time.Now().Add(-time.Hour)
+	createMessage(t, db, alice, nil, "public", ts)
+	createMessage(t, db, alice, bob, "alice to bob", ts.Add(time.Minute))
+	createMessage(t, db, bob, alice, "bob to alice", ts.Add(2*time.Minute))
+	createMessage(t, db, bob, alice, "bob to alice again", ts.Add(3*time.Minute))
+	createMessage(t, db, alice, carol, "alice to carol", ts.Add(4*time.Minute))
+	createMessage(t, db, carol, bob, "carol to bob", ts.Add(5*time.Minute))
+
+	expired := createMessage(t, db, bob, alice, "expired", ts.Add(6*time.Minute))
+	expiresAt := time.Now().Add(-time.Minute)
+	expired.ExpiresAt = &expiresAt
+	_, err := db.Model(expired).Column("expires_at").WherePK().Update()
+	require.NoError(t, err)
+
+	repo := NewMessage(db)
+
+	t.Run("Both directions", func(t *testing.T) {
+		messages, err := repo.LastPrivateMessages(*alice, 10, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice to carol", "bob to alice again", "bob to alice", "alice to bob"}, texts(messages))
+		require.Equal(t, "bob@example.com", messages[1].User.Email)
+		require.Equal(t, "alice@example.com", messages[1].Receiver.Email)
+	})
+
+	t.Run("Other participant", func(t *testing.T) {
+		messages, err := repo.LastPrivateMessages(*bob, 10, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"carol to bob", "bob to alice again", "bob to alice", "alice to bob"}, texts(messages))
+	})
+
+	t.Run("Limit per conversation", func(t *testing.T) {
+		messages, err := repo.LastPrivateMessages(*alice, 10, 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice to carol", "bob to alice again", "bob to alice"}, texts(messages))
+	})
+
+	t.Run("Most recent conversations", func(t *testing.T) {
+		messages, err := repo.LastPrivateMessages(*alice, 1, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice to carol"}, texts(messages))
+	})
+
+	t.Run("No conversations", func(t *testing.T) {
+		dave := createUser(t, db, "dave@example.com")
+
+		messages, err := repo.LastPrivateMessages(*dave, 10, 10)
+		require.NoError(t, err)
+		require.Empty(t, messages)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	// Messages sharing creation time are ordered by id
+	ts := time.Now().Add(-time.Hour)
+	first := createMessage(t, db, alice, nil, "first", ts)
+	second := createMessage(t, db, bob, nil, "second", ts)
+	createMessage(t, db, alice, nil, "third", ts)
+	createMessage(t, db, alice, bob, "private", ts)
+	createMessage(t, db, carol, bob, "foreign", ts)
+
+	repo := NewMessage(db)
+
+	t.Run("Latest", func(t *testing.T) {
+		messages, err := repo.History(models.HistoryQuery{Conversation: models.PublicConversation, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"second", "third"}, texts(messages))
+	})
+
+	t.Run("Before", func(t *testing.T) {
+		messages, err := repo.History(models.HistoryQuery{Conversation: models.PublicConversation, Before: second.Id, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"first"}, texts(messages))
+	})
+
+	t.Run("After", func(t *testing.T) {
+		messages, err := repo.History(models.HistoryQuery{Conversation: models.PublicConversation, After: first.Id, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, []string{"second"}, texts(messages))
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		messages, err := repo.History(models.HistoryQuery{Conversation: models.DirectConversation(alice.ID, bob.ID), Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, []string{"private"}, texts(messages))
+	})
+}
+
+func TestMissed(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	ts := time.Now().Add(-time.Hour)
+	seen := createMessage(t, db, alice, nil, "seen", ts)
+	createMessage(t, db, bob, nil, "public", ts.Add(time.Minute))
+	createMessage(t, db, bob, alice, "to alice", ts.Add(2*time.Minute))
+	createMessage(t, db, carol, bob, "foreign", ts.Add(3*time.Minute))
+	createMessage(t, db, alice, carol, "from alice", ts.Add(4*time.Minute))
+
+	repo := NewMessage(db)
+
+	t.Run("Everything", func(t *testing.T) {
+		messages, err := repo.Missed(*alice, seen.Id, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"public", "to alice", "from alice"}, texts(messages))
+	})
+
+	t.Run("Newest only", func(t *testing.T) {
+		messages, err := repo.Missed(*alice, seen.Id, 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"to alice", "from alice"}, texts(messages))
+	})
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+
+	repo := NewMessage(db)
+
+	require.NoError(t, repo.Create(&models.Message{UserId: alice.ID, ClientMsgId: "c1", Text: "first"}))
+	require.Equal(t, ErrDuplicateMessage, repo.Create(&models.Message{UserId: alice.ID, ClientMsgId: "c1", Text: "retry"}))
+
+	// Client ids are unique per user only
+	require.NoError(t, repo.Create(&models.Message{UserId: bob.ID, ClientMsgId: "c1", Text: "other"}))
+
+	message, err := repo.FindByClientID(*alice, "c1")
+	require.NoError(t, err)
+	require.Equal(t, "first", message.Text)
+}
+
+func TestDelivery(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+
+	ts := time.Now().Add(-time.Hour)
+	createMessage(t, db, bob, nil, "public", ts)
+	first := createMessage(t, db, bob, alice, "first", ts.Add(time.Minute))
+	second := createMessage(t, db, bob, alice, "second", ts.Add(2*time.Minute))
+	createMessage(t, db, alice, bob, "reply", ts.Add(3*time.Minute))
+
+	repo := NewMessage(db)
+
+	t.Run("Undelivered", func(t *testing.T) {
+		messages, err := repo.Undelivered(*alice, 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second"}, texts(messages))
+		require.Equal(t, "bob@example.com", messages[0].User.Email)
+	})
+
+	t.Run("Marked once", func(t *testing.T) {
+		delivered, err := repo.MarkDelivered([]int64{first.Id}, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, []int64{first.Id}, delivered)
+
+		delivered, err = repo.MarkDelivered([]int64{first.Id, second.Id}, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, []int64{second.Id}, delivered)
+
+		messages, err := repo.Undelivered(*alice, 10)
+		require.NoError(t, err)
+		require.Empty(t, messages)
+	})
+}
+
+func TestPrunable(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	now := time.Now()
+	old := createMessage(t, db, alice, nil, "old", now.Add(-48*time.Hour))
+	createMessage(t, db, bob, nil, "recent", now.Add(-time.Hour))
+	first := createMessage(t, db, alice, bob, "first", now.Add(-3*time.Hour))
+	createMessage(t, db, bob, alice, "second", now.Add(-2*time.Hour))
+	createMessage(t, db, alice, carol, "only", now.Add(-4*time.Hour))
+
+	repo := NewMessage(db)
+
+	t.Run("Max age", func(t *testing.T) {
+		policy := models.RetentionPolicy{Scope: models.RetentionPublic, MaxAge: 24 * time.Hour}
+
+		ids, err := repo.Prunable(policy, now, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{old.Id}, ids)
+
+		count, err := repo.CountPrunable(policy, now)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("Max count per conversation", func(t *testing.T) {
+		policy := models.RetentionPolicy{Scope: models.RetentionPrivate, MaxCount: 1}
+
+		ids, err := repo.Prunable(policy, now, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{first.Id}, ids)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		policy := models.RetentionPolicy{Scope: models.RetentionPrivate, MaxAge: time.Minute}
+
+		ids, err := repo.Prunable(policy, now, 2)
+		require.NoError(t, err)
+		require.Len(t, ids, 2)
+
+		count, err := repo.CountPrunable(policy, now)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+}
+
+func TestArchivable(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+
+	now := time.Now()
+	createMessage(t, db, alice, nil, "old", now.Add(-72*time.Hour))
+	createMessage(t, db, bob, nil, "older", now.Add(-96*time.Hour))
+	createMessage(t, db, alice, nil, "recent", now.Add(-time.Hour))
+	createMessage(t, db, alice, bob, "first", now.Add(-96*time.Hour))
+	createMessage(t, db, bob, alice, "second", now.Add(-72*time.Hour))
+
+	// Self-destructing message stops archival of its conversation
+	expiring := createMessage(t, db, alice, bob, "expiring", now.Add(-80*time.Hour))
+	expires := now.Add(time.Hour)
+	expiring.ExpiresAt = &expires
+	_, err := db.Model(expiring).Column("expires_at").WherePK().Update()
+	require.NoError(t, err)
+
+	repo := NewMessage(db)
+
+	messages, err := repo.Archivable(now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"older", "first", "old"}, texts(messages))
+
+	// Starred, reacted or mentioning message would lose those rows along with it
+	_, err = db.Exec(`INSERT INTO stars (user_id, message_id) SELECT ?, id FROM messages WHERE text='old'`, bob.ID)
+	require.NoError(t, err)
+
+	messages, err = repo.Archivable(now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"older", "first"}, texts(messages))
+
+	count, err := repo.Count(models.PublicConversation)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	messages, err = repo.Archivable(now.Add(-24*time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+}
+
+func TestHistoryByKey(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+
+	now := time.Now()
+	createMessage(t, db, alice, nil, "one", now.Add(-3*time.Hour))
+	two := createMessage(t, db, alice, nil, "two", now.Add(-2*time.Hour))
+	createMessage(t, db, alice, nil, "three", now.Add(-time.Hour))
+
+	repo := NewMessage(db)
+	key := two.Key()
+
+	messages, err := repo.History(models.HistoryQuery{Conversation: models.PublicConversation, BeforeKey: &key, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one"}, texts(messages))
+
+	messages, err = repo.History(models.HistoryQuery{Conversation: models.PublicConversation, AfterKey: &key, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"three"}, texts(messages))
+}
+
+func TestExport(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	ts := time.Now().Add(-time.Hour)
+	createMessage(t, db, alice, nil, "public", ts)
+	createMessage(t, db, alice, bob, "alice to bob", ts.Add(time.Minute))
+	createMessage(t, db, bob, alice, "bob to alice", ts.Add(2*time.Minute))
+	createMessage(t, db, carol, bob, "carol to bob", ts.Add(3*time.Minute))
+
+	repo := NewMessage(db)
+
+	// export collects exported messages along with sizes of batches
+	export := func(query models.ExportQuery) ([]models.Message, []int) {
+		var (
+			exported []models.Message
+			batches  []int
+		)
+
+		require.NoError(t, repo.Export(query, func(messages []models.Message) error {
+			exported = append(exported, messages...)
+			batches = append(batches, len(messages))
+			return nil
+		}))
+
+		return exported, batches
+	}
+
+	t.Run("Everything", func(t *testing.T) {
+		messages, batches := export(models.ExportQuery{Batch: 3})
+		require.Equal(t, []string{"public", "alice to bob", "bob to alice", "carol to bob"}, texts(messages))
+		require.Equal(t, []int{3, 1}, batches)
+		require.Nil(t, messages[0].Receiver)
+		require.Equal(t, "bob@example.com", messages[2].User.Email)
+		require.Equal(t, "alice@example.com", messages[2].Receiver.Email)
+	})
+
+	t.Run("Conversation", func(t *testing.T) {
+		messages, _ := export(models.ExportQuery{Conversation: models.DirectConversation(alice.ID, bob.ID), Batch: 10})
+		require.Equal(t, []string{"alice to bob", "bob to alice"}, texts(messages))
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		messages, _ := export(models.ExportQuery{Since: ts.Add(time.Minute), Until: ts.Add(3 * time.Minute), Batch: 10})
+		require.Equal(t, []string{"alice to bob", "bob to alice"}, texts(messages))
+	})
+}
+
+func TestImport(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	users := NewUser(db)
+
+	alice := &models.User{Email: "alice@example.com", Nickname: "alice", Password: "!", PasswordReset: true}
+	created, err := users.Import(alice)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	again := &models.User{Email: "alice@example.com", Password: "!", PasswordReset: true}
+	created, err = users.Import(again)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, alice.ID, again.ID)
+	require.Equal(t, "alice", again.Nickname)
+
+	repo := NewMessage(db)
+	ts := time.Now().Add(-24 * time.Hour).Truncate(time.Microsecond)
+	messages := []models.Message{
+		{ClientMsgId: "slack:C1:1", UserId: alice.ID, Type: models.MessageText, Text: "first", Format: models.FormatPlain, CreatedAt: ts, UpdatedAt: ts},
+		{ClientMsgId: "slack:C1:2", UserId: alice.ID, Type: models.MessageText, Text: "second", Format: models.FormatPlain, CreatedAt: ts, UpdatedAt: ts},
+	}
+
+	n, err := repo.Import(messages)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	n, err = repo.Import(messages)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	history, err := repo.History(models.HistoryQuery{Conversation: models.PublicConversation, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, texts(history))
+	require.True(t, history[0].CreatedAt.Equal(ts))
+}
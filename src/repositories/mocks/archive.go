@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/archive.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+	time "time"
+)
+
+// MockArchive is a mock of Archive interface
+type MockArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveMockRecorder
+}
+
+// MockArchiveMockRecorder is the mock recorder for MockArchive
+type MockArchiveMockRecorder struct {
+	mock *MockArchive
+}
+
+// NewMockArchive creates a new mock instance
+func NewMockArchive(ctrl *gomock.Controller) *MockArchive {
+	mock := &MockArchive{ctrl: ctrl}
+	mock.recorder = &MockArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockArchive) EXPECT() *MockArchiveMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockArchive) Create(segment *models.ArchiveSegment, ids []int64) (bool, error) {
+	ret := m.ctrl.Call(m, "Create", segment, ids)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockArchiveMockRecorder) Create(segment, ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArchive)(nil).Create), segment, ids)
+}
+
+// Segments mocks base method
+func (m *MockArchive) Segments(conversation string, from, to time.Time) ([]models.ArchiveSegment, error) {
+	ret := m.ctrl.Call(m, "Segments", conversation, from, to)
+	ret0, _ := ret[0].([]models.ArchiveSegment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Segments indicates an expected call of Segments
+func (mr *MockArchiveMockRecorder) Segments(conversation, from, to interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Segments", reflect.TypeOf((*MockArchive)(nil).Segments), conversation, from, to)
+}
+
+// Containing mocks base method
+func (m *MockArchive) Containing(conversation string, id int64) ([]models.ArchiveSegment, error) {
+	ret := m.ctrl.Call(m, "Containing", conversation, id)
+	ret0, _ := ret[0].([]models.ArchiveSegment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Containing indicates an expected call of Containing
+func (mr *MockArchiveMockRecorder) Containing(conversation, id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Containing", reflect.TypeOf((*MockArchive)(nil).Containing), conversation, id)
+}
+
+// Prunable mocks base method
+func (m *MockArchive) Prunable(scope string) ([]models.ArchiveSegment, error) {
+	ret := m.ctrl.Call(m, "Prunable", scope)
+	ret0, _ := ret[0].([]models.ArchiveSegment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prunable indicates an expected call of Prunable
+func (mr *MockArchiveMockRecorder) Prunable(scope interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prunable", reflect.TypeOf((*MockArchive)(nil).Prunable), scope)
+}
+
+// Replace mocks base method
+func (m *MockArchive) Replace(key string, segment *models.ArchiveSegment) (bool, error) {
+	ret := m.ctrl.Call(m, "Replace", key, segment)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockArchiveMockRecorder) Replace(key, segment interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockArchive)(nil).Replace), key, segment)
+}
+
+// Delete mocks base method
+func (m *MockArchive) Delete(segment models.ArchiveSegment) (bool, error) {
+	ret := m.ctrl.Call(m, "Delete", segment)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockArchiveMockRecorder) Delete(segment interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArchive)(nil).Delete), segment)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/attachment.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockAttachment is a mock of Attachment interface
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockAttachment) Create(attachment *models.Attachment) error {
+	ret := m.ctrl.Call(m, "Create", attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockAttachmentMockRecorder) Create(attachment interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachment)(nil).Create), attachment)
+}
+
+// Find mocks base method
+func (m *MockAttachment) Find(id int64) (*models.Attachment, error) {
+	ret := m.ctrl.Call(m, "Find", id)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockAttachmentMockRecorder) Find(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAttachment)(nil).Find), id)
+}
+
+// Unlinked mocks base method
+func (m *MockAttachment) Unlinked(user models.User, ids []int64) ([]models.Attachment, error) {
+	ret := m.ctrl.Call(m, "Unlinked", user, ids)
+	ret0, _ := ret[0].([]models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlinked indicates an expected call of Unlinked
+func (mr *MockAttachmentMockRecorder) Unlinked(user, ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlinked", reflect.TypeOf((*MockAttachment)(nil).Unlinked), user, ids)
+}
+
+// ForMessages mocks base method
+func (m *MockAttachment) ForMessages(messageIDs []int64) (map[int64][]models.Attachment, error) {
+	ret := m.ctrl.Call(m, "ForMessages", messageIDs)
+	ret0, _ := ret[0].(map[int64][]models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForMessages indicates an expected call of ForMessages
+func (mr *MockAttachmentMockRecorder) ForMessages(messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForMessages", reflect.TypeOf((*MockAttachment)(nil).ForMessages), messageIDs)
+}
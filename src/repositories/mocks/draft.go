@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/draft.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockDraft is a mock of Draft interface
+type MockDraft struct {
+	ctrl     *gomock.Controller
+	recorder *MockDraftMockRecorder
+}
+
+// MockDraftMockRecorder is the mock recorder for MockDraft
+type MockDraftMockRecorder struct {
+	mock *MockDraft
+}
+
+// NewMockDraft creates a new mock instance
+func NewMockDraft(ctrl *gomock.Controller) *MockDraft {
+	mock := &MockDraft{ctrl: ctrl}
+	mock.recorder = &MockDraftMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDraft) EXPECT() *MockDraftMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method
+func (m *MockDraft) Save(draft *models.Draft) error {
+	ret := m.ctrl.Call(m, "Save", draft)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockDraftMockRecorder) Save(draft interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDraft)(nil).Save), draft)
+}
+
+// Find mocks base method
+func (m *MockDraft) Find(userID int64, conversation string) (*models.Draft, error) {
+	ret := m.ctrl.Call(m, "Find", userID, conversation)
+	ret0, _ := ret[0].(*models.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockDraftMockRecorder) Find(userID, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDraft)(nil).Find), userID, conversation)
+}
+
+// Delete mocks base method
+func (m *MockDraft) Delete(userID int64, conversation string) (bool, error) {
+	ret := m.ctrl.Call(m, "Delete", userID, conversation)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockDraftMockRecorder) Delete(userID, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDraft)(nil).Delete), userID, conversation)
+}
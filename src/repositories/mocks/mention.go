@@ -0,0 +1,60 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/mention.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockMention is a mock of Mention interface
+type MockMention struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionMockRecorder
+}
+
+// MockMentionMockRecorder is the mock recorder for MockMention
+type MockMentionMockRecorder struct {
+	mock *MockMention
+}
+
+// NewMockMention creates a new mock instance
+func NewMockMention(ctrl *gomock.Controller) *MockMention {
+	mock := &MockMention{ctrl: ctrl}
+	mock.recorder = &MockMentionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMention) EXPECT() *MockMentionMockRecorder {
+	return m.recorder
+}
+
+// ForMessages mocks base method
+func (m *MockMention) ForMessages(messageIDs []int64) (map[int64][]models.Mention, error) {
+	ret := m.ctrl.Call(m, "ForMessages", messageIDs)
+	ret0, _ := ret[0].(map[int64][]models.Mention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForMessages indicates an expected call of ForMessages
+func (mr *MockMentionMockRecorder) ForMessages(messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForMessages", reflect.TypeOf((*MockMention)(nil).ForMessages), messageIDs)
+}
+
+// ForUser mocks base method
+func (m *MockMention) ForUser(user models.User, limit int) ([]models.Mention, error) {
+	ret := m.ctrl.Call(m, "ForUser", user, limit)
+	ret0, _ := ret[0].([]models.Mention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForUser indicates an expected call of ForUser
+func (mr *MockMentionMockRecorder) ForUser(user, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForUser", reflect.TypeOf((*MockMention)(nil).ForUser), user, limit)
+}
@@ -8,6 +8,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/playneta/go-sessions/src/models"
 	reflect "reflect"
+	time "time"
 )
 
 // MockMessage is a mock of Message interface
@@ -45,6 +46,32 @@ func (mr *MockMessageMockRecorder) Create(message interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessage)(nil).Create), message)
 }
 
+// Find mocks base method
+func (m *MockMessage) Find(id int64) (*models.Message, error) {
+	ret := m.ctrl.Call(m, "Find", id)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockMessageMockRecorder) Find(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockMessage)(nil).Find), id)
+}
+
+// FindByClientID mocks base method
+func (m *MockMessage) FindByClientID(user models.User, clientMsgID string) (*models.Message, error) {
+	ret := m.ctrl.Call(m, "FindByClientID", user, clientMsgID)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByClientID indicates an expected call of FindByClientID
+func (mr *MockMessageMockRecorder) FindByClientID(user, clientMsgID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByClientID", reflect.TypeOf((*MockMessage)(nil).FindByClientID), user, clientMsgID)
+}
+
 // LastPublicMessages mocks base method
 func (m *MockMessage) LastPublicMessages(limit int) ([]models.Message, error) {
 	ret := m.ctrl.Call(m, "LastPublicMessages", limit)
@@ -59,14 +86,182 @@ func (mr *MockMessageMockRecorder) LastPublicMessages(limit interface{}) *gomock
 }
 
 // LastPrivateMessages mocks base method
-func (m *MockMessage) LastPrivateMessages(user models.User, limit int) ([]models.Message, error) {
-	ret := m.ctrl.Call(m, "LastPrivateMessages", user, limit)
+func (m *MockMessage) LastPrivateMessages(user models.User, conversations, limit int) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "LastPrivateMessages", user, conversations, limit)
 	ret0, _ := ret[0].([]models.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LastPrivateMessages indicates an expected call of LastPrivateMessages
-func (mr *MockMessageMockRecorder) LastPrivateMessages(user, limit interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastPrivateMessages", reflect.TypeOf((*MockMessage)(nil).LastPrivateMessages), user, limit)
+func (mr *MockMessageMockRecorder) LastPrivateMessages(user, conversations, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastPrivateMessages", reflect.TypeOf((*MockMessage)(nil).LastPrivateMessages), user, conversations, limit)
+}
+
+// History mocks base method
+func (m *MockMessage) History(query models.HistoryQuery) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "History", query)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History
+func (mr *MockMessageMockRecorder) History(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockMessage)(nil).History), query)
+}
+
+// Missed mocks base method
+func (m *MockMessage) Missed(user models.User, lastID int64, limit int) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Missed", user, lastID, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Missed indicates an expected call of Missed
+func (mr *MockMessageMockRecorder) Missed(user, lastID, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Missed", reflect.TypeOf((*MockMessage)(nil).Missed), user, lastID, limit)
+}
+
+// Undelivered mocks base method
+func (m *MockMessage) Undelivered(user models.User, limit int) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Undelivered", user, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelivered indicates an expected call of Undelivered
+func (mr *MockMessageMockRecorder) Undelivered(user, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelivered", reflect.TypeOf((*MockMessage)(nil).Undelivered), user, limit)
+}
+
+// MarkDelivered mocks base method
+func (m *MockMessage) MarkDelivered(ids []int64, at time.Time) ([]int64, error) {
+	ret := m.ctrl.Call(m, "MarkDelivered", ids, at)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered
+func (mr *MockMessageMockRecorder) MarkDelivered(ids, at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockMessage)(nil).MarkDelivered), ids, at)
+}
+
+// Search mocks base method
+func (m *MockMessage) Search(user models.User, query models.SearchQuery) ([]models.SearchResult, error) {
+	ret := m.ctrl.Call(m, "Search", user, query)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockMessageMockRecorder) Search(user, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockMessage)(nil).Search), user, query)
+}
+
+// Expired mocks base method
+func (m *MockMessage) Expired(now time.Time, limit int) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Expired", now, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expired indicates an expected call of Expired
+func (mr *MockMessageMockRecorder) Expired(now, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expired", reflect.TypeOf((*MockMessage)(nil).Expired), now, limit)
+}
+
+// Prunable mocks base method
+func (m *MockMessage) Prunable(policy models.RetentionPolicy, now time.Time, limit int) ([]int64, error) {
+	ret := m.ctrl.Call(m, "Prunable", policy, now, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prunable indicates an expected call of Prunable
+func (mr *MockMessageMockRecorder) Prunable(policy, now, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prunable", reflect.TypeOf((*MockMessage)(nil).Prunable), policy, now, limit)
+}
+
+// CountPrunable mocks base method
+func (m *MockMessage) CountPrunable(policy models.RetentionPolicy, now time.Time) (int, error) {
+	ret := m.ctrl.Call(m, "CountPrunable", policy, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPrunable indicates an expected call of CountPrunable
+func (mr *MockMessageMockRecorder) CountPrunable(policy, now interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPrunable", reflect.TypeOf((*MockMessage)(nil).CountPrunable), policy, now)
+}
+
+// Delete mocks base method
+func (m *MockMessage) Delete(ids []int64) ([]int64, error) {
+	ret := m.ctrl.Call(m, "Delete", ids)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockMessageMockRecorder) Delete(ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMessage)(nil).Delete), ids)
+}
+
+// Export mocks base method
+func (m *MockMessage) Export(query models.ExportQuery, fn func([]models.Message) error) error {
+	ret := m.ctrl.Call(m, "Export", query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export
+func (mr *MockMessageMockRecorder) Export(query, fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockMessage)(nil).Export), query, fn)
+}
+
+// Import mocks base method
+func (m *MockMessage) Import(messages []models.Message) (int, error) {
+	ret := m.ctrl.Call(m, "Import", messages)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *MockMessageMockRecorder) Import(messages interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockMessage)(nil).Import), messages)
+}
+
+// Archivable mocks base method
+func (m *MockMessage) Archivable(before time.Time, limit int) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Archivable", before, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archivable indicates an expected call of Archivable
+func (mr *MockMessageMockRecorder) Archivable(before, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archivable", reflect.TypeOf((*MockMessage)(nil).Archivable), before, limit)
+}
+
+// Count mocks base method
+func (m *MockMessage) Count(conversation string) (int, error) {
+	ret := m.ctrl.Call(m, "Count", conversation)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockMessageMockRecorder) Count(conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockMessage)(nil).Count), conversation)
 }
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/pin.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockPin is a mock of Pin interface
+type MockPin struct {
+	ctrl     *gomock.Controller
+	recorder *MockPinMockRecorder
+}
+
+// MockPinMockRecorder is the mock recorder for MockPin
+type MockPinMockRecorder struct {
+	mock *MockPin
+}
+
+// NewMockPin creates a new mock instance
+func NewMockPin(ctrl *gomock.Controller) *MockPin {
+	mock := &MockPin{ctrl: ctrl}
+	mock.recorder = &MockPinMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPin) EXPECT() *MockPinMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPin) Create(pin *models.Pin, max int) (bool, error) {
+	ret := m.ctrl.Call(m, "Create", pin, max)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPinMockRecorder) Create(pin, max interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPin)(nil).Create), pin, max)
+}
+
+// Delete mocks base method
+func (m *MockPin) Delete(pin *models.Pin) error {
+	ret := m.ctrl.Call(m, "Delete", pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockPinMockRecorder) Delete(pin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPin)(nil).Delete), pin)
+}
+
+// Find mocks base method
+func (m *MockPin) Find(messageID int64) (*models.Pin, error) {
+	ret := m.ctrl.Call(m, "Find", messageID)
+	ret0, _ := ret[0].(*models.Pin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockPinMockRecorder) Find(messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPin)(nil).Find), messageID)
+}
+
+// ForConversation mocks base method
+func (m *MockPin) ForConversation(conversation string) ([]models.Pin, error) {
+	ret := m.ctrl.Call(m, "ForConversation", conversation)
+	ret0, _ := ret[0].([]models.Pin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForConversation indicates an expected call of ForConversation
+func (mr *MockPinMockRecorder) ForConversation(conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForConversation", reflect.TypeOf((*MockPin)(nil).ForConversation), conversation)
+}
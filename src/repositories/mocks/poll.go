@@ -0,0 +1,59 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/poll.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockPoll is a mock of Poll interface
+type MockPoll struct {
+	ctrl     *gomock.Controller
+	recorder *MockPollMockRecorder
+}
+
+// MockPollMockRecorder is the mock recorder for MockPoll
+type MockPollMockRecorder struct {
+	mock *MockPoll
+}
+
+// NewMockPoll creates a new mock instance
+func NewMockPoll(ctrl *gomock.Controller) *MockPoll {
+	mock := &MockPoll{ctrl: ctrl}
+	mock.recorder = &MockPollMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPoll) EXPECT() *MockPollMockRecorder {
+	return m.recorder
+}
+
+// ForMessages mocks base method
+func (m *MockPoll) ForMessages(user models.User, messageIDs []int64) (map[int64]*models.Poll, error) {
+	ret := m.ctrl.Call(m, "ForMessages", user, messageIDs)
+	ret0, _ := ret[0].(map[int64]*models.Poll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForMessages indicates an expected call of ForMessages
+func (mr *MockPollMockRecorder) ForMessages(user, messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForMessages", reflect.TypeOf((*MockPoll)(nil).ForMessages), user, messageIDs)
+}
+
+// Vote mocks base method
+func (m *MockPoll) Vote(vote models.PollVote, optionIDs []int64) error {
+	ret := m.ctrl.Call(m, "Vote", vote, optionIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Vote indicates an expected call of Vote
+func (mr *MockPollMockRecorder) Vote(vote, optionIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockPoll)(nil).Vote), vote, optionIDs)
+}
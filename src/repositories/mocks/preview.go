@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/preview.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockPreview is a mock of Preview interface
+type MockPreview struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreviewMockRecorder
+}
+
+// MockPreviewMockRecorder is the mock recorder for MockPreview
+type MockPreviewMockRecorder struct {
+	mock *MockPreview
+}
+
+// NewMockPreview creates a new mock instance
+func NewMockPreview(ctrl *gomock.Controller) *MockPreview {
+	mock := &MockPreview{ctrl: ctrl}
+	mock.recorder = &MockPreviewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPreview) EXPECT() *MockPreviewMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method
+func (m *MockPreview) Find(url string) (*models.Preview, error) {
+	ret := m.ctrl.Call(m, "Find", url)
+	ret0, _ := ret[0].(*models.Preview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockPreviewMockRecorder) Find(url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPreview)(nil).Find), url)
+}
+
+// Save mocks base method
+func (m *MockPreview) Save(preview *models.Preview) error {
+	ret := m.ctrl.Call(m, "Save", preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockPreviewMockRecorder) Save(preview interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPreview)(nil).Save), preview)
+}
+
+// Link mocks base method
+func (m *MockPreview) Link(messageID int64, urls []string) error {
+	ret := m.ctrl.Call(m, "Link", messageID, urls)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link
+func (mr *MockPreviewMockRecorder) Link(messageID, urls interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockPreview)(nil).Link), messageID, urls)
+}
+
+// ForMessages mocks base method
+func (m *MockPreview) ForMessages(messageIDs []int64) (map[int64][]models.Preview, error) {
+	ret := m.ctrl.Call(m, "ForMessages", messageIDs)
+	ret0, _ := ret[0].(map[int64][]models.Preview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForMessages indicates an expected call of ForMessages
+func (mr *MockPreviewMockRecorder) ForMessages(messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForMessages", reflect.TypeOf((*MockPreview)(nil).ForMessages), messageIDs)
+}
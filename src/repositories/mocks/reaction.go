@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/reaction.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockReaction is a mock of Reaction interface
+type MockReaction struct {
+	ctrl     *gomock.Controller
+	recorder *MockReactionMockRecorder
+}
+
+// MockReactionMockRecorder is the mock recorder for MockReaction
+type MockReactionMockRecorder struct {
+	mock *MockReaction
+}
+
+// NewMockReaction creates a new mock instance
+func NewMockReaction(ctrl *gomock.Controller) *MockReaction {
+	mock := &MockReaction{ctrl: ctrl}
+	mock.recorder = &MockReactionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReaction) EXPECT() *MockReactionMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockReaction) Create(reaction *models.Reaction) (bool, error) {
+	ret := m.ctrl.Call(m, "Create", reaction)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockReactionMockRecorder) Create(reaction interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReaction)(nil).Create), reaction)
+}
+
+// Delete mocks base method
+func (m *MockReaction) Delete(reaction *models.Reaction) (bool, error) {
+	ret := m.ctrl.Call(m, "Delete", reaction)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockReactionMockRecorder) Delete(reaction interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReaction)(nil).Delete), reaction)
+}
+
+// Summaries mocks base method
+func (m *MockReaction) Summaries(user models.User, messageIDs []int64) (map[int64][]models.ReactionSummary, error) {
+	ret := m.ctrl.Call(m, "Summaries", user, messageIDs)
+	ret0, _ := ret[0].(map[int64][]models.ReactionSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summaries indicates an expected call of Summaries
+func (mr *MockReactionMockRecorder) Summaries(user, messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summaries", reflect.TypeOf((*MockReaction)(nil).Summaries), user, messageIDs)
+}
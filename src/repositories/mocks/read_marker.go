@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/read_marker.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockReadMarker is a mock of ReadMarker interface
+type MockReadMarker struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadMarkerMockRecorder
+}
+
+// MockReadMarkerMockRecorder is the mock recorder for MockReadMarker
+type MockReadMarkerMockRecorder struct {
+	mock *MockReadMarker
+}
+
+// NewMockReadMarker creates a new mock instance
+func NewMockReadMarker(ctrl *gomock.Controller) *MockReadMarker {
+	mock := &MockReadMarker{ctrl: ctrl}
+	mock.recorder = &MockReadMarkerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReadMarker) EXPECT() *MockReadMarkerMockRecorder {
+	return m.recorder
+}
+
+// Mark mocks base method
+func (m *MockReadMarker) Mark(marker *models.ReadMarker) (bool, error) {
+	ret := m.ctrl.Call(m, "Mark", marker)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mark indicates an expected call of Mark
+func (mr *MockReadMarkerMockRecorder) Mark(marker interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mark", reflect.TypeOf((*MockReadMarker)(nil).Mark), marker)
+}
+
+// Find mocks base method
+func (m *MockReadMarker) Find(userID int64, conversation string) (*models.ReadMarker, error) {
+	ret := m.ctrl.Call(m, "Find", userID, conversation)
+	ret0, _ := ret[0].(*models.ReadMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockReadMarkerMockRecorder) Find(userID, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockReadMarker)(nil).Find), userID, conversation)
+}
+
+// ForUser mocks base method
+func (m *MockReadMarker) ForUser(user models.User) ([]models.ReadMarker, error) {
+	ret := m.ctrl.Call(m, "ForUser", user)
+	ret0, _ := ret[0].([]models.ReadMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForUser indicates an expected call of ForUser
+func (mr *MockReadMarkerMockRecorder) ForUser(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForUser", reflect.TypeOf((*MockReadMarker)(nil).ForUser), user)
+}
+
+// Unread mocks base method
+func (m *MockReadMarker) Unread(user models.User) (map[string]int, error) {
+	ret := m.ctrl.Call(m, "Unread", user)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unread indicates an expected call of Unread
+func (mr *MockReadMarkerMockRecorder) Unread(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unread", reflect.TypeOf((*MockReadMarker)(nil).Unread), user)
+}
+
+// UnreadIn mocks base method
+func (m *MockReadMarker) UnreadIn(user models.User, conversation string, after int64) (int, error) {
+	ret := m.ctrl.Call(m, "UnreadIn", user, conversation, after)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreadIn indicates an expected call of UnreadIn
+func (mr *MockReadMarkerMockRecorder) UnreadIn(user, conversation, after interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreadIn", reflect.TypeOf((*MockReadMarker)(nil).UnreadIn), user, conversation, after)
+}
+
+// Partners mocks base method
+func (m *MockReadMarker) Partners(user models.User, conversations []string) (map[string]int64, error) {
+	ret := m.ctrl.Call(m, "Partners", user, conversations)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Partners indicates an expected call of Partners
+func (mr *MockReadMarkerMockRecorder) Partners(user, conversations interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Partners", reflect.TypeOf((*MockReadMarker)(nil).Partners), user, conversations)
+}
@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/scheduled_message.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+	time "time"
+)
+
+// MockScheduledMessage is a mock of ScheduledMessage interface
+type MockScheduledMessage struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledMessageMockRecorder
+}
+
+// MockScheduledMessageMockRecorder is the mock recorder for MockScheduledMessage
+type MockScheduledMessageMockRecorder struct {
+	mock *MockScheduledMessage
+}
+
+// NewMockScheduledMessage creates a new mock instance
+func NewMockScheduledMessage(ctrl *gomock.Controller) *MockScheduledMessage {
+	mock := &MockScheduledMessage{ctrl: ctrl}
+	mock.recorder = &MockScheduledMessageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScheduledMessage) EXPECT() *MockScheduledMessageMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockScheduledMessage) Create(message *models.ScheduledMessage) error {
+	ret := m.ctrl.Call(m, "Create", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockScheduledMessageMockRecorder) Create(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledMessage)(nil).Create), message)
+}
+
+// Find mocks base method
+func (m *MockScheduledMessage) Find(id int64) (*models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "Find", id)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockScheduledMessageMockRecorder) Find(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScheduledMessage)(nil).Find), id)
+}
+
+// Pending mocks base method
+func (m *MockScheduledMessage) Pending(user models.User) ([]models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "Pending", user)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending
+func (mr *MockScheduledMessageMockRecorder) Pending(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockScheduledMessage)(nil).Pending), user)
+}
+
+// CountPending mocks base method
+func (m *MockScheduledMessage) CountPending(user models.User) (int, error) {
+	ret := m.ctrl.Call(m, "CountPending", user)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPending indicates an expected call of CountPending
+func (mr *MockScheduledMessageMockRecorder) CountPending(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPending", reflect.TypeOf((*MockScheduledMessage)(nil).CountPending), user)
+}
+
+// Update mocks base method
+func (m *MockScheduledMessage) Update(message *models.ScheduledMessage, now time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "Update", message, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockScheduledMessageMockRecorder) Update(message, now interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledMessage)(nil).Update), message, now)
+}
+
+// Delete mocks base method
+func (m *MockScheduledMessage) Delete(message *models.ScheduledMessage, now time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "Delete", message, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockScheduledMessageMockRecorder) Delete(message, now interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduledMessage)(nil).Delete), message, now)
+}
+
+// Claim mocks base method
+func (m *MockScheduledMessage) Claim(now time.Time, lease time.Duration, limit int) ([]models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "Claim", now, lease, limit)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim
+func (mr *MockScheduledMessageMockRecorder) Claim(now, lease, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockScheduledMessage)(nil).Claim), now, lease, limit)
+}
+
+// Finish mocks base method
+func (m *MockScheduledMessage) Finish(message *models.ScheduledMessage) error {
+	ret := m.ctrl.Call(m, "Finish", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish
+func (mr *MockScheduledMessageMockRecorder) Finish(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockScheduledMessage)(nil).Finish), message)
+}
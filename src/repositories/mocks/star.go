@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/repositories/star.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockStar is a mock of Star interface
+type MockStar struct {
+	ctrl     *gomock.Controller
+	recorder *MockStarMockRecorder
+}
+
+// MockStarMockRecorder is the mock recorder for MockStar
+type MockStarMockRecorder struct {
+	mock *MockStar
+}
+
+// NewMockStar creates a new mock instance
+func NewMockStar(ctrl *gomock.Controller) *MockStar {
+	mock := &MockStar{ctrl: ctrl}
+	mock.recorder = &MockStarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStar) EXPECT() *MockStarMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockStar) Create(star *models.Star) (bool, error) {
+	ret := m.ctrl.Call(m, "Create", star)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockStarMockRecorder) Create(star interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStar)(nil).Create), star)
+}
+
+// Delete mocks base method
+func (m *MockStar) Delete(user models.User, messageID int64) (bool, error) {
+	ret := m.ctrl.Call(m, "Delete", user, messageID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockStarMockRecorder) Delete(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStar)(nil).Delete), user, messageID)
+}
+
+// ForUser mocks base method
+func (m *MockStar) ForUser(user models.User, cursor int64, limit int) ([]models.Star, error) {
+	ret := m.ctrl.Call(m, "ForUser", user, cursor, limit)
+	ret0, _ := ret[0].([]models.Star)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForUser indicates an expected call of ForUser
+func (mr *MockStarMockRecorder) ForUser(user, cursor, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForUser", reflect.TypeOf((*MockStar)(nil).ForUser), user, cursor, limit)
+}
+
+// Starred mocks base method
+func (m *MockStar) Starred(user models.User, messageIDs []int64) (map[int64]bool, error) {
+	ret := m.ctrl.Call(m, "Starred", user, messageIDs)
+	ret0, _ := ret[0].(map[int64]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Starred indicates an expected call of Starred
+func (mr *MockStarMockRecorder) Starred(user, messageIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Starred", reflect.TypeOf((*MockStar)(nil).Starred), user, messageIDs)
+}
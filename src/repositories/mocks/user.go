@@ -8,6 +8,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/playneta/go-sessions/src/models"
 	reflect "reflect"
+	time "time"
 )
 
 // MockUser is a mock of User interface
@@ -46,6 +47,19 @@ func (mr *MockUserMockRecorder) Create(email, password interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), email, password)
 }
 
+// Import mocks base method
+func (m *MockUser) Import(user *models.User) (bool, error) {
+	ret := m.ctrl.Call(m, "Import", user)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *MockUserMockRecorder) Import(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockUser)(nil).Import), user)
+}
+
 // FindByEmail mocks base method
 func (m *MockUser) FindByEmail(email string) (*models.User, error) {
 	ret := m.ctrl.Call(m, "FindByEmail", email)
@@ -72,6 +86,32 @@ func (mr *MockUserMockRecorder) FindByToken(token interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByToken", reflect.TypeOf((*MockUser)(nil).FindByToken), token)
 }
 
+// FindByNickname mocks base method
+func (m *MockUser) FindByNickname(nickname string) (*models.User, error) {
+	ret := m.ctrl.Call(m, "FindByNickname", nickname)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNickname indicates an expected call of FindByNickname
+func (mr *MockUserMockRecorder) FindByNickname(nickname interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNickname", reflect.TypeOf((*MockUser)(nil).FindByNickname), nickname)
+}
+
+// FindByIDs mocks base method
+func (m *MockUser) FindByIDs(ids []int64) ([]models.User, error) {
+	ret := m.ctrl.Call(m, "FindByIDs", ids)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs
+func (mr *MockUserMockRecorder) FindByIDs(ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockUser)(nil).FindByIDs), ids)
+}
+
 // UpdateToken mocks base method
 func (m *MockUser) UpdateToken(user *models.User, token string) error {
 	ret := m.ctrl.Call(m, "UpdateToken", user, token)
@@ -83,3 +123,52 @@ func (m *MockUser) UpdateToken(user *models.User, token string) error {
 func (mr *MockUserMockRecorder) UpdateToken(user, token interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToken", reflect.TypeOf((*MockUser)(nil).UpdateToken), user, token)
 }
+
+// UpdateNickname mocks base method
+func (m *MockUser) UpdateNickname(user *models.User, nickname string) error {
+	ret := m.ctrl.Call(m, "UpdateNickname", user, nickname)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname
+func (mr *MockUserMockRecorder) UpdateNickname(user, nickname interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockUser)(nil).UpdateNickname), user, nickname)
+}
+
+// SetResetToken mocks base method
+func (m *MockUser) SetResetToken(user *models.User, tokenHash string, expiresAt time.Time) error {
+	ret := m.ctrl.Call(m, "SetResetToken", user, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetResetToken indicates an expected call of SetResetToken
+func (mr *MockUserMockRecorder) SetResetToken(user, tokenHash, expiresAt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResetToken", reflect.TypeOf((*MockUser)(nil).SetResetToken), user, tokenHash, expiresAt)
+}
+
+// FindByResetToken mocks base method
+func (m *MockUser) FindByResetToken(tokenHash string) (*models.User, error) {
+	ret := m.ctrl.Call(m, "FindByResetToken", tokenHash)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByResetToken indicates an expected call of FindByResetToken
+func (mr *MockUserMockRecorder) FindByResetToken(tokenHash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByResetToken", reflect.TypeOf((*MockUser)(nil).FindByResetToken), tokenHash)
+}
+
+// ResetPassword mocks base method
+func (m *MockUser) ResetPassword(user *models.User, hashedPassword string) error {
+	ret := m.ctrl.Call(m, "ResetPassword", user, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword
+func (mr *MockUserMockRecorder) ResetPassword(user, hashedPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUser)(nil).ResetPassword), user, hashedPassword)
+}
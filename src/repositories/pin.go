@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Pin interface {
+		Create(pin *models.Pin, max int) (bool, error)
+		Delete(pin *models.Pin) error
+		Find(messageID int64) (*models.Pin, error)
+		ForConversation(conversation string) ([]models.Pin, error)
+	}
+
+	pinRepository struct {
+		db *pg.DB
+	}
+)
+
+// ErrPinLimit is returned by Create when conversation has maximum number of pins already
+var ErrPinLimit = errors.New("conversation has maximum number of pins")
+
+func NewPin(db *pg.DB) Pin {
+	return &pinRepository{
+		db: db,
+	}
+}
+
+// Create stores pin unless conversation has max pins already and reports whether message
+// was not pinned before, pinned message is not counted against the limit again
+func (p *pinRepository) Create(pin *models.Pin, max int) (bool, error) {
+	created := false
+	err := p.db.RunInTransaction(func(tx *pg.Tx) error {
+		// Pins of conversation are counted under lock so concurrent ones can not exceed the limit
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, "pins:"+pin.Conversation); err != nil {
+			return err
+		}
+
+		pinned, err := tx.Model((*models.Pin)(nil)).Where("message_id=?", pin.MessageId).Exists()
+		if err != nil || pinned {
+			return err
+		}
+
+		count, err := tx.Model((*models.Pin)(nil)).Where("conversation=?", pin.Conversation).Count()
+		if err != nil {
+			return err
+		}
+
+		if count >= max {
+			return ErrPinLimit
+		}
+
+		res, err := tx.Model(pin).OnConflict("DO NOTHING").Insert()
+		if err != nil {
+			return err
+		}
+
+		created = res.RowsAffected() > 0
+		return nil
+	})
+
+	return created, err
+}
+
+func (p *pinRepository) Delete(pin *models.Pin) error {
+	_, err := p.db.Model(pin).WherePK().Delete()
+	return err
+}
+
+func (p *pinRepository) Find(messageID int64) (*models.Pin, error) {
+	var pin models.Pin
+	if err := p.db.Model(&pin).
+		Column("pin.*").
+		Relation("User").
+		Where("pin.message_id=?", messageID).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &pin, nil
+}
+
+func (p *pinRepository) ForConversation(conversation string) ([]models.Pin, error) {
+	var pins []models.Pin
+	if err := p.db.Model(&pins).
+		Column("pin.*").
+		Relation("User").
+		Relation("Message").
+		Relation("Message.User").
+		Relation("Message.Receiver").
+		Where("pin.conversation=?", conversation).
+		Where(notExpiredCondition, time.Now()).
+		Order("pin.id desc").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return pins, nil
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Poll interface {
+		// ForMessages returns polls of messages with tallies, me flags are set for given user
+		ForMessages(user models.User, messageIDs []int64) (map[int64]*models.Poll, error)
+		// Vote replaces votes of user in poll with given options, ErrSingleChoice is
+		// returned for several options in poll that is not multiple choice
+		Vote(vote models.PollVote, optionIDs []int64) error
+	}
+
+	pollRepository struct {
+		db *pg.DB
+	}
+)
+
+// ErrSingleChoice is returned by Vote when poll allows a single option only
+var ErrSingleChoice = errors.New("poll allows a single option")
+
+func NewPoll(db *pg.DB) Poll {
+	return &pollRepository{
+		db: db,
+	}
+}
+
+// createPoll stores poll of message being created inside its transaction
+func createPoll(tx *pg.Tx, message *models.Message) error {
+	message.Poll.MessageId = message.Id
+	if _, err := tx.Model(message.Poll).Insert(); err != nil {
+		return err
+	}
+
+	for i := range message.Poll.Options {
+		message.Poll.Options[i].MessageId = message.Id
+		message.Poll.Options[i].Position = i
+	}
+
+	_, err := tx.Model(&message.Poll.Options).Insert()
+	return err
+}
+
+func (p *pollRepository) ForMessages(user models.User, messageIDs []int64) (map[int64]*models.Poll, error) {
+	polls := make(map[int64]*models.Poll)
+	if len(messageIDs) == 0 {
+		return polls, nil
+	}
+
+	var rows []models.Poll
+	if err := p.db.Model(&rows).
+		Where("message_id IN (?)", pg.In(messageIDs)).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return polls, nil
+	}
+
+	ids := make([]int64, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].MessageId
+		polls[rows[i].MessageId] = &rows[i]
+	}
+
+	var options []models.PollOption
+	if err := p.db.Model(&options).
+		Where("message_id IN (?)", pg.In(ids)).
+		Order("message_id", "position").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	var votes []models.PollVote
+	if err := p.db.Model(&votes).
+		Column("poll_vote.*").
+		Relation("User").
+		Where("poll_vote.message_id IN (?)", pg.In(ids)).
+		Order("poll_vote.created_at").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	type key struct{ message, option int64 }
+	tallies := make(map[key][]models.PollVote)
+	voters := make(map[int64]map[int64]bool)
+	for _, vote := range votes {
+		tallies[key{vote.MessageId, vote.OptionId}] = append(tallies[key{vote.MessageId, vote.OptionId}], vote)
+
+		if voters[vote.MessageId] == nil {
+			voters[vote.MessageId] = make(map[int64]bool)
+		}
+		voters[vote.MessageId][vote.UserId] = true
+	}
+
+	for _, option := range options {
+		poll := polls[option.MessageId]
+
+		for _, vote := range tallies[key{option.MessageId, option.Id}] {
+			option.Votes++
+			option.Me = option.Me || vote.UserId == user.ID
+
+			if !poll.Anonymous && vote.User != nil {
+				option.Voters = append(option.Voters, vote.User.Email)
+			}
+		}
+
+		poll.Options = append(poll.Options, option)
+	}
+
+	for id, poll := range polls {
+		poll.Voters = len(voters[id])
+	}
+
+	return polls, nil
+}
+
+func (p *pollRepository) Vote(vote models.PollVote, optionIDs []int64) error {
+	return p.db.RunInTransaction(func(tx *pg.Tx) error {
+		// Votes of poll are replaced under lock of its row, otherwise concurrent
+		// votes of one user could both survive their deletes
+		var poll models.Poll
+		if err := tx.Model(&poll).
+			Where("message_id=?", vote.MessageId).
+			For("UPDATE").
+			Select(); err != nil {
+			return err
+		}
+
+		if len(optionIDs) > 1 && !poll.Multiple {
+			return ErrSingleChoice
+		}
+
+		if _, err := tx.Model((*models.PollVote)(nil)).
+			Where("message_id=? and user_id=?", vote.MessageId, vote.UserId).
+			Delete(); err != nil {
+			return err
+		}
+
+		if len(optionIDs) == 0 {
+			return nil
+		}
+
+		votes := make([]models.PollVote, len(optionIDs))
+		for i, id := range optionIDs {
+			votes[i] = vote
+			votes[i].OptionId = id
+		}
+
+		_, err := tx.Model(&votes).Insert()
+		return err
+	})
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollVote(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	message := createMessage(t, db, alice, nil, "Lunch?", time.Now())
+	message.Poll = &models.Poll{
+		CreatedAt: time.Now(),
+		Options:   []models.PollOption{{Text: "pizza"}, {Text: "sushi"}},
+	}
+	require.NoError(t, db.RunInTransaction(func(tx *pg.Tx) error {
+		return createPoll(tx, message)
+	}))
+
+	repo := NewPoll(db)
+	vote := models.PollVote{MessageId: message.Id, UserId: alice.ID, CreatedAt: time.Now()}
+	pizza, sushi := message.Poll.Options[0].Id, message.Poll.Options[1].Id
+
+	require.Equal(t, ErrSingleChoice, repo.Vote(vote, []int64{pizza, sushi}))
+
+	// concurrent votes of the same user leave a single one
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(option int64) {
+			defer wg.Done()
+			assert.NoError(t, repo.Vote(vote, []int64{option}))
+		}([]int64{pizza, sushi}[i%2])
+	}
+	wg.Wait()
+
+	polls, err := repo.ForMessages(*alice, []int64{message.Id})
+	require.NoError(t, err)
+	require.Equal(t, 1, polls[message.Id].Voters)
+	require.Equal(t, 1, polls[message.Id].Options[0].Votes+polls[message.Id].Options[1].Votes)
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Preview interface {
+		Find(url string) (*models.Preview, error)
+		Save(preview *models.Preview) error
+		Link(messageID int64, urls []string) error
+		ForMessages(messageIDs []int64) (map[int64][]models.Preview, error)
+	}
+
+	previewRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewPreview(db *pg.DB) Preview {
+	return &previewRepository{
+		db: db,
+	}
+}
+
+func (p *previewRepository) Find(url string) (*models.Preview, error) {
+	var preview models.Preview
+	if err := p.db.Model(&preview).Where("url=?", url).First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &preview, nil
+}
+
+// Save stores preview in cache replacing previously fetched one
+func (p *previewRepository) Save(preview *models.Preview) error {
+	_, err := p.db.Model(preview).
+		OnConflict("(url) DO UPDATE").
+		Set("title=EXCLUDED.title, description=EXCLUDED.description, image=EXCLUDED.image").
+		Set("site_name=EXCLUDED.site_name, failed=EXCLUDED.failed, fetched_at=EXCLUDED.fetched_at").
+		Insert()
+	return err
+}
+
+func (p *previewRepository) Link(messageID int64, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	links := make([]models.MessagePreview, len(urls))
+	for i, url := range urls {
+		links[i] = models.MessagePreview{
+			MessageId: messageID,
+			URL:       url,
+			Position:  i,
+		}
+	}
+
+	_, err := p.db.Model(&links).OnConflict("DO NOTHING").Insert()
+	return err
+}
+
+// ForMessages returns successfully fetched previews of messages in order links appear in text
+func (p *previewRepository) ForMessages(messageIDs []int64) (map[int64][]models.Preview, error) {
+	previews := make(map[int64][]models.Preview)
+	if len(messageIDs) == 0 {
+		return previews, nil
+	}
+
+	var links []models.MessagePreview
+	if err := p.db.Model(&links).
+		Where("message_id IN (?)", pg.In(messageIDs)).
+		Order("message_id", "position").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	if len(links) == 0 {
+		return previews, nil
+	}
+
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.URL
+	}
+
+	var rows []models.Preview
+	if err := p.db.Model(&rows).
+		Where("url IN (?) and not failed", pg.In(urls)).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	byURL := make(map[string]models.Preview, len(rows))
+	for _, preview := range rows {
+		byURL[preview.URL] = preview
+	}
+
+	for _, link := range links {
+		if preview, ok := byURL[link.URL]; ok {
+			previews[link.MessageId] = append(previews[link.MessageId], preview)
+		}
+	}
+
+	return previews, nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Reaction interface {
+		Create(reaction *models.Reaction) (bool, error)
+		Delete(reaction *models.Reaction) (bool, error)
+		Summaries(user models.User, messageIDs []int64) (map[int64][]models.ReactionSummary, error)
+	}
+
+	reactionRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewReaction(db *pg.DB) Reaction {
+	return &reactionRepository{
+		db: db,
+	}
+}
+
+// Create stores reaction and reports whether it was new, adding the same
+// emoji twice is not an error
+func (r *reactionRepository) Create(reaction *models.Reaction) (bool, error) {
+	res, err := r.db.Model(reaction).OnConflict("DO NOTHING").Insert()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// Delete removes reaction and reports whether it existed
+func (r *reactionRepository) Delete(reaction *models.Reaction) (bool, error) {
+	res, err := r.db.Model(reaction).
+		Where("message_id=? and user_id=? and emoji=?", reaction.MessageId, reaction.UserId, reaction.Emoji).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// Summaries aggregates reactions per message and emoji from the point of view of user
+func (r *reactionRepository) Summaries(user models.User, messageIDs []int64) (map[int64][]models.ReactionSummary, error) {
+	summaries := make(map[int64][]models.ReactionSummary)
+	if len(messageIDs) == 0 {
+		return summaries, nil
+	}
+
+	var rows []struct {
+		MessageId int64
+		Emoji     string
+		Count     int
+		Me        bool
+	}
+	if err := r.db.Model((*models.Reaction)(nil)).
+		ColumnExpr("message_id, emoji, count(*) AS count, bool_or(user_id=?) AS me", user.ID).
+		Where("message_id IN (?)", pg.In(messageIDs)).
+		Group("message_id", "emoji").
+		OrderExpr("min(created_at)").
+		Select(&rows); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		summaries[row.MessageId] = append(summaries[row.MessageId], models.ReactionSummary{
+			Emoji: row.Emoji,
+			Count: row.Count,
+			Me:    row.Me,
+		})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	ReadMarker interface {
+		Mark(marker *models.ReadMarker) (bool, error)
+		Find(userID int64, conversation string) (*models.ReadMarker, error)
+		ForUser(user models.User) ([]models.ReadMarker, error)
+		Unread(user models.User) (map[string]int, error)
+		UnreadIn(user models.User, conversation string, after int64) (int, error)
+		Partners(user models.User, conversations []string) (map[string]int64, error)
+	}
+
+	readMarkerRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewReadMarker(db *pg.DB) ReadMarker {
+	return &readMarkerRepository{
+		db: db,
+	}
+}
+
+// Mark moves read marker forward and reports whether it moved,
+// markers never go back so late or duplicated events are harmless
+func (r *readMarkerRepository) Mark(marker *models.ReadMarker) (bool, error) {
+	res, err := r.db.Model(marker).
+		OnConflict("(user_id, conversation) DO UPDATE").
+		Set("message_id=EXCLUDED.message_id, updated_at=EXCLUDED.updated_at").
+		Where("read_marker.message_id < EXCLUDED.message_id").
+		Insert()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *readMarkerRepository) Find(userID int64, conversation string) (*models.ReadMarker, error) {
+	var marker models.ReadMarker
+	if err := r.db.Model(&marker).
+		Where("user_id=? and conversation=?", userID, conversation).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &marker, nil
+}
+
+func (r *readMarkerRepository) ForUser(user models.User) ([]models.ReadMarker, error) {
+	var markers []models.ReadMarker
+	if err := r.db.Model(&markers).
+		Where("user_id=?", user.ID).
+		Order("conversation").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return markers, nil
+}
+
+// Unread counts messages from other people after read marker per conversation,
+// conversations without unread messages are omitted
+func (r *readMarkerRepository) Unread(user models.User) (map[string]int, error) {
+	var rows []struct {
+		Conversation string
+		Unread       int
+	}
+	if _, err := r.db.Query(&rows, `
+		SELECT t.conversation, count(*) AS unread
+		FROM (
+			SELECT `+conversationExpr+` AS conversation, message.id
+			FROM messages AS message
+			WHERE message.user_id <> ?0 AND (message.receiver_id IS NULL OR message.receiver_id = ?0)
+				AND (message.expires_at IS NULL OR message.expires_at > ?1)
+		) AS t
+		LEFT JOIN read_markers AS marker ON marker.user_id = ?0 AND marker.conversation = t.conversation
+		WHERE t.id > COALESCE(marker.message_id, 0)
+		GROUP BY t.conversation`, user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	unread := make(map[string]int, len(rows))
+	for _, row := range rows {
+		unread[row.Conversation] = row.Unread
+	}
+
+	return unread, nil
+}
+
+// UnreadIn counts messages from other people after given one in a single conversation
+func (r *readMarkerRepository) UnreadIn(user models.User, conversation string, after int64) (int, error) {
+	q, err := inConversation(r.db.Model((*models.Message)(nil)).
+		Where("message.user_id<>?", user.ID).
+		Where("message.id>?", after).
+		Where(notExpiredCondition, time.Now()), conversation)
+	if err != nil {
+		return 0, err
+	}
+
+	return q.Count()
+}
+
+// Partners returns last message read by the other participant of each private conversation
+func (r *readMarkerRepository) Partners(user models.User, conversations []string) (map[string]int64, error) {
+	read := make(map[string]int64)
+	if len(conversations) == 0 {
+		return read, nil
+	}
+
+	var markers []models.ReadMarker
+	if err := r.db.Model(&markers).
+		Where("conversation IN (?) and user_id<>?", pg.In(conversations), user.ID).
+		Select(); err != nil {
+		return nil, err
+	}
+
+	for _, marker := range markers {
+		read[marker.Conversation] = marker.MessageId
+	}
+
+	return read, nil
+}
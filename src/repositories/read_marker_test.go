@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnreadIn(t *testing.T) {
+	db := testDB(t)
+	defer db.Close()
+
+	alice := createUser(t, db, "alice@example.com")
+	bob := createUser(t, db, "bob@example.com")
+	carol := createUser(t, db, "carol@example.com")
+
+	ts := time.Now().Add(-time.Hour)
+	read := createMessage(t, db, bob, alice, "read", ts)
+	createMessage(t, db, bob, alice, "unread", ts.Add(time.Minute))
+	createMessage(t, db, alice, bob, "own", ts.Add(2*time.Minute))
+	createMessage(t, db, carol, alice, "other conversation", ts.Add(3*time.Minute))
+	createMessage(t, db, bob, nil, "public", ts.Add(4*time.Minute))
+
+	repo := NewReadMarker(db)
+
+	unread, err := repo.UnreadIn(*alice, models.DirectConversation(alice.ID, bob.ID), read.Id)
+	require.NoError(t, err)
+	require.Equal(t, 1, unread)
+
+	unread, err = repo.UnreadIn(*alice, models.PublicConversation, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, unread)
+}
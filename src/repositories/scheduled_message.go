@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	ScheduledMessage interface {
+		Create(message *models.ScheduledMessage) error
+		Find(id int64) (*models.ScheduledMessage, error)
+		Pending(user models.User) ([]models.ScheduledMessage, error)
+		CountPending(user models.User) (int, error)
+		Update(message *models.ScheduledMessage, now time.Time) (bool, error)
+		Delete(message *models.ScheduledMessage, now time.Time) (bool, error)
+		Claim(now time.Time, lease time.Duration, limit int) ([]models.ScheduledMessage, error)
+		Finish(message *models.ScheduledMessage) error
+	}
+
+	scheduledMessageRepository struct {
+		db *pg.DB
+	}
+)
+
+// editableCondition matches pending messages not being delivered right now
+const editableCondition = "status = ? and (claimed_until IS NULL or claimed_until < ?)"
+
+func NewScheduledMessage(db *pg.DB) ScheduledMessage {
+	return &scheduledMessageRepository{
+		db: db,
+	}
+}
+
+func (s *scheduledMessageRepository) Create(message *models.ScheduledMessage) error {
+	if _, err := s.db.Model(message).Insert(); err != nil {
+		return err
+	}
+
+	return s.load(message)
+}
+
+func (s *scheduledMessageRepository) Find(id int64) (*models.ScheduledMessage, error) {
+	message := &models.ScheduledMessage{Id: id}
+	if err := s.load(message); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (s *scheduledMessageRepository) load(message *models.ScheduledMessage) error {
+	return s.db.Model(message).
+		Column("scheduled_message.*").
+		Relation("User").Relation("Receiver").
+		Where("scheduled_message.id=?", message.Id).First()
+}
+
+func (s *scheduledMessageRepository) Pending(user models.User) ([]models.ScheduledMessage, error) {
+	messages := make([]models.ScheduledMessage, 0)
+	if err := s.db.Model(&messages).
+		Column("scheduled_message.*").
+		Relation("Receiver").
+		Where("scheduled_message.user_id=? and scheduled_message.status=?", user.ID, models.ScheduledPending).
+		Order("send_at", "id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (s *scheduledMessageRepository) CountPending(user models.User) (int, error) {
+	return s.db.Model((*models.ScheduledMessage)(nil)).
+		Where("user_id=? and status=?", user.ID, models.ScheduledPending).
+		Count()
+}
+
+// Update changes receiver, text and time of pending message unless dispatcher already took it
+func (s *scheduledMessageRepository) Update(message *models.ScheduledMessage, now time.Time) (bool, error) {
+	res, err := s.db.Model(message).
+		Column("receiver_id", "text", "format", "send_at", "updated_at").
+		WherePK().
+		Where(editableCondition, models.ScheduledPending, now).
+		Update()
+	if err != nil {
+		return false, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, s.load(message)
+}
+
+// Delete cancels pending message unless dispatcher already took it
+func (s *scheduledMessageRepository) Delete(message *models.ScheduledMessage, now time.Time) (bool, error) {
+	res, err := s.db.Model(message).
+		WherePK().
+		Where(editableCondition, models.ScheduledPending, now).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// Claim leases due messages to the caller, rows locked by other instances are skipped
+// and messages of crashed instance are claimed again once their lease expires
+func (s *scheduledMessageRepository) Claim(now time.Time, lease time.Duration, limit int) ([]models.ScheduledMessage, error) {
+	var ids []int64
+	if _, err := s.db.Query(&ids, `
+		UPDATE scheduled_messages SET claimed_until = ?0
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE status = ?1 AND send_at <= ?2 AND (claimed_until IS NULL OR claimed_until < ?2)
+			ORDER BY send_at
+			LIMIT ?3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, now.Add(lease), models.ScheduledPending, now, limit); err != nil {
+		return nil, err
+	}
+
+	messages := make([]models.ScheduledMessage, 0, len(ids))
+	if len(ids) == 0 {
+		return messages, nil
+	}
+
+	if err := s.db.Model(&messages).
+		Column("scheduled_message.*").
+		Relation("User").Relation("Receiver").
+		Where("scheduled_message.id IN (?)", pg.In(ids)).
+		Order("send_at", "id").
+		Select(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Finish stores result of delivery and releases the lease
+func (s *scheduledMessageRepository) Finish(message *models.ScheduledMessage) error {
+	message.ClaimedUntil = time.Time{}
+
+	_, err := s.db.Model(message).
+		Column("status", "message_id", "error", "claimed_until", "updated_at").
+		WherePK().
+		Update()
+	return err
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/playneta/go-sessions/src/models"
+)
+
+type (
+	Star interface {
+		Create(star *models.Star) (bool, error)
+		Delete(user models.User, messageID int64) (bool, error)
+		ForUser(user models.User, cursor int64, limit int) ([]models.Star, error)
+		Starred(user models.User, messageIDs []int64) (map[int64]bool, error)
+	}
+
+	starRepository struct {
+		db *pg.DB
+	}
+)
+
+func NewStar(db *pg.DB) Star {
+	return &starRepository{
+		db: db,
+	}
+}
+
+// Create stores star and reports whether message was not starred before
+func (s *starRepository) Create(star *models.Star) (bool, error) {
+	res, err := s.db.Model(star).OnConflict("DO NOTHING").Insert()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// Delete removes star and reports whether message was starred
+func (s *starRepository) Delete(user models.User, messageID int64) (bool, error) {
+	res, err := s.db.Model((*models.Star)(nil)).
+		Where("user_id=? and message_id=?", user.ID, messageID).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// ForUser returns stars of user newest first, cursor is id of the last
+// star of previous page, zero cursor starts from the newest one
+func (s *starRepository) ForUser(user models.User, cursor int64, limit int) ([]models.Star, error) {
+	var stars []models.Star
+	q := s.db.Model(&stars).
+		Column("star.*").
+		Relation("Message").
+		Relation("Message.User").
+		Relation("Message.Receiver").
+		Where("star.user_id=?", user.ID).
+		Where(notExpiredCondition, time.Now())
+
+	if cursor > 0 {
+		q = q.Where("star.id<?", cursor)
+	}
+
+	if err := q.Order("star.id desc").Limit(limit).Select(); err != nil {
+		return nil, err
+	}
+
+	return stars, nil
+}
+
+// Starred reports which of messages are starred by user
+func (s *starRepository) Starred(user models.User, messageIDs []int64) (map[int64]bool, error) {
+	starred := make(map[int64]bool)
+	if len(messageIDs) == 0 {
+		return starred, nil
+	}
+
+	var ids []int64
+	if err := s.db.Model((*models.Star)(nil)).
+		Column("message_id").
+		Where("user_id=? and message_id IN (?)", user.ID, pg.In(messageIDs)).
+		Select(&ids); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		starred[id] = true
+	}
+
+	return starred, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"time"
 
 	"github.com/go-pg/pg"
@@ -10,9 +11,16 @@ import (
 type (
 	User interface {
 		Create(email, password string) (*models.User, error)
+		Import(user *models.User) (bool, error)
 		FindByEmail(email string) (*models.User, error)
 		FindByToken(token string) (*models.User, error)
+		FindByNickname(nickname string) (*models.User, error)
+		FindByIDs(ids []int64) ([]models.User, error)
 		UpdateToken(user *models.User, token string) error
+		UpdateNickname(user *models.User, nickname string) error
+		SetResetToken(user *models.User, tokenHash string, expiresAt time.Time) error
+		FindByResetToken(tokenHash string) (*models.User, error)
+		ResetPassword(user *models.User, hashedPassword string) error
 	}
 
 	userRepository struct {
@@ -20,6 +28,12 @@ type (
 	}
 )
 
+// ErrNicknameTaken is returned by UpdateNickname when another user took nickname first
+var ErrNicknameTaken = errors.New("nickname is taken")
+
+// nicknameIndex is unique index of nicknames ignoring their case
+const nicknameIndex = "users_nickname_idx"
+
 func NewUser(db *pg.DB) User {
 	return &userRepository{
 		db: db,
@@ -39,6 +53,12 @@ func (u *userRepository) Create(email, hashedPassword string) (*models.User, err
 	return &user, nil
 }
 
+// Import creates user unless there is one with the same email already ignoring its case,
+// existing user is loaded into user and false is returned
+func (u *userRepository) Import(user *models.User) (bool, error) {
+	return u.db.Model(user).Where("lower(email)=lower(?)", user.Email).SelectOrInsert()
+}
+
 func (u *userRepository) findBy(condition string, val interface{}) (*models.User, error) {
 	var user models.User
 
@@ -61,6 +81,25 @@ func (u *userRepository) FindByToken(token string) (*models.User, error) {
 	return u.findBy("token=?", token)
 }
 
+// FindByNickname looks user up by nickname ignoring its case
+func (u *userRepository) FindByNickname(nickname string) (*models.User, error) {
+	return u.findBy("lower(nickname)=lower(?)", nickname)
+}
+
+// FindByIDs returns users with given ids, missing ones are skipped
+func (u *userRepository) FindByIDs(ids []int64) ([]models.User, error) {
+	users := make([]models.User, 0, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	if err := u.db.Model(&users).Where("id IN (?)", pg.In(ids)).Select(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (u *userRepository) UpdateToken(user *models.User, token string) error {
 	user.Token = token
 	user.UpdatedAt = time.Now()
@@ -71,3 +110,61 @@ func (u *userRepository) UpdateToken(user *models.User, token string) error {
 
 	return nil
 }
+
+// UpdateNickname sets nickname of user, empty nickname removes it
+func (u *userRepository) UpdateNickname(user *models.User, nickname string) error {
+	user.Nickname = nickname
+	user.UpdatedAt = time.Now()
+
+	if _, err := u.db.Model(user).Column("nickname", "updated_at").WherePK().Update(); err != nil {
+		if pgErr, ok := err.(pg.Error); ok && pgErr.IntegrityViolation() && pgErr.Field('n') == nicknameIndex {
+			return ErrNicknameTaken
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// SetResetToken lets user set password with token until it expires, it replaces previous token
+func (u *userRepository) SetResetToken(user *models.User, tokenHash string, expiresAt time.Time) error {
+	user.ResetToken = tokenHash
+	user.ResetExpiresAt = &expiresAt
+	user.UpdatedAt = time.Now()
+
+	_, err := u.db.Model(user).Column("reset_token", "reset_expires_at", "updated_at").WherePK().Update()
+	return err
+}
+
+// FindByResetToken returns user whose reset token is not expired yet
+func (u *userRepository) FindByResetToken(tokenHash string) (*models.User, error) {
+	var user models.User
+	if err := u.db.Model(&user).
+		Where("reset_token=? and reset_expires_at>?", tokenHash, time.Now()).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// ResetPassword sets password of user, drops reset token and lets user sign in again
+func (u *userRepository) ResetPassword(user *models.User, hashedPassword string) error {
+	user.Password = hashedPassword
+	user.PasswordReset = false
+	user.ResetToken = ""
+	user.ResetExpiresAt = nil
+	user.UpdatedAt = time.Now()
+
+	// Zero values are written explicitly since go-pg turns them into NULL
+	_, err := u.db.Model(user).
+		Set("password=?, password_reset=false, reset_token=NULL, reset_expires_at=NULL, updated_at=?", hashedPassword, user.UpdatedAt).
+		WherePK().
+		Update()
+	return err
+}
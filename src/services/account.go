@@ -1,15 +1,21 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"html"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/m1ome/randstr"
 	"github.com/playneta/go-sessions/src/models"
 	"github.com/playneta/go-sessions/src/providers"
 	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -18,24 +24,91 @@ type (
 	Account interface {
 		Register(email, password string) (*models.User, error)
 		Authorize(email, password string) (*models.User, error)
-		CreateMessage(user models.User, receiverEmail, text string) (*models.Message, error)
+		CreateMessage(user models.User, req MessageRequest) (*models.Message, error)
 		History(user models.User) ([]models.Message, error)
+		Messages(user models.User, req HistoryRequest) (*models.HistoryPage, error)
+		CatchUp(user models.User, lastID int64) (*models.HistoryPage, error)
+		Queued(user models.User) ([]models.Message, error)
+		Rename(user models.User, nickname string) (*models.User, error)
+		// IssuePasswordReset returns token user sets new password with, operators hand it out
+		IssuePasswordReset(email string) (string, error)
+		// ResetPassword sets password of user holding reset token and lets user sign in again
+		ResetPassword(token, password string) (*models.User, error)
 	}
 
 	AccountOptions struct {
 		fx.In
 
-		Logger      *zap.SugaredLogger
-		Hasher      providers.Hasher
-		AccountRepo repositories.User
-		MessageRepo repositories.Message
+		Logger         *zap.SugaredLogger
+		Config         *viper.Viper
+		Hasher         providers.Hasher
+		AccountRepo    repositories.User
+		MessageRepo    repositories.Message
+		ReactionRepo   repositories.Reaction
+		AttachmentRepo repositories.Attachment
+		PollRepo       repositories.Poll
+		StarRepo       repositories.Star
+		ReadMarkerRepo repositories.ReadMarker
+		Renderer       providers.Renderer
+
+		MentionService Mention
+		PreviewService Preview
+		ArchiveService Archive
+	}
+
+	// MessageRequest describes message user wants to send, empty receiver
+	// means public message, empty format means markdown and zero ttl means
+	// message never expires
+	MessageRequest struct {
+		// ClientMsgId is set by client so retried sends are stored only once
+		ClientMsgId string
+		To          string
+		Text        string
+		Format      string
+		TTL         time.Duration
+		Attachments []int64
+		// Action marks message sent with /me
+		Action bool
+		// RefId references forwarded or quoted message, RefKind tells which
+		RefId   int64
+		RefKind string
+		// Poll makes message a poll, it is validated by poll service
+		Poll *models.Poll
+	}
+
+	// HistoryRequest describes page of conversation messages, Before and After
+	// are ids of messages page is bounded with, zero means unbounded
+	HistoryRequest struct {
+		Conversation string
+		Before       int64
+		After        int64
+		Limit        int
 	}
 
 	accountService struct {
-		accountRepo repositories.User
-		messageRepo repositories.Message
-		logger      *zap.SugaredLogger
-		hasher      providers.Hasher
+		accountRepo    repositories.User
+		messageRepo    repositories.Message
+		reactionRepo   repositories.Reaction
+		attachmentRepo repositories.Attachment
+		pollRepo       repositories.Poll
+		starRepo       repositories.Star
+		readMarkerRepo repositories.ReadMarker
+		mentions       Mention
+		previews       Preview
+		archive        Archive
+		logger         *zap.SugaredLogger
+		hasher         providers.Hasher
+		renderer       providers.Renderer
+		maxTTL         time.Duration
+		maxLength      int
+		resetTTL       time.Duration
+
+		// History sent on connect, private limit applies to every conversation
+		publicHistory        int
+		privateHistory       int
+		privateConversations int
+		catchUp              int
+		queue                int
 	}
 )
 
@@ -43,14 +116,62 @@ var (
 	ErrMalformedEmail  = errors.New("malformed email")
 	ErrPasswordToSmall = errors.New("pussword must be more than 5 symbols")
 	ErrUnauthorized    = errors.New("unauthorized")
+	ErrMalformedText   = errors.New("message text is not valid utf-8")
+	ErrMalformedFormat = errors.New("unknown message format")
+	ErrMalformedTTL    = errors.New("message ttl is out of range")
+	ErrMessageTooLong  = errors.New("message text is too long")
+
+	ErrMalformedClientMsgID = errors.New("client message id must be at most 64 characters")
+	// ErrDuplicateMessage is returned along with message stored by earlier send with the same client id
+	ErrDuplicateMessage = errors.New("message was already sent")
+
+	ErrMalformedRef  = errors.New("reference must forward a message or quote one from the same conversation")
+	ErrRefNotAllowed = errors.New("self-destructing messages can not be forwarded or quoted")
+
+	ErrMalformedNickname = errors.New("nickname must be 2-32 letters, digits, dots, dashes or underscores")
+	ErrNicknameTaken     = errors.New("nickname is already taken")
+
+	ErrUserNotFound      = errors.New("user not found")
+	ErrResetTokenInvalid = errors.New("password reset token is invalid or expired")
 )
 
+const maxClientMsgID = 64
+
+var nicknameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{2,32}$`)
+
 func NewAccount(opts AccountOptions) Account {
+	opts.Config.SetDefault("messages.max_ttl", 7*24*time.Hour)
+	opts.Config.SetDefault("messages.max_length", 16384)
+	opts.Config.SetDefault("history.public", 10)
+	opts.Config.SetDefault("history.private", 10)
+	opts.Config.SetDefault("history.conversations", 20)
+	opts.Config.SetDefault("history.catch_up", 500)
+	opts.Config.SetDefault("history.queue", 500)
+	opts.Config.SetDefault("password_reset.ttl", 72*time.Hour)
+
 	return &accountService{
-		logger:      opts.Logger.Named("account_service"),
-		accountRepo: opts.AccountRepo,
-		messageRepo: opts.MessageRepo,
-		hasher:      opts.Hasher,
+		logger:         opts.Logger.Named("account_service"),
+		accountRepo:    opts.AccountRepo,
+		messageRepo:    opts.MessageRepo,
+		reactionRepo:   opts.ReactionRepo,
+		attachmentRepo: opts.AttachmentRepo,
+		pollRepo:       opts.PollRepo,
+		starRepo:       opts.StarRepo,
+		readMarkerRepo: opts.ReadMarkerRepo,
+		mentions:       opts.MentionService,
+		previews:       opts.PreviewService,
+		archive:        opts.ArchiveService,
+		hasher:         opts.Hasher,
+		renderer:       opts.Renderer,
+		maxTTL:         opts.Config.GetDuration("messages.max_ttl"),
+		maxLength:      opts.Config.GetInt("messages.max_length"),
+		resetTTL:       opts.Config.GetDuration("password_reset.ttl"),
+
+		publicHistory:        opts.Config.GetInt("history.public"),
+		privateHistory:       opts.Config.GetInt("history.private"),
+		privateConversations: opts.Config.GetInt("history.conversations"),
+		catchUp:              opts.Config.GetInt("history.catch_up"),
+		queue:                opts.Config.GetInt("history.queue"),
 	}
 }
 
@@ -78,6 +199,11 @@ func (a *accountService) Authorize(email, password string) (*models.User, error)
 		return nil, ErrUnauthorized
 	}
 
+	if user.PasswordReset {
+		a.logger.Debugf("password reset pending")
+		return nil, ErrUnauthorized
+	}
+
 	if !a.hasher.Compare(password, user.Password) {
 		a.logger.Debugf("password hash not match")
 		return nil, ErrUnauthorized
@@ -91,43 +217,178 @@ func (a *accountService) Authorize(email, password string) (*models.User, error)
 	return user, nil
 }
 
-func (a *accountService) CreateMessage(user models.User, receiverEmail, text string) (*models.Message, error) {
-	if len(text) == 0 {
-		return nil, errors.New("empty message text")
+// IssuePasswordReset stores only hash of token so it can not be taken from database
+func (a *accountService) IssuePasswordReset(email string) (string, error) {
+	user, err := a.accountRepo.FindByEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := a.accountRepo.SetResetToken(user, hashResetToken(token), time.Now().Add(a.resetTTL)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ResetPassword token is used once, repository drops it with password_reset flag
+func (a *accountService) ResetPassword(token, password string) (*models.User, error) {
+	if len(password) < 6 {
+		return nil, ErrPasswordToSmall
+	}
+
+	user, err := a.accountRepo.FindByResetToken(hashResetToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrResetTokenInvalid
+	}
+
+	hashedPassword, err := a.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.accountRepo.ResetPassword(user, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *accountService) CreateMessage(user models.User, req MessageRequest) (*models.Message, error) {
+	if len(req.ClientMsgId) > maxClientMsgID {
+		return nil, ErrMalformedClientMsgID
+	}
+
+	// Retried send is answered with the message stored before, attachments
+	// of retried message are already linked so it would not pass validation
+	if req.ClientMsgId != "" {
+		if message, err := a.sent(user, req.ClientMsgId); message != nil || err != nil {
+			return message, err
+		}
+	}
+
+	// Forwarded message is allowed to have no text of its own
+	if len(req.Text) == 0 && len(req.Attachments) == 0 && req.RefKind != models.RefForward {
+		return nil, ErrEmptyText
+	}
+
+	// Text is rendered on every read, long ones would slow down every reader
+	if len(req.Text) > a.maxLength {
+		return nil, ErrMessageTooLong
+	}
+
+	format, err := messageFormat(req.Text, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TTL < 0 || req.TTL > a.maxTTL {
+		return nil, ErrMalformedTTL
+	}
+
+	if (req.RefId == 0) != (req.RefKind == "") ||
+		(req.RefKind != "" && req.RefKind != models.RefForward && req.RefKind != models.RefQuote) {
+		return nil, ErrMalformedRef
 	}
 
 	var receiverID int64
-	if receiverEmail != "" {
-		r, err := a.accountRepo.FindByEmail(receiverEmail)
+	if req.To != "" {
+		r, err := a.accountRepo.FindByEmail(req.To)
 		if err != nil {
 			return nil, err
 		}
 
+		if r == nil {
+			return nil, ErrReceiverNotFound
+		}
+
 		receiverID = r.ID
 	}
 
+	mentions, err := a.mentions.Resolve(user, receiverID, req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := a.attachmentRepo.Unlinked(user, req.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attachments) != len(req.Attachments) {
+		return nil, ErrAttachmentNotFound
+	}
+
 	message := &models.Message{
-		UserId:     user.ID,
-		ReceiverId: receiverID,
-		Text:       text,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ClientMsgId: req.ClientMsgId,
+		UserId:      user.ID,
+		ReceiverId:  receiverID,
+		Type:        models.MessageText,
+		Text:        req.Text,
+		Format:      format,
+		Attachments: attachments,
+		Mentions:    mentions,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if req.Action {
+		message.Type = models.MessageAction
+	}
+
+	if req.Poll != nil {
+		message.Type = models.MessagePoll
+		message.Poll = req.Poll
+	}
+
+	if err := a.reference(user, message, req); err != nil {
+		return nil, err
+	}
+
+	if req.TTL > 0 {
+		expiresAt := message.CreatedAt.Add(req.TTL)
+		message.ExpiresAt = &expiresAt
 	}
 
 	if err := a.messageRepo.Create(message); err != nil {
+		// Concurrent retry was stored first
+		if err == repositories.ErrDuplicateMessage {
+			if sent, err := a.sent(user, req.ClientMsgId); sent != nil || err != nil {
+				return sent, err
+			}
+		}
+
+		if err == repositories.ErrAttachmentTaken {
+			return nil, ErrAttachmentNotFound
+		}
+
 		return nil, err
 	}
 
+	render(a.renderer, message)
 	return message, nil
 }
 
 func (a *accountService) History(user models.User) ([]models.Message, error) {
-	public, err := a.messageRepo.LastPublicMessages(10)
+	public, err := a.messageRepo.LastPublicMessages(a.publicHistory)
 	if err != nil {
 		return nil, err
 	}
 
-	private, err := a.messageRepo.LastPrivateMessages(user, 10)
+	private, err := a.messageRepo.LastPrivateMessages(user, a.privateConversations, a.privateHistory)
 	if err != nil {
 		return nil, err
 	}
@@ -140,9 +401,423 @@ func (a *accountService) History(user models.User) ([]models.Message, error) {
 		return messages[j].CreatedAt.After(messages[i].CreatedAt)
 	})
 
+	if err := a.attach(user, messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Rename sets nickname of user, empty nickname removes it
+func (a *accountService) Rename(user models.User, nickname string) (*models.User, error) {
+	if nickname != "" {
+		if !nicknameRegex.MatchString(nickname) {
+			return nil, ErrMalformedNickname
+		}
+
+		owner, err := a.accountRepo.FindByNickname(nickname)
+		if err != nil {
+			return nil, err
+		}
+
+		if owner != nil && owner.ID != user.ID {
+			return nil, ErrNicknameTaken
+		}
+	}
+
+	// Nickname could be taken by concurrent rename after it was checked
+	if err := a.accountRepo.UpdateNickname(&user, nickname); err == repositories.ErrNicknameTaken {
+		return nil, ErrNicknameTaken
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// status fills delivery status of private messages user sent,
+// read ones are those up to read marker of the other participant
+func (a *accountService) status(user models.User, messages []models.Message) error {
+	var conversations []string
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		if message.UserId != user.ID || message.ReceiverId == 0 || seen[message.Conversation()] {
+			continue
+		}
+
+		seen[message.Conversation()] = true
+		conversations = append(conversations, message.Conversation())
+	}
+
+	if len(conversations) == 0 {
+		return nil
+	}
+
+	read, err := a.readMarkerRepo.Partners(user, conversations)
+	if err != nil {
+		return err
+	}
+
+	for i, message := range messages {
+		if message.UserId != user.ID || message.ReceiverId == 0 {
+			continue
+		}
+
+		switch {
+		case read[message.Conversation()] >= message.Id:
+			messages[i].Status = models.StatusRead
+		case message.DeliveredAt != nil:
+			messages[i].Status = models.StatusDelivered
+		default:
+			messages[i].Status = models.StatusSent
+		}
+	}
+
+	return nil
+}
+
+// sent returns message user already sent with client id along with ErrDuplicateMessage
+func (a *accountService) sent(user models.User, clientMsgID string) (*models.Message, error) {
+	message, err := a.messageRepo.FindByClientID(user, clientMsgID)
+	if err != nil || message == nil {
+		return nil, err
+	}
+
+	render(a.renderer, message)
+	return message, ErrDuplicateMessage
+}
+
+// reference snapshots message forwarded or quoted by user, the original
+// must be visible to user so private messages can not leak through forwards
+func (a *accountService) reference(user models.User, message *models.Message, req MessageRequest) error {
+	if req.RefId == 0 {
+		return nil
+	}
+
+	original, err := a.messageRepo.Find(req.RefId)
+	if err != nil {
+		return err
+	}
+
+	if original == nil || !original.VisibleTo(user) {
+		return ErrMessageNotFound
+	}
+
+	if original.ExpiresAt != nil {
+		return ErrRefNotAllowed
+	}
+
+	if req.RefKind == models.RefQuote && original.Conversation() != message.Conversation() {
+		return ErrMalformedRef
+	}
+
+	message.RefId = original.Id
+	message.Ref = &models.MessageRef{
+		Kind:      req.RefKind,
+		From:      original.User.Email,
+		Type:      original.Type,
+		Text:      original.Text,
+		Format:    original.Format,
+		CreatedAt: original.CreatedAt,
+	}
+
+	return nil
+}
+
+// Messages returns page of conversation user participates in, it lets clients
+// scroll history back and forth without reconnecting
+func (a *accountService) Messages(user models.User, req HistoryRequest) (*models.HistoryPage, error) {
+	if !models.ConversationVisibleTo(req.Conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	query := models.HistoryQuery{
+		Conversation: req.Conversation,
+		Before:       req.Before,
+		After:        req.After,
+		Limit:        req.Limit + 1,
+	}
+
+	messages, err := a.messageRepo.History(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Table ran out in the direction of paging, the rest may be archived
+	if len(messages) < query.Limit {
+		if messages, err = a.archived(query, messages); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &models.HistoryPage{
+		Messages: []models.Message{},
+	}
+
+	// The extra message tells whether there is more in the direction of paging,
+	// in the opposite direction there is more whenever page is bounded by cursor
+	ascending := query.Ascending()
+	more := len(messages) > req.Limit
+
+	older, newer := more, req.Before > 0
+	if ascending {
+		older, newer = true, more
+	}
+
+	if more {
+		if ascending {
+			messages = messages[:req.Limit]
+		} else {
+			messages = messages[1:]
+		}
+	}
+
+	if len(messages) == 0 {
+		return page, nil
+	}
+
+	if older {
+		page.Before = messages[0].Id
+	}
+
+	if newer {
+		page.After = messages[len(messages)-1].Id
+	}
+
+	if err := a.attach(user, messages); err != nil {
+		return nil, err
+	}
+
+	page.Messages = messages
+	return page, nil
+}
+
+// archived completes page of messages from table with archived ones, archived
+// messages of conversation are always older than those left in table
+func (a *accountService) archived(query models.HistoryQuery, messages []models.Message) ([]models.Message, error) {
+	if query.Ascending() {
+		// Paging forward reaches archive only from archived cursor table does not know
+		if len(messages) > 0 {
+			return messages, nil
+		}
+
+		if message, err := a.messageRepo.Find(query.After); err != nil || message != nil {
+			return messages, err
+		}
+
+		message, err := a.archive.Find(query.Conversation, query.After)
+		if err != nil || message == nil {
+			return messages, err
+		}
+
+		after := message.Key()
+		archived, err := a.archive.Range(query.Conversation, &after, nil, query.Limit, true)
+		if err != nil || len(archived) == query.Limit {
+			return archived, err
+		}
+
+		if len(archived) > 0 {
+			after = archived[len(archived)-1].Key()
+		}
+
+		rest, err := a.messageRepo.History(models.HistoryQuery{
+			Conversation: query.Conversation,
+			AfterKey:     &after,
+			Limit:        query.Limit - len(archived),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return append(archived, rest...), nil
+	}
+
+	var before, after *models.MessageKey
+	if len(messages) > 0 {
+		key := messages[0].Key()
+		before = &key
+	} else if query.Before > 0 {
+		key, err := a.cursor(query.Conversation, query.Before)
+		if err != nil || key == nil {
+			return messages, err
+		}
+
+		before = key
+	}
+
+	if query.After > 0 {
+		key, err := a.cursor(query.Conversation, query.After)
+		if err != nil || key == nil {
+			return messages, err
+		}
+
+		after = key
+	}
+
+	archived, err := a.archive.Range(query.Conversation, after, before, query.Limit-len(messages), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(archived, messages...), nil
+}
+
+// cursor locates message page is bounded with either in table or in archive
+func (a *accountService) cursor(conversation string, id int64) (*models.MessageKey, error) {
+	message, err := a.messageRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil {
+		if message, err = a.archive.Find(conversation, id); err != nil || message == nil {
+			return nil, err
+		}
+	}
+
+	key := message.Key()
+	return &key, nil
+}
+
+// CatchUp returns messages of all conversations of user posted after the last one
+// client saw, when there are too many of them only the newest are returned and
+// Before cursor of the page tells client that history in between has to be paged
+func (a *accountService) CatchUp(user models.User, lastID int64) (*models.HistoryPage, error) {
+	messages, err := a.messageRepo.Missed(user, lastID, a.catchUp+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.HistoryPage{
+		Messages: []models.Message{},
+	}
+
+	if len(messages) > a.catchUp {
+		messages = messages[1:]
+		if len(messages) > 0 {
+			page.Before = messages[0].Id
+		}
+	}
+
+	if len(messages) == 0 {
+		return page, nil
+	}
+
+	if err := a.attach(user, messages); err != nil {
+		return nil, err
+	}
+
+	page.Messages = messages
+	return page, nil
+}
+
+// Queued returns private messages sent to user while none of user devices was connected
+func (a *accountService) Queued(user models.User) ([]models.Message, error) {
+	messages, err := a.messageRepo.Undelivered(user, a.queue)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return []models.Message{}, nil
+	}
+
+	if err := a.attach(user, messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
 
+// attach loads everything shown along with messages and renders them
+func (a *accountService) attach(user models.User, messages []models.Message) error {
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	reactions, err := a.reactionRepo.Summaries(user, ids)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := a.attachmentRepo.ForMessages(ids)
+	if err != nil {
+		return err
+	}
+
+	polls, err := a.pollRepo.ForMessages(user, ids)
+	if err != nil {
+		return err
+	}
+
+	starred, err := a.starRepo.Starred(user, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Reactions = reactions[messages[i].Id]
+		messages[i].Attachments = attachments[messages[i].Id]
+		messages[i].Poll = polls[messages[i].Id]
+		messages[i].Starred = starred[messages[i].Id]
+		render(a.renderer, &messages[i])
+	}
+
+	if err := a.status(user, messages); err != nil {
+		return err
+	}
+
+	if err := a.mentions.Attach(messages); err != nil {
+		return err
+	}
+
+	if err := a.previews.Attach(messages); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func generateToken() string {
 	return randstr.GetString(16)
 }
+
+// messageFormat validates message text and returns its format, markdown is the default
+func messageFormat(text, format string) (string, error) {
+	if !utf8.ValidString(text) {
+		return "", ErrMalformedText
+	}
+
+	switch format {
+	case "":
+		return models.FormatMarkdown, nil
+	case models.FormatMarkdown, models.FormatPlain:
+		return format, nil
+	default:
+		return "", ErrMalformedFormat
+	}
+}
+
+// render fills html of message, it is rendered on every read
+// so changes of renderer apply to already stored messages
+func render(renderer providers.Renderer, message *models.Message) {
+	message.HTML = renderText(renderer, message.Text, message.Format)
+
+	if message.Ref != nil {
+		message.Ref.HTML = renderText(renderer, message.Ref.Text, message.Ref.Format)
+	}
+}
+
+func renderText(renderer providers.Renderer, text, format string) string {
+	if format == models.FormatPlain {
+		return strings.Replace(html.EscapeString(text), "\n", "<br>", -1)
+	}
+
+	return renderer.Render(text)
+}
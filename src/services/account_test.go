@@ -2,14 +2,20 @@ package services
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
 	mock_providers "github.com/playneta/go-sessions/src/providers/mocks"
+	"github.com/playneta/go-sessions/src/repositories"
 	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -21,6 +27,22 @@ func TestAccountService(t *testing.T) {
 	// Creating new account repository mock
 	account := mock_repositories.NewMockUser(ctrl)
 	messages := mock_repositories.NewMockMessage(ctrl)
+	reactions := mock_repositories.NewMockReaction(ctrl)
+	mentions := mock_repositories.NewMockMention(ctrl)
+	attachments := mock_repositories.NewMockAttachment(ctrl)
+	previews := mock_repositories.NewMockPreview(ctrl)
+	polls := mock_repositories.NewMockPoll(ctrl)
+	stars := mock_repositories.NewMockStar(ctrl)
+	readMarkers := mock_repositories.NewMockReadMarker(ctrl)
+	archives := mock_repositories.NewMockArchive(ctrl)
+
+	// Archived segments are kept on disk
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := providers.NewLocalBlobStore(dir)
+	require.NoError(t, err)
 
 	// Creating new hasher mock
 	hasher := mock_providers.NewMockHasher(ctrl)
@@ -28,12 +50,40 @@ func TestAccountService(t *testing.T) {
 	// Noop logger
 	logger := zap.NewNop().Sugar()
 
-	// Service
-	accountService := NewAccount(AccountOptions{
+	archiveService := NewArchive(ArchiveOptions{
+		Logger:      logger,
+		Config:      viper.New(),
 		AccountRepo: account,
 		MessageRepo: messages,
-		Logger:      logger,
-		Hasher:      hasher,
+		ArchiveRepo: archives,
+		Store:       store,
+	})
+
+	// Service
+	accountService := NewAccount(AccountOptions{
+		AccountRepo:    account,
+		MessageRepo:    messages,
+		ReactionRepo:   reactions,
+		AttachmentRepo: attachments,
+		PollRepo:       polls,
+		StarRepo:       stars,
+		ReadMarkerRepo: readMarkers,
+		Logger:         logger,
+		Config:         viper.New(),
+		Hasher:         hasher,
+		Renderer:       providers.NewMarkdownRenderer(),
+
+		MentionService: NewMention(MentionOptions{
+			Logger:      logger,
+			AccountRepo: account,
+			MentionRepo: mentions,
+		}),
+		PreviewService: NewPreview(PreviewOptions{
+			Logger:      logger,
+			Config:      viper.New(),
+			PreviewRepo: previews,
+		}),
+		ArchiveService: archiveService,
 	})
 
 	t.Run("Register", func(t *testing.T) {
@@ -115,6 +165,15 @@ func TestAccountService(t *testing.T) {
 				require.Error(t, err, ErrUnauthorized)
 			})
 
+			t.Run("Pending password reset should return error", func(t *testing.T) {
+				imported := &models.User{ID: 2, Email: "user@example.com", Password: "!", PasswordReset: true}
+				account.EXPECT().FindByEmail("user@example.com").Return(imported, nil)
+
+				user, err := accountService.Authorize("user@example.com", "!")
+				require.Nil(t, user)
+				require.Equal(t, ErrUnauthorized, err)
+			})
+
 			t.Run("Update error should return error", func(t *testing.T) {
 				account.EXPECT().FindByEmail("user@example.com").Return(user, nil)
 				hasher.EXPECT().Compare("123456", "my_password_hash").Return(true)
@@ -137,6 +196,60 @@ func TestAccountService(t *testing.T) {
 		})
 	})
 
+	t.Run("Password reset", func(t *testing.T) {
+		t.Run("Unknown user", func(t *testing.T) {
+			account.EXPECT().FindByEmail("user@example.com").Return(nil, nil)
+
+			token, err := accountService.IssuePasswordReset("user@example.com")
+			require.Empty(t, token)
+			require.Equal(t, ErrUserNotFound, err)
+		})
+
+		t.Run("Issue", func(t *testing.T) {
+			imported := &models.User{ID: 2, Email: "user@example.com", Password: "!", PasswordReset: true}
+			account.EXPECT().FindByEmail("user@example.com").Return(imported, nil)
+
+			var stored string
+			account.EXPECT().SetResetToken(imported, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(user *models.User, tokenHash string, expiresAt time.Time) error {
+					stored = tokenHash
+					require.True(t, expiresAt.After(time.Now().Add(71*time.Hour)))
+					return nil
+				})
+
+			token, err := accountService.IssuePasswordReset("user@example.com")
+			require.NoError(t, err)
+			require.Len(t, token, 64)
+			require.Equal(t, hashResetToken(token), stored)
+			require.NotEqual(t, token, stored)
+		})
+
+		t.Run("Password not strong enough", func(t *testing.T) {
+			user, err := accountService.ResetPassword("token", "123")
+			require.Nil(t, user)
+			require.Equal(t, ErrPasswordToSmall, err)
+		})
+
+		t.Run("Invalid token", func(t *testing.T) {
+			account.EXPECT().FindByResetToken(hashResetToken("token")).Return(nil, nil)
+
+			user, err := accountService.ResetPassword("token", "123456")
+			require.Nil(t, user)
+			require.Equal(t, ErrResetTokenInvalid, err)
+		})
+
+		t.Run("Reset", func(t *testing.T) {
+			imported := &models.User{ID: 2, Email: "user@example.com", Password: "!", PasswordReset: true}
+			account.EXPECT().FindByResetToken(hashResetToken("token")).Return(imported, nil)
+			hasher.EXPECT().Hash("123456").Return("my_password_hash", nil)
+			account.EXPECT().ResetPassword(imported, "my_password_hash").Return(nil)
+
+			user, err := accountService.ResetPassword("token", "123456")
+			require.NoError(t, err)
+			require.Equal(t, imported, user)
+		})
+	})
+
 	t.Run("Create Message", func(t *testing.T) {
 		user := models.User{
 			ID:        1,
@@ -148,37 +261,294 @@ func TestAccountService(t *testing.T) {
 		}
 
 		t.Run("Empty text", func(t *testing.T) {
-			message, err := accountService.CreateMessage(user, "", "")
+			message, err := accountService.CreateMessage(user, MessageRequest{})
 			require.Nil(t, message)
 			require.Error(t, err)
 		})
 
+		t.Run("Malformed text", func(t *testing.T) {
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "\xff"})
+			require.Nil(t, message)
+			require.Equal(t, ErrMalformedText, err)
+		})
+
+		t.Run("Too long text", func(t *testing.T) {
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: strings.Repeat("a", 16385)})
+			require.Nil(t, message)
+			require.Equal(t, ErrMessageTooLong, err)
+		})
+
+		t.Run("Malformed format", func(t *testing.T) {
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", Format: "html"})
+			require.Nil(t, message)
+			require.Equal(t, ErrMalformedFormat, err)
+		})
+
+		t.Run("Malformed ttl", func(t *testing.T) {
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", TTL: 30 * 24 * time.Hour})
+			require.Nil(t, message)
+			require.Equal(t, ErrMalformedTTL, err)
+		})
+
+		t.Run("Self-destructing", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+			messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", TTL: time.Minute})
+			require.NoError(t, err)
+			require.Equal(t, message.CreatedAt.Add(time.Minute), *message.ExpiresAt)
+			require.False(t, message.Expired(time.Now()))
+			require.True(t, message.Expired(time.Now().Add(time.Minute)))
+		})
+
+		t.Run("Plain", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+			messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "**<b>**\nnext", Format: models.FormatPlain})
+			require.NoError(t, err)
+			require.Equal(t, models.FormatPlain, message.Format)
+			require.Equal(t, "**&lt;b&gt;**<br>next", message.HTML)
+		})
+
+		t.Run("Attachment taken by concurrent send", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, []int64{5}).Return([]models.Attachment{{Id: 5, UserId: 1}}, nil)
+			messages.EXPECT().Create(gomock.Any()).Return(repositories.ErrAttachmentTaken)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", Attachments: []int64{5}})
+			require.Nil(t, message)
+			require.Equal(t, ErrAttachmentNotFound, err)
+		})
+
 		t.Run("Non existent receiver", func(t *testing.T) {
 			account.EXPECT().FindByEmail("unknown@example.com").Return(nil, errors.New("unknown user"))
 
-			message, err := accountService.CreateMessage(user, "unknown@example.com", "text")
+			message, err := accountService.CreateMessage(user, MessageRequest{To: "unknown@example.com", Text: "text"})
 			require.Nil(t, message)
 			require.Error(t, err)
 		})
 
+		t.Run("Client message id", func(t *testing.T) {
+			t.Run("Malformed", func(t *testing.T) {
+				message, err := accountService.CreateMessage(user, MessageRequest{ClientMsgId: strings.Repeat("a", 65), Text: "text"})
+				require.Nil(t, message)
+				require.Equal(t, ErrMalformedClientMsgID, err)
+			})
+
+			t.Run("Retried", func(t *testing.T) {
+				sent := &models.Message{Id: 5, UserId: 1, ClientMsgId: "c1", Text: "text", Attachments: []models.Attachment{{Id: 5}}}
+				messages.EXPECT().FindByClientID(user, "c1").Return(sent, nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{ClientMsgId: "c1", Text: "text", Attachments: []int64{5}})
+				require.Equal(t, ErrDuplicateMessage, err)
+				require.Equal(t, int64(5), message.Id)
+				require.Equal(t, "<p>text</p>", message.HTML)
+			})
+
+			t.Run("Concurrent retry", func(t *testing.T) {
+				sent := &models.Message{Id: 6, UserId: 1, ClientMsgId: "c2", Text: "text"}
+				messages.EXPECT().FindByClientID(user, "c2").Return(nil, nil)
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Create(gomock.Any()).Return(repositories.ErrDuplicateMessage)
+				messages.EXPECT().FindByClientID(user, "c2").Return(sent, nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{ClientMsgId: "c2", Text: "text"})
+				require.Equal(t, ErrDuplicateMessage, err)
+				require.Equal(t, int64(6), message.Id)
+			})
+
+			t.Run("First send", func(t *testing.T) {
+				messages.EXPECT().FindByClientID(user, "c3").Return(nil, nil)
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{ClientMsgId: "c3", Text: "text"})
+				require.NoError(t, err)
+				require.Equal(t, "c3", message.ClientMsgId)
+			})
+		})
+
+		t.Run("Unknown receiver", func(t *testing.T) {
+			account.EXPECT().FindByEmail("unknown@example.com").Return(nil, nil)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{To: "unknown@example.com", Text: "text"})
+			require.Nil(t, message)
+			require.Equal(t, ErrReceiverNotFound, err)
+		})
+
+		t.Run("Action", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+			messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "waves", Action: true})
+			require.NoError(t, err)
+			require.Equal(t, models.MessageAction, message.Type)
+		})
+
+		t.Run("References", func(t *testing.T) {
+			author := &models.User{ID: 2, Email: "author@example.com"}
+			friend := &models.User{ID: 3, Email: "friend@example.com"}
+			created := time.Now().Add(-time.Hour)
+			public := &models.Message{Id: 7, UserId: 2, User: author, Type: models.MessageText, Text: "**news**", Format: models.FormatMarkdown, CreatedAt: created}
+
+			t.Run("Malformed kind", func(t *testing.T) {
+				message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", RefId: 7, RefKind: "reply"})
+				require.Nil(t, message)
+				require.Equal(t, ErrMalformedRef, err)
+			})
+
+			t.Run("Foreign direct message", func(t *testing.T) {
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Find(int64(8)).Return(&models.Message{Id: 8, UserId: 2, User: author, ReceiverId: 3, Receiver: friend}, nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{RefId: 8, RefKind: models.RefForward})
+				require.Nil(t, message)
+				require.Equal(t, ErrMessageNotFound, err)
+			})
+
+			t.Run("Self-destructing original", func(t *testing.T) {
+				expiresAt := time.Now().Add(time.Minute)
+				ephemeral := *public
+				ephemeral.ExpiresAt = &expiresAt
+
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Find(int64(7)).Return(&ephemeral, nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{RefId: 7, RefKind: models.RefForward})
+				require.Nil(t, message)
+				require.Equal(t, ErrRefNotAllowed, err)
+			})
+
+			t.Run("Quote from another conversation", func(t *testing.T) {
+				account.EXPECT().FindByEmail("friend@example.com").Return(friend, nil)
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Find(int64(7)).Return(public, nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{To: "friend@example.com", Text: "look", RefId: 7, RefKind: models.RefQuote})
+				require.Nil(t, message)
+				require.Equal(t, ErrMalformedRef, err)
+			})
+
+			t.Run("Forward", func(t *testing.T) {
+				account.EXPECT().FindByEmail("friend@example.com").Return(friend, nil)
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Find(int64(7)).Return(public, nil)
+				messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{To: "friend@example.com", RefId: 7, RefKind: models.RefForward})
+				require.NoError(t, err)
+				require.Equal(t, int64(7), message.RefId)
+				require.Equal(t, &models.MessageRef{
+					Kind:      models.RefForward,
+					From:      "author@example.com",
+					Type:      models.MessageText,
+					Text:      "**news**",
+					Format:    models.FormatMarkdown,
+					HTML:      "<p><strong>news</strong></p>",
+					CreatedAt: created,
+				}, message.Ref)
+			})
+
+			t.Run("Quote", func(t *testing.T) {
+				attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
+				messages.EXPECT().Find(int64(7)).Return(public, nil)
+				messages.EXPECT().Create(gomock.Any()).Return(nil)
+
+				message, err := accountService.CreateMessage(user, MessageRequest{Text: "agreed", RefId: 7, RefKind: models.RefQuote})
+				require.NoError(t, err)
+				require.Equal(t, models.RefQuote, message.Ref.Kind)
+				require.Equal(t, "agreed", message.Text)
+			})
+		})
+
+		t.Run("Foreign attachment", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, []int64{5}).Return([]models.Attachment{}, nil)
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text", Attachments: []int64{5}})
+			require.Nil(t, message)
+			require.Equal(t, ErrAttachmentNotFound, err)
+		})
+
+		t.Run("Mention not allowed", func(t *testing.T) {
+
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "@all hello"})
+			require.Nil(t, message)
+			require.Equal(t, ErrMentionNotAllowed, err)
+		})
+
 		t.Run("Failure", func(t *testing.T) {
+			attachments.EXPECT().Unlinked(user, nil).Return([]models.Attachment{}, nil)
 			messages.EXPECT().Create(gomock.Any()).Return(errors.New("error creating message"))
 
-			message, err := accountService.CreateMessage(user, "", "text")
+			message, err := accountService.CreateMessage(user, MessageRequest{Text: "text"})
 			require.Nil(t, message)
 			require.Error(t, err)
 		})
 
 		t.Run("Success", func(t *testing.T) {
 			account.EXPECT().FindByEmail("user@example.com").Return(&models.User{ID: 100}, nil)
+			attachments.EXPECT().Unlinked(user, []int64{5}).Return([]models.Attachment{{Id: 5, UserId: 1}}, nil)
 			messages.EXPECT().Create(gomock.Any()).Return(nil)
 
-			message, err := accountService.CreateMessage(user, "user@example.com", "text")
+			message, err := accountService.CreateMessage(user, MessageRequest{To: "user@example.com", Text: "text", Attachments: []int64{5}})
 
 			require.NoError(t, err)
 			require.Equal(t, user.ID, message.UserId)
 			require.Equal(t, int64(100), message.ReceiverId)
 			require.Equal(t, "text", message.Text)
+			require.Equal(t, models.FormatMarkdown, message.Format)
+			require.Equal(t, "<p>text</p>", message.HTML)
+		})
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		user := models.User{ID: 1, Email: "user@example.com"}
+
+		t.Run("Malformed nickname", func(t *testing.T) {
+			renamed, err := accountService.Rename(user, "bad nick")
+			require.Nil(t, renamed)
+			require.Equal(t, ErrMalformedNickname, err)
+		})
+
+		t.Run("Taken", func(t *testing.T) {
+			account.EXPECT().FindByNickname("neo").Return(&models.User{ID: 2, Nickname: "Neo"}, nil)
+
+			renamed, err := accountService.Rename(user, "neo")
+			require.Nil(t, renamed)
+			require.Equal(t, ErrNicknameTaken, err)
+		})
+
+		t.Run("Taken by concurrent rename", func(t *testing.T) {
+			account.EXPECT().FindByNickname("neo").Return(nil, nil)
+			account.EXPECT().UpdateNickname(gomock.Any(), "neo").Return(repositories.ErrNicknameTaken)
+
+			renamed, err := accountService.Rename(user, "neo")
+			require.Nil(t, renamed)
+			require.Equal(t, ErrNicknameTaken, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			account.EXPECT().FindByNickname("neo").Return(nil, nil)
+			account.EXPECT().UpdateNickname(gomock.Any(), "neo").DoAndReturn(func(user *models.User, nickname string) error {
+				user.Nickname = nickname
+				return nil
+			})
+
+			renamed, err := accountService.Rename(user, "neo")
+			require.NoError(t, err)
+			require.Equal(t, "neo", renamed.Nickname)
+			require.Equal(t, "neo", renamed.Name())
+		})
+
+		t.Run("Reset", func(t *testing.T) {
+			account.EXPECT().UpdateNickname(gomock.Any(), "").DoAndReturn(func(user *models.User, nickname string) error {
+				user.Nickname = nickname
+				return nil
+			})
+
+			renamed, err := accountService.Rename(models.User{ID: 1, Email: "user@example.com", Nickname: "neo"}, "")
+			require.NoError(t, err)
+			require.Equal(t, "user@example.com", renamed.Name())
 		})
 	})
 
@@ -203,7 +573,7 @@ func TestAccountService(t *testing.T) {
 
 			t.Run("Private", func(t *testing.T) {
 				messages.EXPECT().LastPublicMessages(10).Return([]models.Message{}, nil)
-				messages.EXPECT().LastPrivateMessages(user, 10).Return(nil, errors.New("error getting private messages"))
+				messages.EXPECT().LastPrivateMessages(user, 20, 10).Return(nil, errors.New("error getting private messages"))
 
 				messages, err := accountService.History(user)
 				require.Nil(t, messages)
@@ -221,6 +591,8 @@ func TestAccountService(t *testing.T) {
 				},
 				{
 					Id:        2,
+					Text:      "**hi**",
+					Format:    models.FormatMarkdown,
 					CreatedAt: ts,
 				},
 			}
@@ -233,12 +605,314 @@ func TestAccountService(t *testing.T) {
 			}
 
 			messages.EXPECT().LastPublicMessages(10).Return(public, nil)
-			messages.EXPECT().LastPrivateMessages(user, 10).Return(private, nil)
+			messages.EXPECT().LastPrivateMessages(user, 20, 10).Return(private, nil)
+			reactions.EXPECT().Summaries(user, []int64{1, 2, 3}).Return(map[int64][]models.ReactionSummary{
+				2: {{Emoji: "👍", Count: 2, Me: true}},
+			}, nil)
+			attachments.EXPECT().ForMessages([]int64{1, 2, 3}).Return(map[int64][]models.Attachment{}, nil)
+			polls.EXPECT().ForMessages(user, []int64{1, 2, 3}).Return(map[int64]*models.Poll{
+				1: {MessageId: 1, Options: []models.PollOption{{Id: 1, Text: "yes", Votes: 1, Me: true}}},
+			}, nil)
+			stars.EXPECT().Starred(user, []int64{1, 2, 3}).Return(map[int64]bool{3: true}, nil)
+			mentions.EXPECT().ForMessages([]int64{1, 2, 3}).Return(map[int64][]models.Mention{}, nil)
+			previews.EXPECT().ForMessages([]int64{1, 2, 3}).Return(map[int64][]models.Preview{
+				3: {{URL: "https://example.com", Title: "Example"}},
+			}, nil)
 
 			messages, err := accountService.History(user)
 			require.NoError(t, err)
 			require.Len(t, messages, 3)
+			require.Equal(t, []models.ReactionSummary{{Emoji: "👍", Count: 2, Me: true}}, messages[1].Reactions)
+			require.Equal(t, "<p><strong>hi</strong></p>", messages[1].HTML)
+			require.True(t, messages[0].Poll.Options[0].Me)
+			require.Equal(t, []models.Preview{{URL: "https://example.com", Title: "Example"}}, messages[2].Previews)
+			require.False(t, messages[1].Starred)
+			require.True(t, messages[2].Starred)
+		})
+	})
+
+	t.Run("Messages", func(t *testing.T) {
+		user := models.User{ID: 1, Email: "user@example.com"}
+
+		// attached expects loading of everything shown along with messages
+		attached := func(ids []int64) {
+			reactions.EXPECT().Summaries(user, ids).Return(map[int64][]models.ReactionSummary{}, nil)
+			attachments.EXPECT().ForMessages(ids).Return(map[int64][]models.Attachment{}, nil)
+			polls.EXPECT().ForMessages(user, ids).Return(map[int64]*models.Poll{}, nil)
+			stars.EXPECT().Starred(user, ids).Return(map[int64]bool{}, nil)
+			mentions.EXPECT().ForMessages(ids).Return(map[int64][]models.Mention{}, nil)
+			previews.EXPECT().ForMessages(ids).Return(map[int64][]models.Preview{}, nil)
+		}
+
+		page := func(ids ...int64) []models.Message {
+			messages := make([]models.Message, len(ids))
+			for i, id := range ids {
+				messages[i] = models.Message{Id: id, UserId: 2}
+			}
+
+			return messages
+		}
+
+		t.Run("Foreign conversation", func(t *testing.T) {
+			result, err := accountService.Messages(user, HistoryRequest{Conversation: "dm:2:3", Limit: 2})
+			require.Nil(t, result)
+			require.Equal(t, ErrConversationNotFound, err)
+		})
+
+		t.Run("Latest", func(t *testing.T) {
+			messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, Limit: 3}).Return(page(3, 4, 5), nil)
+			attached([]int64{4, 5})
+
+			result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, Limit: 2})
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 2)
+			require.Equal(t, int64(4), result.Messages[0].Id)
+			require.Equal(t, int64(4), result.Before)
+			require.Zero(t, result.After)
+		})
+
+		t.Run("Before", func(t *testing.T) {
+			messages.EXPECT().History(models.HistoryQuery{Conversation: "dm:1:2", Before: 4, Limit: 3}).Return(page(2, 3), nil)
+			archives.EXPECT().Segments("dm:1:2", time.Time{}, time.Time{}).Return(nil, nil)
+			attached([]int64{2, 3})
+
+			result, err := accountService.Messages(user, HistoryRequest{Conversation: "dm:1:2", Before: 4, Limit: 2})
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 2)
+			require.Zero(t, result.Before)
+			require.Equal(t, int64(3), result.After)
+		})
+
+		t.Run("After", func(t *testing.T) {
+			messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, After: 2, Limit: 3}).Return(page(3, 4, 5), nil)
+			attached([]int64{3, 4})
+
+			result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, After: 2, Limit: 2})
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 2)
+			require.Equal(t, int64(3), result.Before)
+			require.Equal(t, int64(4), result.After)
+		})
+
+		t.Run("Empty", func(t *testing.T) {
+			messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, After: 5, Limit: 3}).Return(nil, nil)
+			messages.EXPECT().Find(int64(5)).Return(&models.Message{Id: 5}, nil)
+
+			result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, After: 5, Limit: 2})
+			require.NoError(t, err)
+			require.Empty(t, result.Messages)
+			require.Zero(t, result.Before)
+			require.Zero(t, result.After)
+		})
+
+		t.Run("Archived", func(t *testing.T) {
+			at := func(month time.Month) time.Time {
+				return time.Date(2026, month, 1, 0, 0, 0, 0, time.UTC)
+			}
+
+			old := []models.Message{
+				{Id: 1, UserId: 2, Text: "first", CreatedAt: at(time.January)},
+				{Id: 2, UserId: 2, Text: "second", CreatedAt: at(time.January).Add(time.Hour)},
+			}
+			hot := models.Message{Id: 3, UserId: 2, Text: "third", CreatedAt: at(time.March)}
+
+			var segment models.ArchiveSegment
+			messages.EXPECT().Archivable(at(time.February), 5000).Return(old, nil)
+			archives.EXPECT().Create(gomock.Any(), []int64{1, 2}).DoAndReturn(func(s *models.ArchiveSegment, ids []int64) (bool, error) {
+				segment = *s
+				return true, nil
+			})
+
+			_, err := archiveService.Archive(at(time.February))
+			require.NoError(t, err)
+
+			author := []models.User{{ID: 2, Email: "author@example.com"}}
+
+			t.Run("Scrolled past table", func(t *testing.T) {
+				messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, Limit: 3}).Return([]models.Message{hot}, nil)
+				archives.EXPECT().Segments(models.PublicConversation, time.Time{}, hot.CreatedAt).Return([]models.ArchiveSegment{segment}, nil)
+				account.EXPECT().FindByIDs([]int64{2}).Return(author, nil)
+				attached([]int64{2, 3})
+
+				result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, Limit: 2})
+				require.NoError(t, err)
+				require.Len(t, result.Messages, 2)
+				require.Equal(t, "second", result.Messages[0].Text)
+				require.Equal(t, "author@example.com", result.Messages[0].User.Email)
+				require.Equal(t, int64(2), result.Before)
+			})
+
+			t.Run("Before archived message", func(t *testing.T) {
+				messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, Before: 2, Limit: 3}).Return(nil, nil)
+				messages.EXPECT().Find(int64(2)).Return(nil, nil)
+				archives.EXPECT().Containing(models.PublicConversation, int64(2)).Return([]models.ArchiveSegment{segment}, nil)
+				archives.EXPECT().Segments(models.PublicConversation, time.Time{}, old[1].CreatedAt).Return([]models.ArchiveSegment{segment}, nil)
+				account.EXPECT().FindByIDs([]int64{2}).Return(author, nil).Times(2)
+				attached([]int64{1})
+
+				result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, Before: 2, Limit: 2})
+				require.NoError(t, err)
+				require.Len(t, result.Messages, 1)
+				require.Equal(t, "first", result.Messages[0].Text)
+				require.Zero(t, result.Before)
+				require.Equal(t, int64(1), result.After)
+			})
+
+			t.Run("After archived message", func(t *testing.T) {
+				after := old[1].Key()
+				messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, After: 1, Limit: 3}).Return(nil, nil)
+				messages.EXPECT().Find(int64(1)).Return(nil, nil)
+				archives.EXPECT().Containing(models.PublicConversation, int64(1)).Return([]models.ArchiveSegment{segment}, nil)
+				archives.EXPECT().Segments(models.PublicConversation, old[0].CreatedAt, time.Time{}).Return([]models.ArchiveSegment{segment}, nil)
+				account.EXPECT().FindByIDs([]int64{2}).Return(author, nil).Times(2)
+				messages.EXPECT().History(models.HistoryQuery{Conversation: models.PublicConversation, AfterKey: &after, Limit: 2}).Return([]models.Message{hot}, nil)
+				attached([]int64{2, 3})
+
+				result, err := accountService.Messages(user, HistoryRequest{Conversation: models.PublicConversation, After: 1, Limit: 2})
+				require.NoError(t, err)
+				require.Len(t, result.Messages, 2)
+				require.Equal(t, "second", result.Messages[0].Text)
+				require.Equal(t, "third", result.Messages[1].Text)
+				require.Equal(t, int64(2), result.Before)
+				require.Zero(t, result.After)
+			})
 		})
 	})
 
+	t.Run("Catch up", func(t *testing.T) {
+		user := models.User{ID: 1, Email: "user@example.com"}
+
+		missed := func(ids ...int64) []models.Message {
+			messages := make([]models.Message, len(ids))
+			for i, id := range ids {
+				messages[i] = models.Message{Id: id, UserId: 2}
+			}
+
+			return messages
+		}
+
+		attached := func(ids []int64) {
+			reactions.EXPECT().Summaries(user, ids).Return(map[int64][]models.ReactionSummary{}, nil)
+			attachments.EXPECT().ForMessages(ids).Return(map[int64][]models.Attachment{}, nil)
+			polls.EXPECT().ForMessages(user, ids).Return(map[int64]*models.Poll{}, nil)
+			stars.EXPECT().Starred(user, ids).Return(map[int64]bool{}, nil)
+			mentions.EXPECT().ForMessages(ids).Return(map[int64][]models.Mention{}, nil)
+			previews.EXPECT().ForMessages(ids).Return(map[int64][]models.Preview{}, nil)
+		}
+
+		t.Run("Nothing missed", func(t *testing.T) {
+			messages.EXPECT().Missed(user, int64(10), 501).Return(nil, nil)
+
+			page, err := accountService.CatchUp(user, 10)
+			require.NoError(t, err)
+			require.Empty(t, page.Messages)
+			require.Zero(t, page.Before)
+		})
+
+		t.Run("Replayed", func(t *testing.T) {
+			messages.EXPECT().Missed(user, int64(10), 501).Return(missed(11, 12), nil)
+			attached([]int64{11, 12})
+
+			page, err := accountService.CatchUp(user, 10)
+			require.NoError(t, err)
+			require.Len(t, page.Messages, 2)
+			require.Zero(t, page.Before)
+		})
+
+		t.Run("Truncated", func(t *testing.T) {
+			config := viper.New()
+			config.Set("history.catch_up", 2)
+
+			catchUp := NewAccount(AccountOptions{
+				Logger:         logger,
+				Config:         config,
+				AccountRepo:    account,
+				MessageRepo:    messages,
+				ReactionRepo:   reactions,
+				AttachmentRepo: attachments,
+				PollRepo:       polls,
+				StarRepo:       stars,
+				ReadMarkerRepo: readMarkers,
+				Renderer:       providers.NewMarkdownRenderer(),
+				MentionService: NewMention(MentionOptions{Logger: logger, AccountRepo: account, MentionRepo: mentions}),
+				PreviewService: NewPreview(PreviewOptions{Logger: logger, Config: viper.New(), PreviewRepo: previews}),
+			})
+
+			messages.EXPECT().Missed(user, int64(10), 3).Return(missed(20, 21, 22), nil)
+			attached([]int64{21, 22})
+
+			page, err := catchUp.CatchUp(user, 10)
+			require.NoError(t, err)
+			require.Len(t, page.Messages, 2)
+			require.Equal(t, int64(21), page.Before)
+		})
+	})
+
+	t.Run("Queued", func(t *testing.T) {
+		user := models.User{ID: 1, Email: "user@example.com"}
+
+		t.Run("Failure", func(t *testing.T) {
+			messages.EXPECT().Undelivered(user, 500).Return(nil, errors.New("db error"))
+
+			queued, err := accountService.Queued(user)
+			require.Nil(t, queued)
+			require.Error(t, err)
+		})
+
+		t.Run("Empty", func(t *testing.T) {
+			messages.EXPECT().Undelivered(user, 500).Return(nil, nil)
+
+			queued, err := accountService.Queued(user)
+			require.NoError(t, err)
+			require.Empty(t, queued)
+		})
+
+		t.Run("Pushed", func(t *testing.T) {
+			ids := []int64{7, 8}
+			messages.EXPECT().Undelivered(user, 500).Return([]models.Message{
+				{Id: 7, UserId: 2, ReceiverId: 1},
+				{Id: 8, UserId: 3, ReceiverId: 1},
+			}, nil)
+			reactions.EXPECT().Summaries(user, ids).Return(map[int64][]models.ReactionSummary{}, nil)
+			attachments.EXPECT().ForMessages(ids).Return(map[int64][]models.Attachment{}, nil)
+			polls.EXPECT().ForMessages(user, ids).Return(map[int64]*models.Poll{}, nil)
+			stars.EXPECT().Starred(user, ids).Return(map[int64]bool{}, nil)
+			mentions.EXPECT().ForMessages(ids).Return(map[int64][]models.Mention{}, nil)
+			previews.EXPECT().ForMessages(ids).Return(map[int64][]models.Preview{}, nil)
+
+			queued, err := accountService.Queued(user)
+			require.NoError(t, err)
+			require.Len(t, queued, 2)
+			require.Empty(t, queued[0].Status)
+		})
+	})
+
+	t.Run("Delivery status", func(t *testing.T) {
+		user := models.User{ID: 1, Email: "user@example.com"}
+		delivered := time.Now()
+		ids := []int64{11, 12, 13, 14, 15}
+
+		messages.EXPECT().Missed(user, int64(10), 501).Return([]models.Message{
+			{Id: 11, UserId: 1, ReceiverId: 2, DeliveredAt: &delivered},
+			{Id: 12, UserId: 1, ReceiverId: 2, DeliveredAt: &delivered},
+			{Id: 13, UserId: 1, ReceiverId: 3},
+			{Id: 14, UserId: 1},
+			{Id: 15, UserId: 2, ReceiverId: 1},
+		}, nil)
+		reactions.EXPECT().Summaries(user, ids).Return(map[int64][]models.ReactionSummary{}, nil)
+		attachments.EXPECT().ForMessages(ids).Return(map[int64][]models.Attachment{}, nil)
+		polls.EXPECT().ForMessages(user, ids).Return(map[int64]*models.Poll{}, nil)
+		stars.EXPECT().Starred(user, ids).Return(map[int64]bool{}, nil)
+		readMarkers.EXPECT().Partners(user, []string{"dm:1:2", "dm:1:3"}).Return(map[string]int64{"dm:1:2": 11}, nil)
+		mentions.EXPECT().ForMessages(ids).Return(map[int64][]models.Mention{}, nil)
+		previews.EXPECT().ForMessages(ids).Return(map[int64][]models.Preview{}, nil)
+
+		page, err := accountService.CatchUp(user, 10)
+		require.NoError(t, err)
+		require.Equal(t, models.StatusRead, page.Messages[0].Status)
+		require.Equal(t, models.StatusDelivered, page.Messages[1].Status)
+		require.Equal(t, models.StatusSent, page.Messages[2].Status)
+		require.Empty(t, page.Messages[3].Status)
+		require.Empty(t, page.Messages[4].Status)
+	})
 }
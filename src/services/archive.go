@@ -0,0 +1,640 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Archive interface {
+		// Archive moves messages created before the time into segments
+		// on blob store and returns number of moved messages
+		Archive(before time.Time) (int, error)
+		// Find returns archived message of conversation, nil when there is no such one
+		Find(conversation string, id int64) (*models.Message, error)
+		// Range returns up to limit archived messages of conversation between optional
+		// exclusive keys, those closest to before or to after when ascending, oldest first
+		Range(conversation string, after, before *models.MessageKey, limit int, ascending bool) ([]models.Message, error)
+		// Export passes archived messages matching query to fn in batches, oldest first
+		Export(query models.ExportQuery, fn func(messages []models.Message) error) error
+		// Prune removes archived messages outside of retention policy and returns
+		// their number, dry run only counts them
+		Prune(policy models.RetentionPolicy, now time.Time, dryRun bool) (int, error)
+	}
+
+	ArchiveOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		Lc          fx.Lifecycle
+		AccountRepo repositories.User
+		MessageRepo repositories.Message
+		ArchiveRepo repositories.Archive
+		Store       providers.BlobStore
+	}
+
+	archiveService struct {
+		logger      *zap.SugaredLogger
+		accountRepo repositories.User
+		messageRepo repositories.Message
+		archiveRepo repositories.Archive
+		store       providers.BlobStore
+		days        int
+		batch       int
+	}
+
+	// archivedMessage is a line of segment, authors are kept by id
+	// since users stay in the database
+	archivedMessage struct {
+		Id          int64              `json:"id"`
+		UserId      int64              `json:"user_id"`
+		ReceiverId  int64              `json:"receiver_id,omitempty"`
+		ClientMsgId string             `json:"client_msg_id,omitempty"`
+		Type        string             `json:"type"`
+		Text        string             `json:"text"`
+		Format      string             `json:"format"`
+		Ref         *models.MessageRef `json:"ref,omitempty"`
+		DeliveredAt *time.Time         `json:"delivered_at,omitempty"`
+		CreatedAt   time.Time          `json:"created_at"`
+		UpdatedAt   time.Time          `json:"updated_at"`
+	}
+
+	// segmentHeap merges messages of segments read for export by their heads
+	segmentHeap [][]models.Message
+)
+
+// NewArchive creates archiver of messages older than archive.days running in background,
+// job is disabled until it is set while archived messages are always readable
+func NewArchive(opts ArchiveOptions) Archive {
+	opts.Config.SetDefault("archive.interval", time.Hour)
+	opts.Config.SetDefault("archive.batch", 5000)
+
+	a := &archiveService{
+		logger:      opts.Logger.Named("archive_service"),
+		accountRepo: opts.AccountRepo,
+		messageRepo: opts.MessageRepo,
+		archiveRepo: opts.ArchiveRepo,
+		store:       opts.Store,
+		days:        opts.Config.GetInt("archive.days"),
+		batch:       opts.Config.GetInt("archive.batch"),
+	}
+
+	if a.days > 0 {
+		startWorker(opts.Lc, opts.Config.GetDuration("archive.interval"), func() bool {
+			if _, err := a.Archive(time.Now().AddDate(0, 0, -a.days)); err != nil {
+				a.logger.Errorf("error archiving messages: %v", err)
+			}
+
+			return false
+		})
+	}
+
+	return a
+}
+
+func (a *archiveService) Archive(before time.Time) (int, error) {
+	total := 0
+	for {
+		messages, err := a.messageRepo.Archivable(before, a.batch)
+		if err != nil {
+			return total, err
+		}
+
+		// Messages are split by conversation and month keeping their order
+		var keys []string
+		groups := make(map[string][]models.Message)
+		for _, message := range messages {
+			key := message.Conversation() + "/" + message.CreatedAt.UTC().Format("2006-01")
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			groups[key] = append(groups[key], message)
+		}
+
+		for _, key := range keys {
+			n, err := a.segment(groups[key])
+			if err != nil {
+				return total, err
+			}
+
+			total += n
+		}
+
+		if len(messages) < a.batch {
+			break
+		}
+	}
+
+	a.logger.Infof("archived messages: %d", total)
+	return total, nil
+}
+
+// segment writes messages of one conversation and month to blob store and removes them from
+// the table, key is derived from messages so instances archiving the same batch meet on it
+func (a *archiveService) segment(messages []models.Message) (int, error) {
+	segment, err := a.write(messages)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	created, err := a.archiveRepo.Create(segment, ids)
+	if err == repositories.ErrArchiveChanged {
+		// Messages are read again on next run, changed ones are held back then
+		a.logger.Infof("skipping archive segment %s: %v", segment.Key, err)
+		a.deleteBlob(segment.Key)
+		return 0, nil
+	}
+
+	if err != nil || !created {
+		return 0, err
+	}
+
+	return len(messages), nil
+}
+
+// write encodes messages of one conversation and month, oldest first, into blob store
+func (a *archiveService) write(messages []models.Message) (*models.ArchiveSegment, error) {
+	first, last := messages[0], messages[len(messages)-1]
+
+	segment := &models.ArchiveSegment{
+		Conversation: first.Conversation(),
+		Month:        time.Date(first.CreatedAt.UTC().Year(), first.CreatedAt.UTC().Month(), 1, 0, 0, 0, 0, time.UTC),
+		Count:        len(messages),
+		MinId:        first.Id,
+		MaxId:        first.Id,
+		FirstAt:      first.CreatedAt,
+		LastAt:       last.CreatedAt,
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+
+	for _, message := range messages {
+		if message.Id < segment.MinId {
+			segment.MinId = message.Id
+		}
+
+		if message.Id > segment.MaxId {
+			segment.MaxId = message.Id
+		}
+
+		if err := encoder.Encode(archivedMessage{
+			Id:          message.Id,
+			UserId:      message.UserId,
+			ReceiverId:  message.ReceiverId,
+			ClientMsgId: message.ClientMsgId,
+			Type:        message.Type,
+			Text:        message.Text,
+			Format:      message.Format,
+			Ref:         message.Ref,
+			DeliveredAt: message.DeliveredAt,
+			CreatedAt:   message.CreatedAt,
+			UpdatedAt:   message.UpdatedAt,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	segment.Key = fmt.Sprintf("archive/%s/%s/%d-%d.ndjson.gz",
+		segment.Conversation, segment.Month.Format("2006-01"), segment.MinId, segment.MaxId)
+
+	if err := a.store.Put(context.Background(), segment.Key, &buf, int64(buf.Len()), "application/gzip"); err != nil {
+		return nil, err
+	}
+
+	return segment, nil
+}
+
+func (a *archiveService) Find(conversation string, id int64) (*models.Message, error) {
+	segments, err := a.archiveRepo.Containing(conversation, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range segments {
+		messages, err := a.read(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, message := range messages {
+			if message.Id != id {
+				continue
+			}
+
+			found := []models.Message{message}
+			if err := a.authors(found, nil); err != nil {
+				return nil, err
+			}
+
+			return &found[0], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Range reads segments starting from the closest one until the rest can not hold closer messages
+func (a *archiveService) Range(conversation string, after, before *models.MessageKey, limit int, ascending bool) ([]models.Message, error) {
+	var from, to time.Time
+	if after != nil {
+		from = after.CreatedAt
+	}
+
+	if before != nil {
+		to = before.CreatedAt
+	}
+
+	segments, err := a.archiveRepo.Segments(conversation, from, to)
+	if err != nil || len(segments) == 0 {
+		return nil, err
+	}
+
+	if !ascending {
+		sort.SliceStable(segments, func(i, j int) bool {
+			return segments[i].LastAt.After(segments[j].LastAt)
+		})
+	}
+
+	var messages []models.Message
+	for _, segment := range segments {
+		if len(messages) == limit {
+			if ascending && segment.FirstAt.After(messages[len(messages)-1].CreatedAt) {
+				break
+			}
+
+			if !ascending && segment.LastAt.Before(messages[0].CreatedAt) {
+				break
+			}
+		}
+
+		read, err := a.read(segment)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, message := range read {
+			if within(message.Key(), after, before) {
+				messages = append(messages, message)
+			}
+		}
+
+		sort.Slice(messages, func(i, j int) bool {
+			return messages[i].Key().Less(messages[j].Key())
+		})
+
+		if len(messages) > limit {
+			if ascending {
+				messages = messages[:limit]
+			} else {
+				messages = messages[len(messages)-limit:]
+			}
+		}
+	}
+
+	if err := a.authors(messages, nil); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Export merges segments which overlap in time, segment is read only once
+// the merge reaches its first message so few of them are in memory at once
+func (a *archiveService) Export(query models.ExportQuery, fn func(messages []models.Message) error) error {
+	segments, err := a.archiveRepo.Segments(query.Conversation, query.Since, query.Until)
+	if err != nil {
+		return err
+	}
+
+	var (
+		since, until *models.MessageKey
+		open         segmentHeap
+		next         int
+	)
+
+	if !query.Since.IsZero() {
+		since = &models.MessageKey{CreatedAt: query.Since}
+	}
+
+	if !query.Until.IsZero() {
+		until = &models.MessageKey{CreatedAt: query.Until}
+	}
+
+	users := make(map[int64]*models.User)
+	batch := make([]models.Message, 0, query.Batch)
+	for {
+		for next < len(segments) && (len(open) == 0 || !segments[next].FirstAt.After(open[0][0].CreatedAt)) {
+			read, err := a.read(segments[next])
+			if err != nil {
+				return err
+			}
+			next++
+
+			var messages []models.Message
+			for _, message := range read {
+				// Since is inclusive unlike key bounds
+				if (since == nil || !message.CreatedAt.Before(query.Since)) && within(message.Key(), nil, until) {
+					messages = append(messages, message)
+				}
+			}
+
+			if len(messages) > 0 {
+				heap.Push(&open, messages)
+			}
+		}
+
+		if len(open) == 0 {
+			break
+		}
+
+		batch = append(batch, open[0][0])
+		if open[0] = open[0][1:]; len(open[0]) == 0 {
+			heap.Pop(&open)
+		} else {
+			heap.Fix(&open, 0)
+		}
+
+		if len(batch) == query.Batch {
+			if err := a.authors(batch, users); err != nil {
+				return err
+			}
+
+			if err := fn(batch); err != nil {
+				return err
+			}
+
+			batch = make([]models.Message, 0, query.Batch)
+		}
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := a.authors(batch, users); err != nil {
+		return err
+	}
+
+	return fn(batch)
+}
+
+// Prune deletes segments holding only messages outside of policy and rewrites those
+// holding some of them, policy must be enabled
+func (a *archiveService) Prune(policy models.RetentionPolicy, now time.Time, dryRun bool) (int, error) {
+	segments, err := a.archiveRepo.Prunable(policy.Scope)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for start, end := 0, 0; start < len(segments); start = end {
+		for end < len(segments) && segments[end].Conversation == segments[start].Conversation {
+			end++
+		}
+
+		n, err := a.prune(policy, now, segments[start:end], dryRun)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// prune goes through segments of one conversation newest first, messages left in the
+// table are newer than archived ones so they take their share of count limit first
+func (a *archiveService) prune(policy models.RetentionPolicy, now time.Time, segments []models.ArchiveSegment, dryRun bool) (int, error) {
+	limited, keep := policy.MaxCount > 0, 0
+	if limited {
+		count, err := a.messageRepo.Count(segments[0].Conversation)
+		if err != nil {
+			return 0, err
+		}
+
+		if keep = policy.MaxCount - count; keep < 0 {
+			keep = 0
+		}
+	}
+
+	var cutoff time.Time
+	if policy.MaxAge > 0 {
+		cutoff = now.Add(-policy.MaxAge)
+	}
+
+	total := 0
+	for _, segment := range segments {
+		if (!limited || segment.Count <= keep) && !segment.FirstAt.Before(cutoff) {
+			keep -= segment.Count
+			continue
+		}
+
+		var kept []models.Message
+		if (!limited || keep > 0) && !segment.LastAt.Before(cutoff) {
+			messages, err := a.read(segment)
+			if err != nil {
+				return total, err
+			}
+
+			from := len(messages)
+			for from > 0 && !messages[from-1].CreatedAt.Before(cutoff) && (!limited || len(messages)-from < keep) {
+				from--
+			}
+
+			kept = messages[from:]
+			keep -= len(kept)
+		}
+
+		if dryRun {
+			total += segment.Count - len(kept)
+			continue
+		}
+
+		n, err := a.rewrite(segment, kept)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// rewrite replaces segment with one holding only kept messages, or deletes it when there
+// are none, and returns number of removed messages, nothing is removed when another
+// instance changed the segment first
+func (a *archiveService) rewrite(segment models.ArchiveSegment, kept []models.Message) (int, error) {
+	if len(kept) == 0 {
+		deleted, err := a.archiveRepo.Delete(segment)
+		if err != nil || !deleted {
+			return 0, err
+		}
+
+		a.deleteBlob(segment.Key)
+		return segment.Count, nil
+	}
+
+	rewritten, err := a.write(kept)
+	if err != nil {
+		return 0, err
+	}
+	rewritten.Id = segment.Id
+
+	replaced, err := a.archiveRepo.Replace(segment.Key, rewritten)
+	if err != nil || !replaced {
+		if rewritten.Key != segment.Key {
+			a.deleteBlob(rewritten.Key)
+		}
+
+		return 0, err
+	}
+
+	if rewritten.Key != segment.Key {
+		a.deleteBlob(segment.Key)
+	}
+
+	return segment.Count - len(kept), nil
+}
+
+// deleteBlob removes blob of segment which is not referenced anymore, failures
+// only leave unreachable blob behind so they are logged
+func (a *archiveService) deleteBlob(key string) {
+	if err := a.store.Delete(context.Background(), key); err != nil {
+		a.logger.Errorf("error deleting archive segment %s: %v", key, err)
+	}
+}
+
+// read loads messages of segment in the order they were written, oldest first
+func (a *archiveService) read(segment models.ArchiveSegment) ([]models.Message, error) {
+	r, err := a.store.Get(context.Background(), segment.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	messages := make([]models.Message, 0, segment.Count)
+	decoder := json.NewDecoder(gz)
+	for {
+		var archived archivedMessage
+		if err := decoder.Decode(&archived); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, models.Message{
+			Id:          archived.Id,
+			UserId:      archived.UserId,
+			ReceiverId:  archived.ReceiverId,
+			ClientMsgId: archived.ClientMsgId,
+			Type:        archived.Type,
+			Text:        archived.Text,
+			Format:      archived.Format,
+			Ref:         archived.Ref,
+			DeliveredAt: archived.DeliveredAt,
+			CreatedAt:   archived.CreatedAt,
+			UpdatedAt:   archived.UpdatedAt,
+		})
+	}
+
+	return messages, nil
+}
+
+// authors loads senders and receivers of archived messages, cache keeps
+// users loaded before when messages are processed in batches
+func (a *archiveService) authors(messages []models.Message, cache map[int64]*models.User) error {
+	if cache == nil {
+		cache = make(map[int64]*models.User)
+	}
+
+	var ids []int64
+	for _, message := range messages {
+		for _, id := range []int64{message.UserId, message.ReceiverId} {
+			if _, ok := cache[id]; id != 0 && !ok {
+				cache[id] = nil
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	if len(ids) > 0 {
+		users, err := a.accountRepo.FindByIDs(ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range users {
+			cache[users[i].ID] = &users[i]
+		}
+	}
+
+	for i, message := range messages {
+		messages[i].User = cache[message.UserId]
+		if message.ReceiverId != 0 {
+			messages[i].Receiver = cache[message.ReceiverId]
+		}
+	}
+
+	return nil
+}
+
+// within tells whether key is between optional exclusive bounds
+func within(key models.MessageKey, after, before *models.MessageKey) bool {
+	return (after == nil || after.Less(key)) && (before == nil || key.Less(*before))
+}
+
+func (h segmentHeap) Len() int {
+	return len(h)
+}
+
+func (h segmentHeap) Less(i, j int) bool {
+	return h[i][0].Key().Less(h[j][0].Key())
+}
+
+func (h segmentHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *segmentHeap) Push(x interface{}) {
+	*h = append(*h, x.([]models.Message))
+}
+
+func (h *segmentHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
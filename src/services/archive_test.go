@@ -0,0 +1,260 @@
+package services_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestArchiveService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := providers.NewLocalBlobStore(dir)
+	require.NoError(t, err)
+
+	accounts := mock_repositories.NewMockUser(ctrl)
+	messages := mock_repositories.NewMockMessage(ctrl)
+	archives := mock_repositories.NewMockArchive(ctrl)
+
+	config := viper.New()
+	config.Set("archive.batch", 10)
+
+	archiveService := services.NewArchive(services.ArchiveOptions{
+		Logger:      zap.NewNop().Sugar(),
+		Config:      config,
+		AccountRepo: accounts,
+		MessageRepo: messages,
+		ArchiveRepo: archives,
+		Store:       store,
+	})
+
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	old := []models.Message{
+		{Id: 1, UserId: 2, Type: models.MessageText, Text: "first", CreatedAt: at(time.January, 10)},
+		{Id: 4, UserId: 1, ReceiverId: 2, Type: models.MessageText, Text: "secret", CreatedAt: at(time.January, 15)},
+		{Id: 2, UserId: 2, Type: models.MessageText, Text: "second", CreatedAt: at(time.January, 20)},
+		{Id: 3, UserId: 1, Type: models.MessageText, Text: "third", CreatedAt: at(time.February, 5)},
+	}
+
+	users := []models.User{{ID: 1, Email: "one@example.com"}, {ID: 2, Email: "two@example.com"}}
+	before := at(time.March, 1)
+
+	var segments []models.ArchiveSegment
+	t.Run("Archive", func(t *testing.T) {
+		messages.EXPECT().Archivable(before, 10).Return(old, nil)
+		archives.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(segment *models.ArchiveSegment, ids []int64) (bool, error) {
+			segment.Id = int64(len(segments) + 1)
+			segments = append(segments, *segment)
+			return true, nil
+		})
+
+		n, err := archiveService.Archive(before)
+		require.NoError(t, err)
+		require.Equal(t, 4, n)
+		require.Len(t, segments, 3)
+
+		require.Equal(t, "archive/public/2026-01/1-2.ndjson.gz", segments[0].Key)
+		require.Equal(t, models.PublicConversation, segments[0].Conversation)
+		require.Equal(t, 2, segments[0].Count)
+		require.Equal(t, at(time.January, 10), segments[0].FirstAt)
+		require.Equal(t, at(time.January, 20), segments[0].LastAt)
+		require.Equal(t, "archive/dm:1:2/2026-01/4-4.ndjson.gz", segments[1].Key)
+		require.Equal(t, "archive/public/2026-02/3-3.ndjson.gz", segments[2].Key)
+	})
+
+	t.Run("Archived by another instance", func(t *testing.T) {
+		messages.EXPECT().Archivable(before, 10).Return(old[3:], nil)
+		archives.EXPECT().Create(gomock.Any(), []int64{3}).Return(false, nil)
+
+		n, err := archiveService.Archive(before)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("Changed meanwhile", func(t *testing.T) {
+		changed := models.Message{Id: 5, UserId: 2, Type: models.MessageText, Text: "reacted", CreatedAt: at(time.January, 25)}
+		messages.EXPECT().Archivable(before, 10).Return([]models.Message{changed}, nil)
+		archives.EXPECT().Create(gomock.Any(), []int64{5}).Return(false, repositories.ErrArchiveChanged)
+
+		n, err := archiveService.Archive(before)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		// blob of segment which was not recorded is removed
+		_, err = store.Get(context.Background(), "archive/public/2026-01/5-5.ndjson.gz")
+		require.Error(t, err)
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		archives.EXPECT().Containing(models.PublicConversation, int64(2)).Return(segments[:1], nil)
+		accounts.EXPECT().FindByIDs([]int64{2}).Return(users[1:], nil)
+
+		message, err := archiveService.Find(models.PublicConversation, 2)
+		require.NoError(t, err)
+		require.Equal(t, "second", message.Text)
+		require.Equal(t, at(time.January, 20), message.CreatedAt)
+		require.Equal(t, "two@example.com", message.User.Email)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		archives.EXPECT().Containing(models.PublicConversation, int64(7)).Return(nil, nil)
+
+		message, err := archiveService.Find(models.PublicConversation, 7)
+		require.NoError(t, err)
+		require.Nil(t, message)
+	})
+
+	// public returns segments of public conversation as repository does, in a new slice
+	public := func() []models.ArchiveSegment {
+		return []models.ArchiveSegment{segments[0], segments[2]}
+	}
+
+	t.Run("Range", func(t *testing.T) {
+		t.Run("Newest", func(t *testing.T) {
+			archives.EXPECT().Segments(models.PublicConversation, time.Time{}, time.Time{}).Return(public(), nil)
+			accounts.EXPECT().FindByIDs(gomock.Any()).Return(users, nil)
+
+			result, err := archiveService.Range(models.PublicConversation, nil, nil, 2, false)
+			require.NoError(t, err)
+			require.Equal(t, []int64{2, 3}, ids(result))
+			require.Equal(t, "one@example.com", result[1].User.Email)
+		})
+
+		t.Run("Before", func(t *testing.T) {
+			key := old[2].Key()
+			archives.EXPECT().Segments(models.PublicConversation, time.Time{}, key.CreatedAt).Return(public()[:1], nil)
+			accounts.EXPECT().FindByIDs([]int64{2}).Return(users[1:], nil)
+
+			result, err := archiveService.Range(models.PublicConversation, nil, &key, 5, false)
+			require.NoError(t, err)
+			require.Equal(t, []int64{1}, ids(result))
+		})
+
+		t.Run("After", func(t *testing.T) {
+			key := old[0].Key()
+			archives.EXPECT().Segments(models.PublicConversation, key.CreatedAt, time.Time{}).Return(public(), nil)
+			accounts.EXPECT().FindByIDs([]int64{2}).Return(users[1:], nil)
+
+			result, err := archiveService.Range(models.PublicConversation, &key, nil, 1, true)
+			require.NoError(t, err)
+			require.Equal(t, []int64{2}, ids(result))
+		})
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		archives.EXPECT().Segments("", time.Time{}, time.Time{}).Return(segments, nil)
+		accounts.EXPECT().FindByIDs([]int64{2, 1}).Return(users, nil)
+
+		var batches [][]int64
+		err := archiveService.Export(models.ExportQuery{Batch: 3}, func(messages []models.Message) error {
+			batches = append(batches, ids(messages))
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, [][]int64{{1, 4, 2}, {3}}, batches)
+	})
+
+	t.Run("Export range", func(t *testing.T) {
+		since, until := at(time.January, 15), at(time.February, 5)
+		archives.EXPECT().Segments(models.PublicConversation, since, until).Return(public(), nil)
+		accounts.EXPECT().FindByIDs([]int64{2}).Return(users[1:], nil)
+
+		var exported []int64
+		err := archiveService.Export(models.ExportQuery{Conversation: models.PublicConversation, Since: since, Until: until, Batch: 10}, func(messages []models.Message) error {
+			exported = append(exported, ids(messages)...)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int64{2}, exported)
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		newest := []models.ArchiveSegment{segments[2], segments[0]}
+
+		t.Run("Dry run", func(t *testing.T) {
+			// one message left in the table and the newest archived one are kept
+			policy := models.RetentionPolicy{Scope: models.RetentionPublic, MaxCount: 2}
+			archives.EXPECT().Prunable(models.RetentionPublic).Return(newest, nil)
+			messages.EXPECT().Count(models.PublicConversation).Return(1, nil)
+
+			n, err := archiveService.Prune(policy, time.Now(), true)
+			require.NoError(t, err)
+			require.Equal(t, 2, n)
+		})
+
+		t.Run("Rewrite", func(t *testing.T) {
+			policy := models.RetentionPolicy{Scope: models.RetentionPublic, MaxAge: 720 * time.Hour}
+			archives.EXPECT().Prunable(models.RetentionPublic).Return(newest, nil)
+			archives.EXPECT().Replace(segments[0].Key, gomock.Any()).DoAndReturn(func(key string, segment *models.ArchiveSegment) (bool, error) {
+				require.Equal(t, segments[0].Id, segment.Id)
+				require.Equal(t, "archive/public/2026-01/2-2.ndjson.gz", segment.Key)
+				require.Equal(t, 1, segment.Count)
+				require.Equal(t, at(time.January, 20), segment.FirstAt)
+				return true, nil
+			})
+
+			n, err := archiveService.Prune(policy, at(time.January, 15).Add(720*time.Hour), false)
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+
+			_, err = store.Get(context.Background(), segments[0].Key)
+			require.Error(t, err)
+
+			r, err := store.Get(context.Background(), "archive/public/2026-01/2-2.ndjson.gz")
+			require.NoError(t, err)
+			r.Close()
+		})
+
+		t.Run("Changed by another instance", func(t *testing.T) {
+			policy := models.RetentionPolicy{Scope: models.RetentionPrivate, MaxAge: 720 * time.Hour}
+			archives.EXPECT().Prunable(models.RetentionPrivate).Return(segments[1:2], nil)
+			archives.EXPECT().Delete(segments[1]).Return(false, nil)
+
+			n, err := archiveService.Prune(policy, at(time.February, 1).Add(720*time.Hour), false)
+			require.NoError(t, err)
+			require.Zero(t, n)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			policy := models.RetentionPolicy{Scope: models.RetentionPrivate, MaxAge: 720 * time.Hour}
+			archives.EXPECT().Prunable(models.RetentionPrivate).Return(segments[1:2], nil)
+			archives.EXPECT().Delete(segments[1]).Return(true, nil)
+
+			n, err := archiveService.Prune(policy, at(time.February, 1).Add(720*time.Hour), false)
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+
+			_, err = store.Get(context.Background(), segments[1].Key)
+			require.Error(t, err)
+		})
+	})
+}
+
+func ids(messages []models.Message) []int64 {
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	return ids
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Attachment interface {
+		Upload(user models.User, name string, size int64, r io.Reader) (*models.Attachment, error)
+		URL(user models.User, id int64) (string, time.Time, error)
+		Open(id, expires int64, signature string) (*models.Attachment, io.ReadCloser, error)
+	}
+
+	AttachmentOptions struct {
+		fx.In
+
+		Logger         *zap.SugaredLogger
+		Config         *viper.Viper
+		AttachmentRepo repositories.Attachment
+		MessageRepo    repositories.Message
+		Store          providers.BlobStore
+	}
+
+	attachmentService struct {
+		logger         *zap.SugaredLogger
+		attachmentRepo repositories.Attachment
+		messageRepo    repositories.Message
+		store          providers.BlobStore
+
+		maxSize int64
+		urlTTL  time.Duration
+		baseURL string
+		secret  []byte
+	}
+)
+
+const (
+	sniffLength       = 512
+	maxAttachmentName = 255
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrLinkExpired        = errors.New("link is expired or malformed")
+)
+
+func NewAttachment(opts AttachmentOptions) (Attachment, error) {
+	opts.Config.SetDefault("attachments.max_size", 10<<20)
+	opts.Config.SetDefault("attachments.url_ttl", 5*time.Minute)
+
+	logger := opts.Logger.Named("attachment_service")
+
+	secret := opts.Config.GetString("attachments.secret")
+	if secret == "" {
+		logger.Warnf("attachments.secret is not set, download links will not survive restart")
+
+		var err error
+		if secret, err = randomHex(32); err != nil {
+			return nil, err
+		}
+	}
+
+	return &attachmentService{
+		logger:         logger,
+		attachmentRepo: opts.AttachmentRepo,
+		messageRepo:    opts.MessageRepo,
+		store:          opts.Store,
+		maxSize:        opts.Config.GetInt64("attachments.max_size"),
+		urlTTL:         opts.Config.GetDuration("attachments.url_ttl"),
+		baseURL:        strings.TrimRight(opts.Config.GetString("attachments.base_url"), "/"),
+		secret:         []byte(secret),
+	}, nil
+}
+
+// Upload stores file in blob store, content type is sniffed from the content
+// and never taken from the client
+func (a *attachmentService) Upload(user models.User, name string, size int64, r io.Reader) (*models.Attachment, error) {
+	if size > a.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	// Keys are unguessable since blob store may serve them without signed links
+	blob, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		UserId:      user.ID,
+		Name:        sanitizeName(name),
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+		Key:         fmt.Sprintf("attachments/%d/%s", user.ID, blob),
+		CreatedAt:   time.Now(),
+	}
+
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), size)
+	if err := a.store.Put(context.Background(), attachment.Key, content, size, attachment.ContentType); err != nil {
+		return nil, err
+	}
+
+	if err := a.attachmentRepo.Create(attachment); err != nil {
+		if err := a.store.Delete(context.Background(), attachment.Key); err != nil {
+			a.logger.Errorf("error deleting orphan blob %s: %v", attachment.Key, err)
+		}
+
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// URL returns signed download link of attachment if user can see it
+func (a *attachmentService) URL(user models.User, id int64) (string, time.Time, error) {
+	attachment, err := a.attachmentRepo.Find(id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if attachment == nil {
+		return "", time.Time{}, ErrAttachmentNotFound
+	}
+
+	if attachment.MessageId == 0 && attachment.UserId != user.ID {
+		return "", time.Time{}, ErrAttachmentNotFound
+	}
+
+	if attachment.MessageId != 0 {
+		message, err := a.messageRepo.Find(attachment.MessageId)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+
+		if message == nil || !message.VisibleTo(user) {
+			return "", time.Time{}, ErrAttachmentNotFound
+		}
+	}
+
+	expires := time.Now().Add(a.urlTTL)
+	url := fmt.Sprintf("%s/attachments/%d/download?expires=%d&signature=%s",
+		a.baseURL, attachment.Id, expires.Unix(), a.sign(attachment.Id, expires.Unix()))
+
+	return url, expires, nil
+}
+
+// Open checks link signature and returns attachment content
+func (a *attachmentService) Open(id, expires int64, signature string) (*models.Attachment, io.ReadCloser, error) {
+	expected := a.sign(id, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) || time.Now().Unix() > expires {
+		return nil, nil, ErrLinkExpired
+	}
+
+	attachment, err := a.attachmentRepo.Find(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	content, err := a.store.Get(context.Background(), attachment.Key)
+	if err != nil {
+		if err == providers.ErrBlobNotFound {
+			return nil, nil, ErrAttachmentNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (a *attachmentService) sign(id, expires int64) string {
+	h := hmac.New(sha256.New, a.secret)
+	fmt.Fprintf(h, "%d:%d", id, expires)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sanitizeName keeps only base name of uploaded file without control characters
+func sanitizeName(name string) string {
+	name = path.Base(strings.Replace(name, "\\", "/", -1))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+
+	if len(name) > maxAttachmentName {
+		cut := maxAttachmentName
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+
+	return name
+}
+
+// randomHex returns n bytes read from crypto/rand encoded as hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_providers "github.com/playneta/go-sessions/src/providers/mocks"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := map[string]string{
+		"photo.png":              "photo.png",
+		"../../etc/passwd":       "passwd",
+		`C:\Users\me\report.pdf`: "report.pdf",
+		"bad\x00name\n.txt":      "badname.txt",
+		"":                       "file",
+		strings.Repeat("я", 200): strings.Repeat("я", 127),
+	}
+
+	for name, expected := range tests {
+		require.Equal(t, expected, sanitizeName(name), name)
+	}
+}
+
+func TestAttachmentService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	attachments := mock_repositories.NewMockAttachment(ctrl)
+	messages := mock_repositories.NewMockMessage(ctrl)
+	store := mock_providers.NewMockBlobStore(ctrl)
+
+	config := viper.New()
+	config.Set("attachments.max_size", 1024)
+	config.Set("attachments.secret", "secret")
+
+	service, err := NewAttachment(AttachmentOptions{
+		Logger:         zap.NewNop().Sugar(),
+		Config:         config,
+		AttachmentRepo: attachments,
+		MessageRepo:    messages,
+		Store:          store,
+	})
+	require.NoError(t, err)
+
+	user := models.User{ID: 1}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...)
+
+	t.Run("Upload", func(t *testing.T) {
+		t.Run("Too large", func(t *testing.T) {
+			attachment, err := service.Upload(user, "big.bin", 2048, bytes.NewReader(nil))
+			require.Nil(t, attachment)
+			require.Equal(t, ErrAttachmentTooLarge, err)
+		})
+
+		t.Run("Database error removes blob", func(t *testing.T) {
+			store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(png)), "image/png").Return(nil)
+			attachments.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
+			store.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+
+			attachment, err := service.Upload(user, "photo.png", int64(len(png)), bytes.NewReader(png))
+			require.Nil(t, attachment)
+			require.Error(t, err)
+		})
+
+		t.Run("Content type is sniffed", func(t *testing.T) {
+			var stored []byte
+			store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(png)), "image/png").
+				DoAndReturn(func(_ context.Context, _ string, r io.Reader, _ int64, _ string) error {
+					stored, _ = ioutil.ReadAll(r)
+					return nil
+				})
+			attachments.EXPECT().Create(gomock.Any()).Return(nil)
+
+			attachment, err := service.Upload(user, "photo.html", int64(len(png)), bytes.NewReader(png))
+			require.NoError(t, err)
+			require.Equal(t, "image/png", attachment.ContentType)
+			require.Equal(t, "photo.html", attachment.Name)
+			require.True(t, strings.HasPrefix(attachment.Key, "attachments/1/"))
+			require.Len(t, strings.TrimPrefix(attachment.Key, "attachments/1/"), 32)
+			require.Equal(t, png, stored)
+		})
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		t.Run("Foreign unlinked attachment", func(t *testing.T) {
+			attachments.EXPECT().Find(int64(5)).Return(&models.Attachment{Id: 5, UserId: 2}, nil)
+
+			_, _, err := service.URL(user, 5)
+			require.Equal(t, ErrAttachmentNotFound, err)
+		})
+
+		t.Run("Attachment of foreign private message", func(t *testing.T) {
+			attachments.EXPECT().Find(int64(5)).Return(&models.Attachment{Id: 5, UserId: 2, MessageId: 10}, nil)
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2, ReceiverId: 3}, nil)
+
+			_, _, err := service.URL(user, 5)
+			require.Equal(t, ErrAttachmentNotFound, err)
+		})
+
+		t.Run("Signed link opens attachment", func(t *testing.T) {
+			attachment := &models.Attachment{Id: 5, UserId: 2, MessageId: 10, Key: "attachments/2/key"}
+			attachments.EXPECT().Find(int64(5)).Return(attachment, nil).Times(2)
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2}, nil)
+			store.EXPECT().Get(gomock.Any(), "attachments/2/key").Return(ioutil.NopCloser(bytes.NewReader(png)), nil)
+
+			link, _, err := service.URL(user, 5)
+			require.NoError(t, err)
+
+			u, err := url.Parse(link)
+			require.NoError(t, err)
+			require.Equal(t, "/attachments/5/download", u.Path)
+
+			expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+			require.NoError(t, err)
+
+			_, _, err = service.Open(5, expires+1, u.Query().Get("signature"))
+			require.Equal(t, ErrLinkExpired, err)
+
+			_, _, err = service.Open(5, 1, service.(*attachmentService).sign(5, 1))
+			require.Equal(t, ErrLinkExpired, err)
+
+			opened, content, err := service.Open(5, expires, u.Query().Get("signature"))
+			require.NoError(t, err)
+			require.Equal(t, attachment, opened)
+			require.NoError(t, content.Close())
+		})
+	})
+}
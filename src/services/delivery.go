@@ -0,0 +1,72 @@
+package services
+
+import (
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Delivery interface {
+		Delivered(receiver models.User, messages []models.Message) error
+	}
+
+	DeliveryOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		MessageRepo repositories.Message
+		Notifier    Notifier
+	}
+
+	deliveryService struct {
+		logger      *zap.SugaredLogger
+		messageRepo repositories.Message
+		notifier    Notifier
+	}
+)
+
+func NewDelivery(opts DeliveryOptions) Delivery {
+	return &deliveryService{
+		logger:      opts.Logger.Named("delivery_service"),
+		messageRepo: opts.MessageRepo,
+		notifier:    opts.Notifier,
+	}
+}
+
+// Delivered records that messages were pushed to a device of receiver, authors of
+// private messages delivered for the first time are notified, other messages are skipped
+func (d *deliveryService) Delivered(receiver models.User, messages []models.Message) error {
+	pending := make(map[int64]models.Message)
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		if message.ReceiverId != receiver.ID || message.UserId == receiver.ID || message.DeliveredAt != nil {
+			continue
+		}
+
+		pending[message.Id] = message
+		ids = append(ids, message.Id)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	delivered, err := d.messageRepo.MarkDelivered(ids, now)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range delivered {
+		message := pending[id]
+		message.DeliveredAt = &now
+		message.Status = models.StatusDelivered
+		d.notifier.Delivered(message)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeliveryService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	deliveryService := services.NewDelivery(services.DeliveryOptions{
+		Logger:      zap.NewNop().Sugar(),
+		MessageRepo: messages,
+		Notifier:    notifier,
+	})
+
+	receiver := models.User{ID: 1, Email: "user@example.com"}
+	delivered := time.Now()
+
+	t.Run("Nothing to deliver", func(t *testing.T) {
+		err := deliveryService.Delivered(receiver, []models.Message{
+			{Id: 1, UserId: 2},
+			{Id: 2, UserId: 1, ReceiverId: 2},
+			{Id: 3, UserId: 1, ReceiverId: 1},
+			{Id: 4, UserId: 2, ReceiverId: 1, DeliveredAt: &delivered},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		messages.EXPECT().MarkDelivered([]int64{5}, gomock.Any()).Return(nil, errors.New("db error"))
+
+		err := deliveryService.Delivered(receiver, []models.Message{{Id: 5, UserId: 2, ReceiverId: 1}})
+		require.Error(t, err)
+	})
+
+	t.Run("Delivered", func(t *testing.T) {
+		// Message 7 was delivered to another device meanwhile
+		messages.EXPECT().MarkDelivered([]int64{6, 7}, gomock.Any()).Return([]int64{6}, nil)
+		notifier.EXPECT().Delivered(gomock.Any()).Do(func(message models.Message) {
+			require.Equal(t, int64(6), message.Id)
+			require.NotNil(t, message.DeliveredAt)
+			require.Equal(t, models.StatusDelivered, message.Status)
+		})
+
+		err := deliveryService.Delivered(receiver, []models.Message{
+			{Id: 1, UserId: 2},
+			{Id: 6, UserId: 2, ReceiverId: 1},
+			{Id: 7, UserId: 3, ReceiverId: 1},
+		})
+		require.NoError(t, err)
+	})
+}
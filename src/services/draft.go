@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Draft interface {
+		// Save replaces draft of conversation and pushes it to every device of user
+		Save(user models.User, conversation, text string) (*models.Draft, error)
+		Find(user models.User, conversation string) (*models.Draft, error)
+		// Delete removes draft of conversation, devices of user get it cleared
+		Delete(user models.User, conversation string) error
+	}
+
+	DraftOptions struct {
+		fx.In
+
+		Logger    *zap.SugaredLogger
+		Config    *viper.Viper
+		DraftRepo repositories.Draft
+		Notifier  Notifier
+	}
+
+	draftService struct {
+		logger    *zap.SugaredLogger
+		draftRepo repositories.Draft
+		notifier  Notifier
+		maxLength int
+	}
+)
+
+var (
+	ErrDraftNotFound  = errors.New("draft not found")
+	ErrDraftTooLong   = errors.New("draft is too long")
+	ErrMalformedDraft = errors.New("draft text is not valid utf-8")
+)
+
+func NewDraft(opts DraftOptions) Draft {
+	opts.Config.SetDefault("drafts.max_length", 16384)
+
+	return &draftService{
+		logger:    opts.Logger.Named("draft_service"),
+		draftRepo: opts.DraftRepo,
+		notifier:  opts.Notifier,
+		maxLength: opts.Config.GetInt("drafts.max_length"),
+	}
+}
+
+// Save does not accept empty text, clients delete draft once input is cleared
+func (d *draftService) Save(user models.User, conversation, text string) (*models.Draft, error) {
+	if !models.ConversationVisibleTo(conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+
+	if !utf8.ValidString(text) {
+		return nil, ErrMalformedDraft
+	}
+
+	if len(text) > d.maxLength {
+		return nil, ErrDraftTooLong
+	}
+
+	draft := &models.Draft{
+		UserId:       user.ID,
+		Conversation: conversation,
+		Text:         text,
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := d.draftRepo.Save(draft); err != nil {
+		return nil, err
+	}
+
+	d.notifier.DraftUpdated(user, *draft)
+	return draft, nil
+}
+
+func (d *draftService) Find(user models.User, conversation string) (*models.Draft, error) {
+	if !models.ConversationVisibleTo(conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	draft, err := d.draftRepo.Find(user.ID, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	if draft == nil {
+		return nil, ErrDraftNotFound
+	}
+
+	return draft, nil
+}
+
+// Delete notifies devices only when there was a draft, so clearing it
+// after every sent message does not flood them with events
+func (d *draftService) Delete(user models.User, conversation string) error {
+	if !models.ConversationVisibleTo(conversation, user) {
+		return ErrConversationNotFound
+	}
+
+	deleted, err := d.draftRepo.Delete(user.ID, conversation)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrDraftNotFound
+	}
+
+	d.notifier.DraftUpdated(user, models.Draft{
+		UserId:       user.ID,
+		Conversation: conversation,
+		UpdatedAt:    time.Now(),
+	})
+	return nil
+}
@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDraftService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	drafts := mock_repositories.NewMockDraft(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("drafts.max_length", 10)
+
+	draftService := services.NewDraft(services.DraftOptions{
+		Logger:    zap.NewNop().Sugar(),
+		Config:    config,
+		DraftRepo: drafts,
+		Notifier:  notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("Foreign conversation", func(t *testing.T) {
+			draft, err := draftService.Save(user, "dm:2:3", "hi")
+			require.Nil(t, draft)
+			require.Equal(t, services.ErrConversationNotFound, err)
+		})
+
+		t.Run("Empty", func(t *testing.T) {
+			_, err := draftService.Save(user, models.PublicConversation, "")
+			require.Equal(t, services.ErrEmptyText, err)
+		})
+
+		t.Run("Malformed", func(t *testing.T) {
+			_, err := draftService.Save(user, models.PublicConversation, "\xff")
+			require.Equal(t, services.ErrMalformedDraft, err)
+		})
+
+		t.Run("Too long", func(t *testing.T) {
+			_, err := draftService.Save(user, models.PublicConversation, strings.Repeat("a", 11))
+			require.Equal(t, services.ErrDraftTooLong, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			drafts.EXPECT().Save(gomock.Any()).Return(nil)
+			notifier.EXPECT().DraftUpdated(user, gomock.Any()).Do(func(user models.User, draft models.Draft) {
+				require.Equal(t, "dm:1:2", draft.Conversation)
+				require.Equal(t, "hi", draft.Text)
+			})
+
+			draft, err := draftService.Save(user, "dm:1:2", "hi")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), draft.UserId)
+			require.False(t, draft.UpdatedAt.IsZero())
+		})
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		t.Run("Not found", func(t *testing.T) {
+			drafts.EXPECT().Find(int64(1), models.PublicConversation).Return(nil, nil)
+
+			draft, err := draftService.Find(user, models.PublicConversation)
+			require.Nil(t, draft)
+			require.Equal(t, services.ErrDraftNotFound, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			drafts.EXPECT().Find(int64(1), models.PublicConversation).Return(&models.Draft{UserId: 1, Conversation: models.PublicConversation, Text: "hi"}, nil)
+
+			draft, err := draftService.Find(user, models.PublicConversation)
+			require.NoError(t, err)
+			require.Equal(t, "hi", draft.Text)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Not found", func(t *testing.T) {
+			drafts.EXPECT().Delete(int64(1), models.PublicConversation).Return(false, nil)
+
+			err := draftService.Delete(user, models.PublicConversation)
+			require.Equal(t, services.ErrDraftNotFound, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			drafts.EXPECT().Delete(int64(1), models.PublicConversation).Return(true, nil)
+			notifier.EXPECT().DraftUpdated(user, gomock.Any()).Do(func(user models.User, draft models.Draft) {
+				require.Equal(t, models.PublicConversation, draft.Conversation)
+				require.Empty(t, draft.Text)
+			})
+
+			require.NoError(t, draftService.Delete(user, models.PublicConversation))
+		})
+	})
+}
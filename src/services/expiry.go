@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Expiry interface {
+		// Reap deletes expired messages with their attachments and
+		// returns number of deleted messages
+		Reap() (int, error)
+	}
+
+	ExpiryOptions struct {
+		fx.In
+
+		Logger         *zap.SugaredLogger
+		Config         *viper.Viper
+		Lc             fx.Lifecycle
+		MessageRepo    repositories.Message
+		AttachmentRepo repositories.Attachment
+		Store          providers.BlobStore
+		Notifier       Notifier
+	}
+
+	expiryService struct {
+		logger         *zap.SugaredLogger
+		messageRepo    repositories.Message
+		attachmentRepo repositories.Attachment
+		store          providers.BlobStore
+		notifier       Notifier
+		batch          int
+	}
+)
+
+// NewExpiry creates reaper of self-destructing messages running in background
+func NewExpiry(opts ExpiryOptions) Expiry {
+	opts.Config.SetDefault("expiry.interval", 10*time.Second)
+	opts.Config.SetDefault("expiry.batch", 100)
+
+	e := &expiryService{
+		logger:         opts.Logger.Named("expiry_service"),
+		messageRepo:    opts.MessageRepo,
+		attachmentRepo: opts.AttachmentRepo,
+		store:          opts.Store,
+		notifier:       opts.Notifier,
+		batch:          opts.Config.GetInt("expiry.batch"),
+	}
+
+	startWorker(opts.Lc, opts.Config.GetDuration("expiry.interval"), func() bool {
+		n, err := e.Reap()
+		if err != nil {
+			e.logger.Errorf("error reaping expired messages: %v", err)
+			return false
+		}
+
+		return n >= e.batch
+	})
+
+	return e
+}
+
+func (e *expiryService) Reap() (int, error) {
+	messages, err := e.messageRepo.Expired(time.Now(), e.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	attachments, err := e.attachmentRepo.ForMessages(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	// other instances could reap the same messages,
+	// only the one which deleted them notifies clients
+	deleted, err := e.messageRepo.Delete(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[int64]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.Id] = message
+	}
+
+	for _, id := range deleted {
+		e.notifier.MessageExpired(byID[id])
+
+		for _, attachment := range attachments[id] {
+			if err := e.store.Delete(context.Background(), attachment.Key); err != nil {
+				e.logger.Errorf("error deleting blob %s of expired message: %v", attachment.Key, err)
+			}
+		}
+	}
+
+	return len(deleted), nil
+}
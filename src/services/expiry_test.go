@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_providers "github.com/playneta/go-sessions/src/providers/mocks"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestExpiryService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	attachments := mock_repositories.NewMockAttachment(ctrl)
+	store := mock_providers.NewMockBlobStore(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("expiry.batch", 10)
+
+	expiryService := services.NewExpiry(services.ExpiryOptions{
+		Logger:         zap.NewNop().Sugar(),
+		Config:         config,
+		MessageRepo:    messages,
+		AttachmentRepo: attachments,
+		Store:          store,
+		Notifier:       notifier,
+	})
+
+	t.Run("Nothing expired", func(t *testing.T) {
+		messages.EXPECT().Expired(gomock.Any(), 10).Return([]models.Message{}, nil)
+
+		n, err := expiryService.Reap()
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		messages.EXPECT().Expired(gomock.Any(), 10).Return(nil, errors.New("error"))
+
+		_, err := expiryService.Reap()
+		require.Error(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		expired := []models.Message{{Id: 1, UserId: 1}, {Id: 2, UserId: 1, ReceiverId: 2}}
+
+		messages.EXPECT().Expired(gomock.Any(), 10).Return(expired, nil)
+		attachments.EXPECT().ForMessages([]int64{1, 2}).Return(map[int64][]models.Attachment{
+			1: {{Id: 5, MessageId: 1, Key: "attachments/1/a"}},
+			2: {{Id: 6, MessageId: 2, Key: "attachments/1/b"}},
+		}, nil)
+
+		// message 1 was reaped by another instance in the meantime
+		messages.EXPECT().Delete([]int64{1, 2}).Return([]int64{2}, nil)
+		notifier.EXPECT().MessageExpired(expired[1])
+		store.EXPECT().Delete(context.Background(), "attachments/1/b").Return(nil)
+
+		n, err := expiryService.Reap()
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Export interface {
+		// Export writes transcript of conversation visible to user,
+		// only admins may export any conversation or all of them at once
+		Export(user models.User, req ExportRequest, w io.Writer) error
+		// Transcript writes transcript without access checks, it is used by operators
+		Transcript(req ExportRequest, w io.Writer) error
+	}
+
+	ExportOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		MessageRepo repositories.Message
+		Renderer    providers.Renderer
+
+		ArchiveService Archive
+	}
+
+	// ExportRequest selects messages of conversation, of every conversation when it is
+	// empty, created within [Since, Until), zero bounds are open
+	ExportRequest struct {
+		Conversation string
+		Format       string
+		Since        time.Time
+		Until        time.Time
+	}
+
+	// ExportFormat tells how transcript of a format is served
+	ExportFormat struct {
+		ContentType string
+		Extension   string
+	}
+
+	exportService struct {
+		logger      *zap.SugaredLogger
+		messageRepo repositories.Message
+		renderer    providers.Renderer
+		archive     Archive
+		batch       int
+	}
+
+	// messageStream lets archived messages be merged with those read
+	// from table by repository which pushes them in batches
+	messageStream struct {
+		batches chan []models.Message
+		errc    chan error
+		done    chan struct{}
+		pending []models.Message
+		ended   bool
+		err     error
+	}
+)
+
+// Transcript formats, ndjson is used when format is not set
+const (
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+	ExportText   = "text"
+	ExportHTML   = "html"
+)
+
+var ExportFormats = map[string]ExportFormat{
+	ExportNDJSON: {ContentType: "application/x-ndjson", Extension: "ndjson"},
+	ExportCSV:    {ContentType: "text/csv; charset=utf-8", Extension: "csv"},
+	ExportText:   {ContentType: "text/plain; charset=utf-8", Extension: "txt"},
+	ExportHTML:   {ContentType: "text/html; charset=utf-8", Extension: "html"},
+}
+
+var (
+	ErrMalformedExportFormat = errors.New("export format must be one of ndjson, csv, text or html")
+	ErrMalformedExportRange  = errors.New("export range must end after it starts")
+	ErrExportNotAllowed      = errors.New("only admins can export every conversation")
+)
+
+func NewExport(opts ExportOptions) Export {
+	opts.Config.SetDefault("export.batch", 500)
+
+	return &exportService{
+		logger:      opts.Logger.Named("export_service"),
+		messageRepo: opts.MessageRepo,
+		renderer:    opts.Renderer,
+		archive:     opts.ArchiveService,
+		batch:       opts.Config.GetInt("export.batch"),
+	}
+}
+
+func (e *exportService) Export(user models.User, req ExportRequest, w io.Writer) error {
+	if user.Role != models.RoleAdmin {
+		if req.Conversation == "" {
+			return ErrExportNotAllowed
+		}
+
+		if !models.ConversationVisibleTo(req.Conversation, user) {
+			return ErrConversationNotFound
+		}
+	}
+
+	return e.Transcript(req, w)
+}
+
+// Transcript validates request before writing anything so errors can still be reported,
+// failures while streaming leave transcript cut short
+func (e *exportService) Transcript(req ExportRequest, w io.Writer) error {
+	if req.Format == "" {
+		req.Format = ExportNDJSON
+	}
+
+	if _, ok := ExportFormats[req.Format]; !ok {
+		return ErrMalformedExportFormat
+	}
+
+	if !req.Since.IsZero() && !req.Until.IsZero() && !req.Until.After(req.Since) {
+		return ErrMalformedExportRange
+	}
+
+	if req.Conversation != "" {
+		if _, _, err := models.ParseConversation(req.Conversation); err != nil {
+			return ErrConversationNotFound
+		}
+	}
+
+	t := newTranscript(req.Format, w, e.renderer)
+	if err := t.begin(); err != nil {
+		return err
+	}
+
+	query := models.ExportQuery{
+		Conversation: req.Conversation,
+		Since:        req.Since,
+		Until:        req.Until,
+		Batch:        e.batch,
+	}
+
+	stream := newMessageStream(e.messageRepo, query)
+	defer stream.close()
+
+	// Archived messages of conversation are older than those in table,
+	// still merging keeps order of transcript of every conversation
+	if err := e.archive.Export(query, func(messages []models.Message) error {
+		for _, message := range messages {
+			if err := stream.writeBefore(&message, t.write); err != nil {
+				return err
+			}
+
+			if err := t.write(message); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := stream.writeBefore(nil, t.write); err != nil {
+		return err
+	}
+
+	return t.end()
+}
+
+var errStreamClosed = errors.New("message stream is closed")
+
+func newMessageStream(repo repositories.Message, query models.ExportQuery) *messageStream {
+	s := &messageStream{
+		batches: make(chan []models.Message),
+		errc:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		s.errc <- repo.Export(query, func(messages []models.Message) error {
+			select {
+			case s.batches <- messages:
+				return nil
+			case <-s.done:
+				return errStreamClosed
+			}
+		})
+		close(s.batches)
+	}()
+
+	return s
+}
+
+// writeBefore passes messages older than message to write, all of them when it is nil
+func (s *messageStream) writeBefore(message *models.Message, write func(message models.Message) error) error {
+	for {
+		if len(s.pending) == 0 {
+			if s.ended {
+				return s.err
+			}
+
+			batch, ok := <-s.batches
+			if !ok {
+				s.ended, s.err = true, <-s.errc
+				continue
+			}
+
+			s.pending = batch
+			continue
+		}
+
+		if message != nil && !s.pending[0].Key().Less(message.Key()) {
+			return nil
+		}
+
+		if err := write(s.pending[0]); err != nil {
+			return err
+		}
+
+		s.pending = s.pending[1:]
+	}
+}
+
+// close stops reading table when transcript is cut short and waits for reading to finish
+func (s *messageStream) close() {
+	close(s.done)
+	for range s.batches {
+	}
+}
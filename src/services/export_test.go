@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestExportService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	archive := mock_services.NewMockArchive(ctrl)
+
+	config := viper.New()
+	config.Set("export.batch", 2)
+
+	exportService := services.NewExport(services.ExportOptions{
+		Logger:      zap.NewNop().Sugar(),
+		Config:      config,
+		MessageRepo: messages,
+		Renderer:    providers.NewMarkdownRenderer(),
+
+		ArchiveService: archive,
+	})
+
+	user := models.User{ID: 1, Email: "alice@example.com", Nickname: "alice"}
+	admin := models.User{ID: 3, Email: "admin@example.com", Role: models.RoleAdmin}
+	bob := &models.User{ID: 2, Email: "bob@example.com"}
+	ts := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	// exported expects transcript of conversation read in two batches
+	exported := func(conversation string) {
+		archive.EXPECT().Export(models.ExportQuery{Conversation: conversation, Batch: 2}, gomock.Any()).Return(nil)
+		messages.EXPECT().Export(models.ExportQuery{Conversation: conversation, Batch: 2}, gomock.Any()).
+			DoAndReturn(func(query models.ExportQuery, fn func([]models.Message) error) error {
+				if err := fn([]models.Message{
+					{Id: 1, UserId: 1, User: &user, Type: models.MessageText, Text: "<b>hi</b>\nthere", Format: models.FormatPlain, CreatedAt: ts},
+					{Id: 2, UserId: 2, ReceiverId: 1, User: bob, Receiver: &user, Type: models.MessageText, Text: "**yo**", Format: models.FormatMarkdown, CreatedAt: ts.Add(time.Minute)},
+				}); err != nil {
+					return err
+				}
+
+				return fn([]models.Message{
+					{Id: 3, UserId: 1, User: &user, Type: models.MessageAction, Text: "waves", Format: models.FormatPlain, CreatedAt: ts.Add(2 * time.Minute)},
+				})
+			})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Run("Everything by user", func(t *testing.T) {
+			err := exportService.Export(user, services.ExportRequest{}, &bytes.Buffer{})
+			require.Equal(t, services.ErrExportNotAllowed, err)
+		})
+
+		t.Run("Archive failure", func(t *testing.T) {
+			archive.EXPECT().Export(gomock.Any(), gomock.Any()).Return(errors.New("blob error"))
+			messages.EXPECT().Export(gomock.Any(), gomock.Any()).DoAndReturn(func(query models.ExportQuery, fn func([]models.Message) error) error {
+				return fn([]models.Message{{Id: 1, UserId: 1, User: &user, CreatedAt: ts}})
+			}).MaxTimes(1)
+
+			err := exportService.Export(user, services.ExportRequest{Conversation: "public"}, &bytes.Buffer{})
+			require.Error(t, err)
+		})
+
+		t.Run("Foreign conversation", func(t *testing.T) {
+			err := exportService.Export(user, services.ExportRequest{Conversation: "dm:2:3"}, &bytes.Buffer{})
+			require.Equal(t, services.ErrConversationNotFound, err)
+		})
+
+		t.Run("Malformed conversation", func(t *testing.T) {
+			err := exportService.Export(admin, services.ExportRequest{Conversation: "dm:x"}, &bytes.Buffer{})
+			require.Equal(t, services.ErrConversationNotFound, err)
+		})
+
+		t.Run("Malformed format", func(t *testing.T) {
+			err := exportService.Export(user, services.ExportRequest{Conversation: "public", Format: "xml"}, &bytes.Buffer{})
+			require.Equal(t, services.ErrMalformedExportFormat, err)
+		})
+
+		t.Run("Malformed range", func(t *testing.T) {
+			err := exportService.Export(user, services.ExportRequest{Conversation: "public", Since: ts, Until: ts}, &bytes.Buffer{})
+			require.Equal(t, services.ErrMalformedExportRange, err)
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			archive.EXPECT().Export(gomock.Any(), gomock.Any()).Return(nil)
+			messages.EXPECT().Export(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+			err := exportService.Export(user, services.ExportRequest{Conversation: "public"}, &bytes.Buffer{})
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		exported("")
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Export(admin, services.ExportRequest{}, &buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		require.JSONEq(t, `{"id":2,"conversation":"dm:1:2","from":"bob@example.com","to":"alice@example.com","type":"text","text":"**yo**","format":"markdown","created_at":"2026-10-19T10:01:00Z"}`, lines[1])
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		exported("public")
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Transcript(services.ExportRequest{Conversation: "public", Format: services.ExportCSV}, &buf))
+		require.Equal(t, "id,conversation,from,to,type,text,format,created_at\n"+
+			"1,public,alice@example.com,,text,\"<b>hi</b>\nthere\",plain,2026-10-19T10:00:00Z\n"+
+			"2,dm:1:2,bob@example.com,alice@example.com,text,**yo**,markdown,2026-10-19T10:01:00Z\n"+
+			"3,public,alice@example.com,,action,waves,plain,2026-10-19T10:02:00Z\n", buf.String())
+	})
+
+	t.Run("CSV formulas", func(t *testing.T) {
+		archive.EXPECT().Export(gomock.Any(), gomock.Any()).Return(nil)
+		messages.EXPECT().Export(gomock.Any(), gomock.Any()).DoAndReturn(func(query models.ExportQuery, fn func([]models.Message) error) error {
+			return fn([]models.Message{
+				{Id: 1, UserId: 1, User: &user, Type: models.MessageText, Text: "=HYPERLINK(\"http://evil\")", Format: models.FormatPlain, CreatedAt: ts},
+				{Id: 2, UserId: 1, User: &user, Type: models.MessageText, Text: "-1+2", Format: models.FormatPlain, CreatedAt: ts},
+				{Id: 3, UserId: 5, Type: models.MessageText, Text: "a=b", Format: models.FormatPlain, CreatedAt: ts},
+			})
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Transcript(services.ExportRequest{Conversation: "public", Format: services.ExportCSV}, &buf))
+		require.Equal(t, "id,conversation,from,to,type,text,format,created_at\n"+
+			"1,public,alice@example.com,,text,\"'=HYPERLINK(\"\"http://evil\"\")\",plain,2026-10-19T10:00:00Z\n"+
+			"2,public,alice@example.com,,text,'-1+2,plain,2026-10-19T10:00:00Z\n"+
+			"3,public,user 5,,text,a=b,plain,2026-10-19T10:00:00Z\n", buf.String())
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		exported("dm:1:2")
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Export(user, services.ExportRequest{Conversation: "dm:1:2", Format: services.ExportText}, &buf))
+		require.Equal(t, "[2026-10-19 10:00:00 UTC] public alice: <b>hi</b>\n    there\n"+
+			"[2026-10-19 10:01:00 UTC] dm:1:2 bob@example.com: **yo**\n"+
+			"[2026-10-19 10:02:00 UTC] public * alice waves\n", buf.String())
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		exported("public")
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Export(user, services.ExportRequest{Conversation: "public", Format: services.ExportHTML}, &buf))
+
+		page := buf.String()
+		require.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+		require.True(t, strings.HasSuffix(page, "</html>\n"))
+		require.Contains(t, page, "&lt;b&gt;hi&lt;/b&gt;<br>there")
+		require.Contains(t, page, "<strong>yo</strong>")
+		require.Contains(t, page, `<div class="message action">`)
+	})
+
+	t.Run("Archived", func(t *testing.T) {
+		exported := []models.Message{
+			{Id: 4, UserId: 4, Type: models.MessageText, Text: "old", Format: models.FormatPlain, CreatedAt: ts.Add(-time.Hour)},
+			{Id: 5, UserId: 2, ReceiverId: 1, User: bob, Receiver: &user, Type: models.MessageText, Text: "archived", Format: models.FormatPlain, CreatedAt: ts.Add(90 * time.Second)},
+		}
+
+		archive.EXPECT().Export(models.ExportQuery{Batch: 2}, gomock.Any()).DoAndReturn(func(query models.ExportQuery, fn func([]models.Message) error) error {
+			return fn(exported)
+		})
+		messages.EXPECT().Export(models.ExportQuery{Batch: 2}, gomock.Any()).DoAndReturn(func(query models.ExportQuery, fn func([]models.Message) error) error {
+			if err := fn([]models.Message{
+				{Id: 1, UserId: 1, User: &user, Type: models.MessageText, Text: "hi", Format: models.FormatPlain, CreatedAt: ts},
+				{Id: 2, UserId: 1, User: &user, Type: models.MessageText, Text: "there", Format: models.FormatPlain, CreatedAt: ts.Add(time.Minute)},
+			}); err != nil {
+				return err
+			}
+
+			return fn([]models.Message{
+				{Id: 3, UserId: 1, User: &user, Type: models.MessageText, Text: "again", Format: models.FormatPlain, CreatedAt: ts.Add(2 * time.Minute)},
+			})
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.Transcript(services.ExportRequest{Format: services.ExportText}, &buf))
+		// author of the oldest message is gone from users table
+		require.Equal(t, "[2026-10-19 09:00:00 UTC] public user 4: old\n"+
+			"[2026-10-19 10:00:00 UTC] public alice: hi\n"+
+			"[2026-10-19 10:01:00 UTC] public alice: there\n"+
+			"[2026-10-19 10:01:30 UTC] dm:1:2 bob@example.com: archived\n"+
+			"[2026-10-19 10:02:00 UTC] public alice: again\n", buf.String())
+	})
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Mention interface {
+		Resolve(author models.User, receiverID int64, text string) ([]models.Mention, error)
+		Attach(messages []models.Message) error
+		List(user models.User, limit int) ([]models.Mention, error)
+	}
+
+	MentionOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		AccountRepo repositories.User
+		MentionRepo repositories.Mention
+	}
+
+	mentionService struct {
+		logger      *zap.SugaredLogger
+		accountRepo repositories.User
+		mentionRepo repositories.Mention
+	}
+)
+
+const maxMentions = 20
+
+var (
+	ErrMentionNotAllowed = errors.New("only moderators can mention @here and @all")
+
+	mentionRe = regexp.MustCompile(`(?:^|\s)@([^\s@]+@[^\s@]+|(?:here|all)\b)`)
+)
+
+func NewMention(opts MentionOptions) Mention {
+	return &mentionService{
+		logger:      opts.Logger.Named("mention_service"),
+		accountRepo: opts.AccountRepo,
+		mentionRepo: opts.MentionRepo,
+	}
+}
+
+// Resolve finds users mentioned in text, unknown users are left as plain text.
+// Private messages could only mention its participants, so nobody else
+// learns about conversation they can't see.
+func (m *mentionService) Resolve(author models.User, receiverID int64, text string) ([]models.Mention, error) {
+	mentions := make([]models.Mention, 0)
+	seen := make(map[string]bool)
+
+	for _, token := range parseMentions(text) {
+		if seen[token] || len(mentions) >= maxMentions {
+			continue
+		}
+		seen[token] = true
+
+		if token == models.MentionHere || token == models.MentionAll {
+			if receiverID != 0 {
+				continue
+			}
+
+			if !author.IsModerator() {
+				return nil, ErrMentionNotAllowed
+			}
+
+			mentions = append(mentions, models.Mention{
+				Kind:      token,
+				CreatedAt: time.Now(),
+			})
+			continue
+		}
+
+		user, err := m.accountRepo.FindByEmail(token)
+		if err != nil {
+			return nil, err
+		}
+
+		if user == nil || user.ID == author.ID {
+			continue
+		}
+
+		if receiverID != 0 && user.ID != receiverID {
+			continue
+		}
+
+		mentions = append(mentions, models.Mention{
+			UserId:    user.ID,
+			User:      user,
+			Kind:      models.MentionUser,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return mentions, nil
+}
+
+// Attach loads mentions of given messages
+func (m *mentionService) Attach(messages []models.Message) error {
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	mentions, err := m.mentionRepo.ForMessages(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Mentions = mentions[messages[i].Id]
+	}
+
+	return nil
+}
+
+func (m *mentionService) List(user models.User, limit int) ([]models.Mention, error) {
+	return m.mentionRepo.ForUser(user, limit)
+}
+
+// parseMentions extracts mentioned emails, @here and @all from text
+func parseMentions(text string) []string {
+	var tokens []string
+	for _, match := range mentionRe.FindAllStringSubmatch(text, -1) {
+		tokens = append(tokens, strings.TrimRight(match[1], ".,;:!?)'\""))
+	}
+
+	return tokens
+}
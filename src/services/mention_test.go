@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := map[string][]string{
+		"no mentions here":                           nil,
+		"@bob@example.com hi":                        {"bob@example.com"},
+		"hi @bob@example.com, and @eve@example.com.": {"bob@example.com", "eve@example.com"},
+		"mail me at bob@example.com":                 nil,
+		"@here please look":                          {"here"},
+		"@hereby not a mention, but @all is":         {"all"},
+	}
+
+	for text, expected := range tests {
+		require.Equal(t, expected, parseMentions(text), text)
+	}
+}
+
+func TestMentionService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accounts := mock_repositories.NewMockUser(ctrl)
+	mentions := mock_repositories.NewMockMention(ctrl)
+
+	mentionService := NewMention(MentionOptions{
+		Logger:      zap.NewNop().Sugar(),
+		AccountRepo: accounts,
+		MentionRepo: mentions,
+	})
+
+	author := models.User{ID: 1, Email: "user@example.com", Role: models.RoleUser}
+	bob := &models.User{ID: 2, Email: "bob@example.com"}
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Run("Users", func(t *testing.T) {
+			accounts.EXPECT().FindByEmail("bob@example.com").Return(bob, nil)
+			accounts.EXPECT().FindByEmail("ghost@example.com").Return(nil, nil)
+			accounts.EXPECT().FindByEmail("user@example.com").Return(&author, nil)
+
+			result, err := mentionService.Resolve(author, 0, "@bob@example.com @bob@example.com @ghost@example.com @user@example.com")
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			require.Equal(t, bob.ID, result[0].UserId)
+			require.Equal(t, models.MentionUser, result[0].Kind)
+		})
+
+		t.Run("Private message mentions only participants", func(t *testing.T) {
+			accounts.EXPECT().FindByEmail("bob@example.com").Return(bob, nil)
+
+			result, err := mentionService.Resolve(author, 3, "@bob@example.com @all")
+			require.NoError(t, err)
+			require.Empty(t, result)
+		})
+
+		t.Run("Everyone requires moderator", func(t *testing.T) {
+			result, err := mentionService.Resolve(author, 0, "@here")
+			require.Nil(t, result)
+			require.Equal(t, ErrMentionNotAllowed, err)
+
+			moderator := models.User{ID: 1, Role: models.RoleModerator}
+			result, err = mentionService.Resolve(moderator, 0, "@here")
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			require.Equal(t, models.MentionHere, result[0].Kind)
+		})
+	})
+
+	t.Run("Attach", func(t *testing.T) {
+		messages := []models.Message{{Id: 1}, {Id: 2}}
+		mentions.EXPECT().ForMessages([]int64{1, 2}).Return(map[int64][]models.Mention{
+			2: {{MessageId: 2, UserId: 2, Kind: models.MentionUser}},
+		}, nil)
+
+		require.NoError(t, mentionService.Attach(messages))
+		require.Empty(t, messages[0].Mentions)
+		require.Len(t, messages[1].Mentions, 1)
+	})
+}
@@ -7,6 +7,7 @@ package mock_services
 import (
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/playneta/go-sessions/src/models"
+	services "github.com/playneta/go-sessions/src/services"
 	reflect "reflect"
 )
 
@@ -60,16 +61,16 @@ func (mr *MockAccountMockRecorder) Authorize(email, password interface{}) *gomoc
 }
 
 // CreateMessage mocks base method
-func (m *MockAccount) CreateMessage(user models.User, receiverEmail, text string) (*models.Message, error) {
-	ret := m.ctrl.Call(m, "CreateMessage", user, receiverEmail, text)
+func (m *MockAccount) CreateMessage(user models.User, req services.MessageRequest) (*models.Message, error) {
+	ret := m.ctrl.Call(m, "CreateMessage", user, req)
 	ret0, _ := ret[0].(*models.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMessage indicates an expected call of CreateMessage
-func (mr *MockAccountMockRecorder) CreateMessage(user, receiverEmail, text interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockAccount)(nil).CreateMessage), user, receiverEmail, text)
+func (mr *MockAccountMockRecorder) CreateMessage(user, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockAccount)(nil).CreateMessage), user, req)
 }
 
 // History mocks base method
@@ -84,3 +85,81 @@ func (m *MockAccount) History(user models.User) ([]models.Message, error) {
 func (mr *MockAccountMockRecorder) History(user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockAccount)(nil).History), user)
 }
+
+// Messages mocks base method
+func (m *MockAccount) Messages(user models.User, req services.HistoryRequest) (*models.HistoryPage, error) {
+	ret := m.ctrl.Call(m, "Messages", user, req)
+	ret0, _ := ret[0].(*models.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Messages indicates an expected call of Messages
+func (mr *MockAccountMockRecorder) Messages(user, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Messages", reflect.TypeOf((*MockAccount)(nil).Messages), user, req)
+}
+
+// CatchUp mocks base method
+func (m *MockAccount) CatchUp(user models.User, lastID int64) (*models.HistoryPage, error) {
+	ret := m.ctrl.Call(m, "CatchUp", user, lastID)
+	ret0, _ := ret[0].(*models.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CatchUp indicates an expected call of CatchUp
+func (mr *MockAccountMockRecorder) CatchUp(user, lastID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CatchUp", reflect.TypeOf((*MockAccount)(nil).CatchUp), user, lastID)
+}
+
+// Queued mocks base method
+func (m *MockAccount) Queued(user models.User) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Queued", user)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queued indicates an expected call of Queued
+func (mr *MockAccountMockRecorder) Queued(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queued", reflect.TypeOf((*MockAccount)(nil).Queued), user)
+}
+
+// Rename mocks base method
+func (m *MockAccount) Rename(user models.User, nickname string) (*models.User, error) {
+	ret := m.ctrl.Call(m, "Rename", user, nickname)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename
+func (mr *MockAccountMockRecorder) Rename(user, nickname interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockAccount)(nil).Rename), user, nickname)
+}
+
+// IssuePasswordReset mocks base method
+func (m *MockAccount) IssuePasswordReset(email string) (string, error) {
+	ret := m.ctrl.Call(m, "IssuePasswordReset", email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssuePasswordReset indicates an expected call of IssuePasswordReset
+func (mr *MockAccountMockRecorder) IssuePasswordReset(email interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssuePasswordReset", reflect.TypeOf((*MockAccount)(nil).IssuePasswordReset), email)
+}
+
+// ResetPassword mocks base method
+func (m *MockAccount) ResetPassword(token, password string) (*models.User, error) {
+	ret := m.ctrl.Call(m, "ResetPassword", token, password)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword
+func (mr *MockAccountMockRecorder) ResetPassword(token, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAccount)(nil).ResetPassword), token, password)
+}
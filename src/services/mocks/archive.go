@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/archive.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+	time "time"
+)
+
+// MockArchive is a mock of Archive interface
+type MockArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveMockRecorder
+}
+
+// MockArchiveMockRecorder is the mock recorder for MockArchive
+type MockArchiveMockRecorder struct {
+	mock *MockArchive
+}
+
+// NewMockArchive creates a new mock instance
+func NewMockArchive(ctrl *gomock.Controller) *MockArchive {
+	mock := &MockArchive{ctrl: ctrl}
+	mock.recorder = &MockArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockArchive) EXPECT() *MockArchiveMockRecorder {
+	return m.recorder
+}
+
+// Archive mocks base method
+func (m *MockArchive) Archive(before time.Time) (int, error) {
+	ret := m.ctrl.Call(m, "Archive", before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive
+func (mr *MockArchiveMockRecorder) Archive(before interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockArchive)(nil).Archive), before)
+}
+
+// Find mocks base method
+func (m *MockArchive) Find(conversation string, id int64) (*models.Message, error) {
+	ret := m.ctrl.Call(m, "Find", conversation, id)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockArchiveMockRecorder) Find(conversation, id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArchive)(nil).Find), conversation, id)
+}
+
+// Range mocks base method
+func (m *MockArchive) Range(conversation string, after, before *models.MessageKey, limit int, ascending bool) ([]models.Message, error) {
+	ret := m.ctrl.Call(m, "Range", conversation, after, before, limit, ascending)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Range indicates an expected call of Range
+func (mr *MockArchiveMockRecorder) Range(conversation, after, before, limit, ascending interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockArchive)(nil).Range), conversation, after, before, limit, ascending)
+}
+
+// Export mocks base method
+func (m *MockArchive) Export(query models.ExportQuery, fn func([]models.Message) error) error {
+	ret := m.ctrl.Call(m, "Export", query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export
+func (mr *MockArchiveMockRecorder) Export(query, fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockArchive)(nil).Export), query, fn)
+}
+
+// Prune mocks base method
+func (m *MockArchive) Prune(policy models.RetentionPolicy, now time.Time, dryRun bool) (int, error) {
+	ret := m.ctrl.Call(m, "Prune", policy, now, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune
+func (mr *MockArchiveMockRecorder) Prune(policy, now, dryRun interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockArchive)(nil).Prune), policy, now, dryRun)
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/attachment.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	io "io"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAttachment is a mock of Attachment interface
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// Upload mocks base method
+func (m *MockAttachment) Upload(user models.User, name string, size int64, r io.Reader) (*models.Attachment, error) {
+	ret := m.ctrl.Call(m, "Upload", user, name, size, r)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload
+func (mr *MockAttachmentMockRecorder) Upload(user, name, size, r interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockAttachment)(nil).Upload), user, name, size, r)
+}
+
+// URL mocks base method
+func (m *MockAttachment) URL(user models.User, id int64) (string, time.Time, error) {
+	ret := m.ctrl.Call(m, "URL", user, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// URL indicates an expected call of URL
+func (mr *MockAttachmentMockRecorder) URL(user, id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockAttachment)(nil).URL), user, id)
+}
+
+// Open mocks base method
+func (m *MockAttachment) Open(id, expires int64, signature string) (*models.Attachment, io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "Open", id, expires, signature)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open
+func (mr *MockAttachmentMockRecorder) Open(id, expires, signature interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachment)(nil).Open), id, expires, signature)
+}
@@ -0,0 +1,46 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/delivery.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockDelivery is a mock of Delivery interface
+type MockDelivery struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryMockRecorder
+}
+
+// MockDeliveryMockRecorder is the mock recorder for MockDelivery
+type MockDeliveryMockRecorder struct {
+	mock *MockDelivery
+}
+
+// NewMockDelivery creates a new mock instance
+func NewMockDelivery(ctrl *gomock.Controller) *MockDelivery {
+	mock := &MockDelivery{ctrl: ctrl}
+	mock.recorder = &MockDeliveryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDelivery) EXPECT() *MockDeliveryMockRecorder {
+	return m.recorder
+}
+
+// Delivered mocks base method
+func (m *MockDelivery) Delivered(receiver models.User, messages []models.Message) error {
+	ret := m.ctrl.Call(m, "Delivered", receiver, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delivered indicates an expected call of Delivered
+func (mr *MockDeliveryMockRecorder) Delivered(receiver, messages interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delivered", reflect.TypeOf((*MockDelivery)(nil).Delivered), receiver, messages)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/draft.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockDraft is a mock of Draft interface
+type MockDraft struct {
+	ctrl     *gomock.Controller
+	recorder *MockDraftMockRecorder
+}
+
+// MockDraftMockRecorder is the mock recorder for MockDraft
+type MockDraftMockRecorder struct {
+	mock *MockDraft
+}
+
+// NewMockDraft creates a new mock instance
+func NewMockDraft(ctrl *gomock.Controller) *MockDraft {
+	mock := &MockDraft{ctrl: ctrl}
+	mock.recorder = &MockDraftMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDraft) EXPECT() *MockDraftMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method
+func (m *MockDraft) Save(user models.User, conversation, text string) (*models.Draft, error) {
+	ret := m.ctrl.Call(m, "Save", user, conversation, text)
+	ret0, _ := ret[0].(*models.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save
+func (mr *MockDraftMockRecorder) Save(user, conversation, text interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDraft)(nil).Save), user, conversation, text)
+}
+
+// Find mocks base method
+func (m *MockDraft) Find(user models.User, conversation string) (*models.Draft, error) {
+	ret := m.ctrl.Call(m, "Find", user, conversation)
+	ret0, _ := ret[0].(*models.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockDraftMockRecorder) Find(user, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDraft)(nil).Find), user, conversation)
+}
+
+// Delete mocks base method
+func (m *MockDraft) Delete(user models.User, conversation string) error {
+	ret := m.ctrl.Call(m, "Delete", user, conversation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockDraftMockRecorder) Delete(user, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDraft)(nil).Delete), user, conversation)
+}
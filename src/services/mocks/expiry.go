@@ -0,0 +1,46 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/expiry.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockExpiry is a mock of Expiry interface
+type MockExpiry struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiryMockRecorder
+}
+
+// MockExpiryMockRecorder is the mock recorder for MockExpiry
+type MockExpiryMockRecorder struct {
+	mock *MockExpiry
+}
+
+// NewMockExpiry creates a new mock instance
+func NewMockExpiry(ctrl *gomock.Controller) *MockExpiry {
+	mock := &MockExpiry{ctrl: ctrl}
+	mock.recorder = &MockExpiryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExpiry) EXPECT() *MockExpiryMockRecorder {
+	return m.recorder
+}
+
+// Reap mocks base method
+func (m *MockExpiry) Reap() (int, error) {
+	ret := m.ctrl.Call(m, "Reap")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reap indicates an expected call of Reap
+func (mr *MockExpiryMockRecorder) Reap() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reap", reflect.TypeOf((*MockExpiry)(nil).Reap))
+}
@@ -0,0 +1,60 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/export.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	services "github.com/playneta/go-sessions/src/services"
+	io "io"
+	reflect "reflect"
+)
+
+// MockExport is a mock of Export interface
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method
+func (m *MockExport) Export(user models.User, req services.ExportRequest, w io.Writer) error {
+	ret := m.ctrl.Call(m, "Export", user, req, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export
+func (mr *MockExportMockRecorder) Export(user, req, w interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExport)(nil).Export), user, req, w)
+}
+
+// Transcript mocks base method
+func (m *MockExport) Transcript(req services.ExportRequest, w io.Writer) error {
+	ret := m.ctrl.Call(m, "Transcript", req, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transcript indicates an expected call of Transcript
+func (mr *MockExportMockRecorder) Transcript(req, w interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcript", reflect.TypeOf((*MockExport)(nil).Transcript), req, w)
+}
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/notifier.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockNotifier is a mock of Notifier interface
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Message mocks base method
+func (m *MockNotifier) Message(message models.Message) {
+	m.ctrl.Call(m, "Message", message)
+}
+
+// Message indicates an expected call of Message
+func (mr *MockNotifierMockRecorder) Message(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Message", reflect.TypeOf((*MockNotifier)(nil).Message), message)
+}
+
+// MessageUpdated mocks base method
+func (m *MockNotifier) MessageUpdated(message models.Message) {
+	m.ctrl.Call(m, "MessageUpdated", message)
+}
+
+// MessageUpdated indicates an expected call of MessageUpdated
+func (mr *MockNotifierMockRecorder) MessageUpdated(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageUpdated", reflect.TypeOf((*MockNotifier)(nil).MessageUpdated), message)
+}
+
+// MessageExpired mocks base method
+func (m *MockNotifier) MessageExpired(message models.Message) {
+	m.ctrl.Call(m, "MessageExpired", message)
+}
+
+// MessageExpired indicates an expected call of MessageExpired
+func (mr *MockNotifierMockRecorder) MessageExpired(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageExpired", reflect.TypeOf((*MockNotifier)(nil).MessageExpired), message)
+}
+
+// ReactionAdded mocks base method
+func (m *MockNotifier) ReactionAdded(message models.Message, reaction models.Reaction) {
+	m.ctrl.Call(m, "ReactionAdded", message, reaction)
+}
+
+// ReactionAdded indicates an expected call of ReactionAdded
+func (mr *MockNotifierMockRecorder) ReactionAdded(message, reaction interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactionAdded", reflect.TypeOf((*MockNotifier)(nil).ReactionAdded), message, reaction)
+}
+
+// ReactionRemoved mocks base method
+func (m *MockNotifier) ReactionRemoved(message models.Message, reaction models.Reaction) {
+	m.ctrl.Call(m, "ReactionRemoved", message, reaction)
+}
+
+// ReactionRemoved indicates an expected call of ReactionRemoved
+func (mr *MockNotifierMockRecorder) ReactionRemoved(message, reaction interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactionRemoved", reflect.TypeOf((*MockNotifier)(nil).ReactionRemoved), message, reaction)
+}
+
+// Delivered mocks base method
+func (m *MockNotifier) Delivered(message models.Message) {
+	m.ctrl.Call(m, "Delivered", message)
+}
+
+// Delivered indicates an expected call of Delivered
+func (mr *MockNotifierMockRecorder) Delivered(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delivered", reflect.TypeOf((*MockNotifier)(nil).Delivered), message)
+}
+
+// Read mocks base method
+func (m *MockNotifier) Read(message models.Message, reader models.User) {
+	m.ctrl.Call(m, "Read", message, reader)
+}
+
+// Read indicates an expected call of Read
+func (mr *MockNotifierMockRecorder) Read(message, reader interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockNotifier)(nil).Read), message, reader)
+}
+
+// PollUpdated mocks base method
+func (m *MockNotifier) PollUpdated(message models.Message) {
+	m.ctrl.Call(m, "PollUpdated", message)
+}
+
+// PollUpdated indicates an expected call of PollUpdated
+func (mr *MockNotifierMockRecorder) PollUpdated(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollUpdated", reflect.TypeOf((*MockNotifier)(nil).PollUpdated), message)
+}
+
+// Pinned mocks base method
+func (m *MockNotifier) Pinned(message models.Message, pin models.Pin) {
+	m.ctrl.Call(m, "Pinned", message, pin)
+}
+
+// Pinned indicates an expected call of Pinned
+func (mr *MockNotifierMockRecorder) Pinned(message, pin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pinned", reflect.TypeOf((*MockNotifier)(nil).Pinned), message, pin)
+}
+
+// Unpinned mocks base method
+func (m *MockNotifier) Unpinned(message models.Message, user models.User) {
+	m.ctrl.Call(m, "Unpinned", message, user)
+}
+
+// Unpinned indicates an expected call of Unpinned
+func (mr *MockNotifierMockRecorder) Unpinned(message, user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpinned", reflect.TypeOf((*MockNotifier)(nil).Unpinned), message, user)
+}
+
+// DraftUpdated mocks base method
+func (m *MockNotifier) DraftUpdated(user models.User, draft models.Draft) {
+	m.ctrl.Call(m, "DraftUpdated", user, draft)
+}
+
+// DraftUpdated indicates an expected call of DraftUpdated
+func (mr *MockNotifierMockRecorder) DraftUpdated(user, draft interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DraftUpdated", reflect.TypeOf((*MockNotifier)(nil).DraftUpdated), user, draft)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/pin.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockPin is a mock of Pin interface
+type MockPin struct {
+	ctrl     *gomock.Controller
+	recorder *MockPinMockRecorder
+}
+
+// MockPinMockRecorder is the mock recorder for MockPin
+type MockPinMockRecorder struct {
+	mock *MockPin
+}
+
+// NewMockPin creates a new mock instance
+func NewMockPin(ctrl *gomock.Controller) *MockPin {
+	mock := &MockPin{ctrl: ctrl}
+	mock.recorder = &MockPinMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPin) EXPECT() *MockPinMockRecorder {
+	return m.recorder
+}
+
+// Pin mocks base method
+func (m *MockPin) Pin(user models.User, messageID int64) (*models.Pin, error) {
+	ret := m.ctrl.Call(m, "Pin", user, messageID)
+	ret0, _ := ret[0].(*models.Pin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pin indicates an expected call of Pin
+func (mr *MockPinMockRecorder) Pin(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockPin)(nil).Pin), user, messageID)
+}
+
+// Unpin mocks base method
+func (m *MockPin) Unpin(user models.User, messageID int64) error {
+	ret := m.ctrl.Call(m, "Unpin", user, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unpin indicates an expected call of Unpin
+func (mr *MockPinMockRecorder) Unpin(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpin", reflect.TypeOf((*MockPin)(nil).Unpin), user, messageID)
+}
+
+// List mocks base method
+func (m *MockPin) List(user models.User, conversation string) ([]models.Pin, error) {
+	ret := m.ctrl.Call(m, "List", user, conversation)
+	ret0, _ := ret[0].([]models.Pin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockPinMockRecorder) List(user, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPin)(nil).List), user, conversation)
+}
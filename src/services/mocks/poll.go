@@ -0,0 +1,61 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/poll.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	services "github.com/playneta/go-sessions/src/services"
+	reflect "reflect"
+)
+
+// MockPoll is a mock of Poll interface
+type MockPoll struct {
+	ctrl     *gomock.Controller
+	recorder *MockPollMockRecorder
+}
+
+// MockPollMockRecorder is the mock recorder for MockPoll
+type MockPollMockRecorder struct {
+	mock *MockPoll
+}
+
+// NewMockPoll creates a new mock instance
+func NewMockPoll(ctrl *gomock.Controller) *MockPoll {
+	mock := &MockPoll{ctrl: ctrl}
+	mock.recorder = &MockPollMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPoll) EXPECT() *MockPollMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPoll) Create(user models.User, req services.PollRequest) (*models.Message, error) {
+	ret := m.ctrl.Call(m, "Create", user, req)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPollMockRecorder) Create(user, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPoll)(nil).Create), user, req)
+}
+
+// Vote mocks base method
+func (m *MockPoll) Vote(user models.User, messageID int64, optionIDs []int64) (*models.Poll, error) {
+	ret := m.ctrl.Call(m, "Vote", user, messageID, optionIDs)
+	ret0, _ := ret[0].(*models.Poll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Vote indicates an expected call of Vote
+func (mr *MockPollMockRecorder) Vote(user, messageID, optionIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockPoll)(nil).Vote), user, messageID, optionIDs)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/preview.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockPreview is a mock of Preview interface
+type MockPreview struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreviewMockRecorder
+}
+
+// MockPreviewMockRecorder is the mock recorder for MockPreview
+type MockPreviewMockRecorder struct {
+	mock *MockPreview
+}
+
+// NewMockPreview creates a new mock instance
+func NewMockPreview(ctrl *gomock.Controller) *MockPreview {
+	mock := &MockPreview{ctrl: ctrl}
+	mock.recorder = &MockPreviewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPreview) EXPECT() *MockPreviewMockRecorder {
+	return m.recorder
+}
+
+// Unfurl mocks base method
+func (m *MockPreview) Unfurl(message models.Message) {
+	m.ctrl.Call(m, "Unfurl", message)
+}
+
+// Unfurl indicates an expected call of Unfurl
+func (mr *MockPreviewMockRecorder) Unfurl(message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfurl", reflect.TypeOf((*MockPreview)(nil).Unfurl), message)
+}
+
+// Attach mocks base method
+func (m *MockPreview) Attach(messages []models.Message) error {
+	ret := m.ctrl.Call(m, "Attach", messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach
+func (mr *MockPreviewMockRecorder) Attach(messages interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockPreview)(nil).Attach), messages)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/read.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockRead is a mock of Read interface
+type MockRead struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadMockRecorder
+}
+
+// MockReadMockRecorder is the mock recorder for MockRead
+type MockReadMockRecorder struct {
+	mock *MockRead
+}
+
+// NewMockRead creates a new mock instance
+func NewMockRead(ctrl *gomock.Controller) *MockRead {
+	mock := &MockRead{ctrl: ctrl}
+	mock.recorder = &MockReadMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRead) EXPECT() *MockReadMockRecorder {
+	return m.recorder
+}
+
+// MarkRead mocks base method
+func (m *MockRead) MarkRead(user models.User, messageID int64) (*models.ReadMarker, error) {
+	ret := m.ctrl.Call(m, "MarkRead", user, messageID)
+	ret0, _ := ret[0].(*models.ReadMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead
+func (mr *MockReadMockRecorder) MarkRead(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockRead)(nil).MarkRead), user, messageID)
+}
+
+// State mocks base method
+func (m *MockRead) State(user models.User) ([]models.ReadState, error) {
+	ret := m.ctrl.Call(m, "State", user)
+	ret0, _ := ret[0].([]models.ReadState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// State indicates an expected call of State
+func (mr *MockReadMockRecorder) State(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockRead)(nil).State), user)
+}
+
+// ConversationState mocks base method
+func (m *MockRead) ConversationState(user models.User, conversation string) (*models.ReadState, error) {
+	ret := m.ctrl.Call(m, "ConversationState", user, conversation)
+	ret0, _ := ret[0].(*models.ReadState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConversationState indicates an expected call of ConversationState
+func (mr *MockReadMockRecorder) ConversationState(user, conversation interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConversationState", reflect.TypeOf((*MockRead)(nil).ConversationState), user, conversation)
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/schedule.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	services "github.com/playneta/go-sessions/src/services"
+	reflect "reflect"
+)
+
+// MockSchedule is a mock of Schedule interface
+type MockSchedule struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleMockRecorder
+}
+
+// MockScheduleMockRecorder is the mock recorder for MockSchedule
+type MockScheduleMockRecorder struct {
+	mock *MockSchedule
+}
+
+// NewMockSchedule creates a new mock instance
+func NewMockSchedule(ctrl *gomock.Controller) *MockSchedule {
+	mock := &MockSchedule{ctrl: ctrl}
+	mock.recorder = &MockScheduleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSchedule) EXPECT() *MockScheduleMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockSchedule) Create(user models.User, req services.ScheduleRequest) (*models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "Create", user, req)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockScheduleMockRecorder) Create(user, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSchedule)(nil).Create), user, req)
+}
+
+// List mocks base method
+func (m *MockSchedule) List(user models.User) ([]models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "List", user)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockScheduleMockRecorder) List(user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSchedule)(nil).List), user)
+}
+
+// Update mocks base method
+func (m *MockSchedule) Update(user models.User, id int64, req services.ScheduleRequest) (*models.ScheduledMessage, error) {
+	ret := m.ctrl.Call(m, "Update", user, id, req)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockScheduleMockRecorder) Update(user, id, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSchedule)(nil).Update), user, id, req)
+}
+
+// Cancel mocks base method
+func (m *MockSchedule) Cancel(user models.User, id int64) error {
+	ret := m.ctrl.Call(m, "Cancel", user, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockScheduleMockRecorder) Cancel(user, id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockSchedule)(nil).Cancel), user, id)
+}
+
+// Dispatch mocks base method
+func (m *MockSchedule) Dispatch() (int, error) {
+	ret := m.ctrl.Call(m, "Dispatch")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch
+func (mr *MockScheduleMockRecorder) Dispatch() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockSchedule)(nil).Dispatch))
+}
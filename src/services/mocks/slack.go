@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/slack.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	zip "archive/zip"
+	gomock "github.com/golang/mock/gomock"
+	services "github.com/playneta/go-sessions/src/services"
+	reflect "reflect"
+)
+
+// MockSlackImport is a mock of SlackImport interface
+type MockSlackImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlackImportMockRecorder
+}
+
+// MockSlackImportMockRecorder is the mock recorder for MockSlackImport
+type MockSlackImportMockRecorder struct {
+	mock *MockSlackImport
+}
+
+// NewMockSlackImport creates a new mock instance
+func NewMockSlackImport(ctrl *gomock.Controller) *MockSlackImport {
+	mock := &MockSlackImport{ctrl: ctrl}
+	mock.recorder = &MockSlackImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSlackImport) EXPECT() *MockSlackImportMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method
+func (m *MockSlackImport) Import(archive *zip.Reader, channels []string) (*services.SlackImportReport, error) {
+	ret := m.ctrl.Call(m, "Import", archive, channels)
+	ret0, _ := ret[0].(*services.SlackImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *MockSlackImportMockRecorder) Import(archive, channels interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockSlackImport)(nil).Import), archive, channels)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./src/services/star.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/playneta/go-sessions/src/models"
+	reflect "reflect"
+)
+
+// MockStar is a mock of Star interface
+type MockStar struct {
+	ctrl     *gomock.Controller
+	recorder *MockStarMockRecorder
+}
+
+// MockStarMockRecorder is the mock recorder for MockStar
+type MockStarMockRecorder struct {
+	mock *MockStar
+}
+
+// NewMockStar creates a new mock instance
+func NewMockStar(ctrl *gomock.Controller) *MockStar {
+	mock := &MockStar{ctrl: ctrl}
+	mock.recorder = &MockStarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStar) EXPECT() *MockStarMockRecorder {
+	return m.recorder
+}
+
+// Star mocks base method
+func (m *MockStar) Star(user models.User, messageID int64) (*models.Star, error) {
+	ret := m.ctrl.Call(m, "Star", user, messageID)
+	ret0, _ := ret[0].(*models.Star)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Star indicates an expected call of Star
+func (mr *MockStarMockRecorder) Star(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Star", reflect.TypeOf((*MockStar)(nil).Star), user, messageID)
+}
+
+// Unstar mocks base method
+func (m *MockStar) Unstar(user models.User, messageID int64) error {
+	ret := m.ctrl.Call(m, "Unstar", user, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unstar indicates an expected call of Unstar
+func (mr *MockStarMockRecorder) Unstar(user, messageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unstar", reflect.TypeOf((*MockStar)(nil).Unstar), user, messageID)
+}
+
+// Saved mocks base method
+func (m *MockStar) Saved(user models.User, cursor int64, limit int) (*models.SavedPage, error) {
+	ret := m.ctrl.Call(m, "Saved", user, cursor, limit)
+	ret0, _ := ret[0].(*models.SavedPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Saved indicates an expected call of Saved
+func (mr *MockStarMockRecorder) Saved(user, cursor, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Saved", reflect.TypeOf((*MockStar)(nil).Saved), user, cursor, limit)
+}
@@ -0,0 +1,32 @@
+package services
+
+import "github.com/playneta/go-sessions/src/models"
+
+type (
+	// Notifier delivers realtime events to connected users, it is implemented
+	// by websocket hub so services could push events without knowing about transport
+	Notifier interface {
+		// Message delivers new message to everyone who can see it
+		Message(message models.Message)
+		// MessageUpdated delivers message again once it got more data, like link previews
+		MessageUpdated(message models.Message)
+		// MessageExpired notifies everyone who could see self-destructing message that it is gone
+		MessageExpired(message models.Message)
+		// ReactionAdded notifies everyone who can see message about new reaction
+		ReactionAdded(message models.Message, reaction models.Reaction)
+		// ReactionRemoved notifies everyone who can see message about removed reaction
+		ReactionRemoved(message models.Message, reaction models.Reaction)
+		// Delivered tells author of private message it reached a device of receiver
+		Delivered(message models.Message)
+		// Read sends read receipt of private message to the other participant
+		Read(message models.Message, reader models.User)
+		// PollUpdated notifies everyone who can see poll message about new tallies
+		PollUpdated(message models.Message)
+		// Pinned notifies everyone who can see message that it was pinned
+		Pinned(message models.Message, pin models.Pin)
+		// Unpinned notifies everyone who can see message that user unpinned it
+		Unpinned(message models.Message, user models.User)
+		// DraftUpdated sends draft to every device of its author, empty text means it was cleared
+		DraftUpdated(user models.User, draft models.Draft)
+	}
+)
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Pin interface {
+		Pin(user models.User, messageID int64) (*models.Pin, error)
+		Unpin(user models.User, messageID int64) error
+		List(user models.User, conversation string) ([]models.Pin, error)
+	}
+
+	PinOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		MessageRepo repositories.Message
+		PinRepo     repositories.Pin
+		Notifier    Notifier
+	}
+
+	pinService struct {
+		logger      *zap.SugaredLogger
+		messageRepo repositories.Message
+		pinRepo     repositories.Pin
+		notifier    Notifier
+		max         int
+	}
+)
+
+var (
+	ErrTooManyPins  = errors.New("too many pinned messages in conversation")
+	ErrPinNotFound  = errors.New("message is not pinned")
+	ErrPinForbidden = errors.New("only moderators can unpin messages pinned by others")
+)
+
+func NewPin(opts PinOptions) Pin {
+	opts.Config.SetDefault("pins.max", 50)
+
+	return &pinService{
+		logger:      opts.Logger.Named("pin_service"),
+		messageRepo: opts.MessageRepo,
+		pinRepo:     opts.PinRepo,
+		notifier:    opts.Notifier,
+		max:         opts.Config.GetInt("pins.max"),
+	}
+}
+
+func (p *pinService) Pin(user models.User, messageID int64) (*models.Pin, error) {
+	message, err := p.find(user, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	pin := &models.Pin{
+		MessageId:    message.Id,
+		Message:      message,
+		Conversation: message.Conversation(),
+		UserId:       user.ID,
+		User:         &user,
+		CreatedAt:    time.Now(),
+	}
+
+	created, err := p.pinRepo.Create(pin, p.max)
+	if err == repositories.ErrPinLimit {
+		return nil, ErrTooManyPins
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		// Message was pinned before, pinning it again returns existing pin
+		existing, err := p.pinRepo.Find(message.Id)
+		if err != nil || existing == nil {
+			return pin, err
+		}
+
+		existing.Message = message
+		return existing, nil
+	}
+
+	p.notifier.Pinned(*message, *pin)
+	return pin, nil
+}
+
+// Unpin removes pin, in private conversations any participant could do it,
+// in public one only who pinned the message or moderator
+func (p *pinService) Unpin(user models.User, messageID int64) error {
+	message, err := p.find(user, messageID)
+	if err != nil {
+		return err
+	}
+
+	pin, err := p.pinRepo.Find(message.Id)
+	if err != nil {
+		return err
+	}
+
+	if pin == nil {
+		return ErrPinNotFound
+	}
+
+	if message.ReceiverId == 0 && pin.UserId != user.ID && !user.IsModerator() {
+		return ErrPinForbidden
+	}
+
+	if err := p.pinRepo.Delete(pin); err != nil {
+		return err
+	}
+
+	p.notifier.Unpinned(*message, user)
+	return nil
+}
+
+func (p *pinService) List(user models.User, conversation string) ([]models.Pin, error) {
+	if !models.ConversationVisibleTo(conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	return p.pinRepo.ForConversation(conversation)
+}
+
+func (p *pinService) find(user models.User, messageID int64) (*models.Message, error) {
+	message, err := p.messageRepo.Find(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil || !message.VisibleTo(user) {
+		return nil, ErrMessageNotFound
+	}
+
+	return message, nil
+}
@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPinService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	pins := mock_repositories.NewMockPin(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("pins.max", 2)
+
+	pinService := services.NewPin(services.PinOptions{
+		Logger:      zap.NewNop().Sugar(),
+		Config:      config,
+		MessageRepo: messages,
+		PinRepo:     pins,
+		Notifier:    notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+	public := &models.Message{Id: 10, UserId: 2}
+	private := &models.Message{Id: 11, UserId: 2, ReceiverId: 1}
+
+	t.Run("Pin", func(t *testing.T) {
+		t.Run("Foreign message", func(t *testing.T) {
+			messages.EXPECT().Find(int64(12)).Return(&models.Message{Id: 12, UserId: 2, ReceiverId: 3}, nil)
+
+			pin, err := pinService.Pin(user, 12)
+			require.Nil(t, pin)
+			require.Equal(t, services.ErrMessageNotFound, err)
+		})
+
+		t.Run("Too many pins", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(public, nil)
+			pins.EXPECT().Create(gomock.Any(), 2).Return(false, repositories.ErrPinLimit)
+
+			pin, err := pinService.Pin(user, 10)
+			require.Nil(t, pin)
+			require.Equal(t, services.ErrTooManyPins, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			messages.EXPECT().Find(int64(11)).Return(private, nil)
+			pins.EXPECT().Create(gomock.Any(), 2).Return(true, nil)
+			notifier.EXPECT().Pinned(*private, gomock.Any())
+
+			pin, err := pinService.Pin(user, 11)
+			require.NoError(t, err)
+			require.Equal(t, "dm:1:2", pin.Conversation)
+			require.Equal(t, user.ID, pin.UserId)
+		})
+
+		t.Run("Already pinned", func(t *testing.T) {
+			// conversation is at the limit, pinned message is returned without notifying anyone
+			existing := &models.Pin{Id: 3, MessageId: 10, Conversation: models.PublicConversation, UserId: 2}
+			messages.EXPECT().Find(int64(10)).Return(public, nil)
+			pins.EXPECT().Create(gomock.Any(), 2).Return(false, nil)
+			pins.EXPECT().Find(int64(10)).Return(existing, nil)
+
+			pin, err := pinService.Pin(user, 10)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), pin.Id)
+			require.Equal(t, public, pin.Message)
+		})
+	})
+
+	t.Run("Unpin", func(t *testing.T) {
+		t.Run("Not pinned", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(public, nil)
+			pins.EXPECT().Find(int64(10)).Return(nil, nil)
+
+			require.Equal(t, services.ErrPinNotFound, pinService.Unpin(user, 10))
+		})
+
+		t.Run("Pinned by someone else in public", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(public, nil)
+			pins.EXPECT().Find(int64(10)).Return(&models.Pin{Id: 1, MessageId: 10, UserId: 3}, nil)
+
+			require.Equal(t, services.ErrPinForbidden, pinService.Unpin(user, 10))
+		})
+
+		t.Run("Moderator", func(t *testing.T) {
+			moderator := models.User{ID: 5, Role: models.RoleModerator}
+			pin := &models.Pin{Id: 1, MessageId: 10, UserId: 3}
+
+			messages.EXPECT().Find(int64(10)).Return(public, nil)
+			pins.EXPECT().Find(int64(10)).Return(pin, nil)
+			pins.EXPECT().Delete(pin).Return(nil)
+			notifier.EXPECT().Unpinned(*public, moderator)
+
+			require.NoError(t, pinService.Unpin(moderator, 10))
+		})
+
+		t.Run("Private participant", func(t *testing.T) {
+			pin := &models.Pin{Id: 2, MessageId: 11, UserId: 2}
+
+			messages.EXPECT().Find(int64(11)).Return(private, nil)
+			pins.EXPECT().Find(int64(11)).Return(pin, nil)
+			pins.EXPECT().Delete(pin).Return(nil)
+			notifier.EXPECT().Unpinned(*private, user)
+
+			require.NoError(t, pinService.Unpin(user, 11))
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		_, err := pinService.List(user, "dm:2:3")
+		require.Equal(t, services.ErrConversationNotFound, err)
+
+		pins.EXPECT().ForConversation("public").Return([]models.Pin{{Id: 1}}, nil)
+
+		result, err := pinService.List(user, "public")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
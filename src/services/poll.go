@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Poll interface {
+		Create(user models.User, req PollRequest) (*models.Message, error)
+		Vote(user models.User, messageID int64, optionIDs []int64) (*models.Poll, error)
+	}
+
+	PollOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		MessageRepo repositories.Message
+		PollRepo    repositories.Poll
+
+		AccountService Account
+		Notifier       Notifier
+	}
+
+	// PollRequest describes poll user wants to post, text is the question
+	PollRequest struct {
+		To        string
+		Text      string
+		Format    string
+		Options   []string
+		Multiple  bool
+		Anonymous bool
+		ClosesAt  *time.Time
+	}
+
+	pollService struct {
+		logger      *zap.SugaredLogger
+		messageRepo repositories.Message
+		pollRepo    repositories.Poll
+		accounts    Account
+		notifier    Notifier
+
+		maxOptions int
+		maxAhead   time.Duration
+	}
+)
+
+const maxPollOption = 200
+
+var (
+	ErrMalformedPoll      = errors.New("poll must have a question and enough distinct options")
+	ErrMalformedClosingAt = errors.New("poll closing time must be in the future")
+	ErrMalformedVote      = errors.New("vote must reference options of the poll, one unless poll is multiple choice")
+	ErrPollNotFound       = errors.New("poll not found")
+	ErrPollClosed         = errors.New("poll is closed")
+)
+
+func NewPoll(opts PollOptions) Poll {
+	opts.Config.SetDefault("polls.max_options", 10)
+	opts.Config.SetDefault("polls.max_ahead", 30*24*time.Hour)
+
+	return &pollService{
+		logger:      opts.Logger.Named("poll_service"),
+		messageRepo: opts.MessageRepo,
+		pollRepo:    opts.PollRepo,
+		accounts:    opts.AccountService,
+		notifier:    opts.Notifier,
+		maxOptions:  opts.Config.GetInt("polls.max_options"),
+		maxAhead:    opts.Config.GetDuration("polls.max_ahead"),
+	}
+}
+
+// Create posts poll message and delivers it like any other message
+func (p *pollService) Create(user models.User, req PollRequest) (*models.Message, error) {
+	if strings.TrimSpace(req.Text) == "" || len(req.Options) < 2 || len(req.Options) > p.maxOptions {
+		return nil, ErrMalformedPoll
+	}
+
+	poll := &models.Poll{
+		Multiple:  req.Multiple,
+		Anonymous: req.Anonymous,
+		CreatedAt: time.Now(),
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	for _, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" || utf8.RuneCountInString(text) > maxPollOption || !utf8.ValidString(text) || seen[text] {
+			return nil, ErrMalformedPoll
+		}
+		seen[text] = true
+
+		poll.Options = append(poll.Options, models.PollOption{Text: text})
+	}
+
+	if req.ClosesAt != nil {
+		if !req.ClosesAt.After(time.Now()) || req.ClosesAt.Sub(time.Now()) > p.maxAhead {
+			return nil, ErrMalformedClosingAt
+		}
+
+		poll.ClosesAt = req.ClosesAt
+	}
+
+	message, err := p.accounts.CreateMessage(user, MessageRequest{
+		To:     req.To,
+		Text:   req.Text,
+		Format: req.Format,
+		Poll:   poll,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.notifier.Message(*message)
+	return message, nil
+}
+
+// Vote replaces votes of user in poll, empty options retract the vote
+func (p *pollService) Vote(user models.User, messageID int64, optionIDs []int64) (*models.Poll, error) {
+	message, err := p.messageRepo.Find(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil || !message.VisibleTo(user) {
+		return nil, ErrMessageNotFound
+	}
+
+	poll, err := p.find(user, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.Closed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+
+	valid := make(map[int64]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.Id] = true
+	}
+
+	seen := make(map[int64]bool, len(optionIDs))
+	unique := make([]int64, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return nil, ErrMalformedVote
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > 1 && !poll.Multiple {
+		return nil, ErrMalformedVote
+	}
+
+	if err := p.pollRepo.Vote(models.PollVote{
+		MessageId: messageID,
+		UserId:    user.ID,
+		CreatedAt: time.Now(),
+	}, unique); err == repositories.ErrSingleChoice {
+		return nil, ErrMalformedVote
+	} else if err != nil {
+		return nil, err
+	}
+
+	if poll, err = p.find(user, messageID); err != nil {
+		return nil, err
+	}
+
+	message.Poll = poll
+	p.notifier.PollUpdated(*message)
+
+	return poll, nil
+}
+
+func (p *pollService) find(user models.User, messageID int64) (*models.Poll, error) {
+	polls, err := p.pollRepo.ForMessages(user, []int64{messageID})
+	if err != nil {
+		return nil, err
+	}
+
+	poll, ok := polls[messageID]
+	if !ok {
+		return nil, ErrPollNotFound
+	}
+
+	return poll, nil
+}
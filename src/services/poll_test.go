@@ -0,0 +1,156 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPollService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	polls := mock_repositories.NewMockPoll(ctrl)
+	accountService := mock_services.NewMockAccount(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("polls.max_options", 3)
+
+	pollService := services.NewPoll(services.PollOptions{
+		Logger:         zap.NewNop().Sugar(),
+		Config:         config,
+		MessageRepo:    messages,
+		PollRepo:       polls,
+		AccountService: accountService,
+		Notifier:       notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	t.Run("Create", func(t *testing.T) {
+		for name, req := range map[string]services.PollRequest{
+			"No question":       {Options: []string{"a", "b"}},
+			"Single option":     {Text: "?", Options: []string{"a"}},
+			"Too many options":  {Text: "?", Options: []string{"a", "b", "c", "d"}},
+			"Duplicate options": {Text: "?", Options: []string{"a", " a "}},
+			"Empty option":      {Text: "?", Options: []string{"a", ""}},
+		} {
+			message, err := pollService.Create(user, req)
+			require.Nil(t, message, name)
+			require.Equal(t, services.ErrMalformedPoll, err, name)
+		}
+
+		past := time.Now().Add(-time.Minute)
+		message, err := pollService.Create(user, services.PollRequest{Text: "?", Options: []string{"a", "b"}, ClosesAt: &past})
+		require.Nil(t, message)
+		require.Equal(t, services.ErrMalformedClosingAt, err)
+
+		created := &models.Message{Id: 10, UserId: 1, Type: models.MessagePoll, Text: "Lunch?"}
+		accountService.EXPECT().CreateMessage(user, gomock.Any()).DoAndReturn(func(user models.User, req services.MessageRequest) (*models.Message, error) {
+			require.Equal(t, "Lunch?", req.Text)
+			require.True(t, req.Poll.Multiple)
+			require.Len(t, req.Poll.Options, 2)
+			require.Equal(t, "pizza", req.Poll.Options[0].Text)
+			return created, nil
+		})
+		notifier.EXPECT().Message(*created)
+
+		message, err = pollService.Create(user, services.PollRequest{Text: "Lunch?", Options: []string{"pizza ", "sushi"}, Multiple: true})
+		require.NoError(t, err)
+		require.Equal(t, created, message)
+	})
+
+	t.Run("Vote", func(t *testing.T) {
+		message := &models.Message{Id: 10, UserId: 2, Type: models.MessagePoll}
+		poll := &models.Poll{
+			MessageId: 10,
+			Options:   []models.PollOption{{Id: 1, Text: "pizza"}, {Id: 2, Text: "sushi"}},
+		}
+
+		t.Run("Foreign message", func(t *testing.T) {
+			messages.EXPECT().Find(int64(11)).Return(&models.Message{Id: 11, UserId: 2, ReceiverId: 3}, nil)
+
+			_, err := pollService.Vote(user, 11, []int64{1})
+			require.Equal(t, services.ErrMessageNotFound, err)
+		})
+
+		t.Run("Not a poll", func(t *testing.T) {
+			messages.EXPECT().Find(int64(12)).Return(&models.Message{Id: 12, UserId: 2}, nil)
+			polls.EXPECT().ForMessages(user, []int64{12}).Return(map[int64]*models.Poll{}, nil)
+
+			_, err := pollService.Vote(user, 12, []int64{1})
+			require.Equal(t, services.ErrPollNotFound, err)
+		})
+
+		t.Run("Closed", func(t *testing.T) {
+			closed := *poll
+			closesAt := time.Now().Add(-time.Minute)
+			closed.ClosesAt = &closesAt
+
+			messages.EXPECT().Find(int64(10)).Return(message, nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: &closed}, nil)
+
+			_, err := pollService.Vote(user, 10, []int64{1})
+			require.Equal(t, services.ErrPollClosed, err)
+		})
+
+		t.Run("Unknown option", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(message, nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: poll}, nil)
+
+			_, err := pollService.Vote(user, 10, []int64{3})
+			require.Equal(t, services.ErrMalformedVote, err)
+		})
+
+		t.Run("Several options in single choice poll", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(message, nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: poll}, nil)
+
+			_, err := pollService.Vote(user, 10, []int64{1, 2})
+			require.Equal(t, services.ErrMalformedVote, err)
+		})
+
+		t.Run("Several options rejected while voting", func(t *testing.T) {
+			multiple := *poll
+			multiple.Multiple = true
+
+			messages.EXPECT().Find(int64(10)).Return(message, nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: &multiple}, nil)
+			polls.EXPECT().Vote(gomock.Any(), []int64{1, 2}).Return(repositories.ErrSingleChoice)
+
+			_, err := pollService.Vote(user, 10, []int64{1, 2})
+			require.Equal(t, services.ErrMalformedVote, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			tallied := &models.Poll{
+				MessageId: 10,
+				Options:   []models.PollOption{{Id: 1, Text: "pizza", Votes: 1, Me: true}, {Id: 2, Text: "sushi"}},
+				Voters:    1,
+			}
+
+			messages.EXPECT().Find(int64(10)).Return(message, nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: poll}, nil)
+			polls.EXPECT().Vote(gomock.Any(), []int64{1}).Return(nil)
+			polls.EXPECT().ForMessages(user, []int64{10}).Return(map[int64]*models.Poll{10: tallied}, nil)
+			notifier.EXPECT().PollUpdated(gomock.Any()).Do(func(message models.Message) {
+				require.Equal(t, tallied, message.Poll)
+			})
+
+			result, err := pollService.Vote(user, 10, []int64{1, 1})
+			require.NoError(t, err)
+			require.Equal(t, tallied, result)
+		})
+	})
+}
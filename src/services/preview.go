@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Preview interface {
+		// Unfurl fetches previews of links found in message in background,
+		// clients receive updated message once previews are ready
+		Unfurl(message models.Message)
+		// Attach fills previews of messages
+		Attach(messages []models.Message) error
+	}
+
+	PreviewOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		PreviewRepo repositories.Preview
+		Unfurler    providers.Unfurler
+		Notifier    Notifier
+	}
+
+	previewService struct {
+		logger      *zap.SugaredLogger
+		previewRepo repositories.Preview
+		unfurler    providers.Unfurler
+		notifier    Notifier
+
+		maxLinks int
+		ttl      time.Duration
+		timeout  time.Duration
+		workers  chan struct{}
+	}
+)
+
+var linkRegex = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+func NewPreview(opts PreviewOptions) Preview {
+	opts.Config.SetDefault("previews.max_links", 3)
+	opts.Config.SetDefault("previews.ttl", 24*time.Hour)
+	opts.Config.SetDefault("previews.timeout", 5*time.Second)
+	opts.Config.SetDefault("previews.workers", 4)
+
+	return &previewService{
+		logger:      opts.Logger.Named("preview_service"),
+		previewRepo: opts.PreviewRepo,
+		unfurler:    opts.Unfurler,
+		notifier:    opts.Notifier,
+		maxLinks:    opts.Config.GetInt("previews.max_links"),
+		ttl:         opts.Config.GetDuration("previews.ttl"),
+		timeout:     opts.Config.GetDuration("previews.timeout"),
+		workers:     make(chan struct{}, opts.Config.GetInt("previews.workers")),
+	}
+}
+
+func (p *previewService) Unfurl(message models.Message) {
+	links := parseLinks(message.Text, p.maxLinks)
+	if len(links) == 0 {
+		return
+	}
+
+	go func() {
+		p.workers <- struct{}{}
+		defer func() { <-p.workers }()
+
+		p.unfurl(message, links)
+	}()
+}
+
+func (p *previewService) unfurl(message models.Message, links []string) {
+	var (
+		previews []models.Preview
+		urls     []string
+	)
+
+	for _, link := range links {
+		preview, err := p.preview(link)
+		if err != nil {
+			p.logger.Errorf("error getting preview of %s: %v", link, err)
+			continue
+		}
+
+		if preview.Failed || preview.Empty() {
+			continue
+		}
+
+		previews = append(previews, *preview)
+		urls = append(urls, link)
+	}
+
+	if len(previews) == 0 {
+		return
+	}
+
+	if err := p.previewRepo.Link(message.Id, urls); err != nil {
+		p.logger.Errorf("error linking previews of message %d: %v", message.Id, err)
+		return
+	}
+
+	message.Previews = previews
+	p.notifier.MessageUpdated(message)
+}
+
+// preview returns cached preview of link or fetches it, failures are cached
+// as well so broken links are not requested on every message
+func (p *previewService) preview(link string) (*models.Preview, error) {
+	cached, err := p.previewRepo.Find(link)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && time.Since(cached.FetchedAt) < p.ttl {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	preview := &models.Preview{
+		URL:       link,
+		FetchedAt: time.Now(),
+	}
+
+	meta, err := p.unfurler.Unfurl(ctx, link)
+	if err != nil {
+		p.logger.Debugf("error fetching %s: %v", link, err)
+		preview.Failed = true
+	} else {
+		preview.Title = meta.Title
+		preview.Description = meta.Description
+		preview.Image = meta.Image
+		preview.SiteName = meta.SiteName
+	}
+
+	if err := p.previewRepo.Save(preview); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (p *previewService) Attach(messages []models.Message) error {
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	previews, err := p.previewRepo.ForMessages(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Previews = previews[messages[i].Id]
+	}
+
+	return nil
+}
+
+// parseLinks returns unique http links found in text, punctuation
+// right after link is treated as part of the sentence
+func parseLinks(text string, limit int) []string {
+	var links []string
+	seen := make(map[string]bool)
+
+	for _, match := range linkRegex.FindAllString(text, -1) {
+		if len(links) >= limit {
+			break
+		}
+
+		link := strings.TrimRight(match, ".,;:!?)]}")
+		if u, err := url.Parse(link); err != nil || u.Host == "" {
+			continue
+		}
+
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+
+		links = append(links, link)
+	}
+
+	return links
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	mock_providers "github.com/playneta/go-sessions/src/providers/mocks"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPreviewService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	previews := mock_repositories.NewMockPreview(ctrl)
+	unfurler := mock_providers.NewMockUnfurler(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("previews.max_links", 2)
+
+	previewService := services.NewPreview(services.PreviewOptions{
+		Logger:      zap.NewNop().Sugar(),
+		Config:      config,
+		PreviewRepo: previews,
+		Unfurler:    unfurler,
+		Notifier:    notifier,
+	})
+
+	wait := func(t *testing.T, done chan struct{}) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("previews were not processed")
+		}
+	}
+
+	t.Run("No links", func(t *testing.T) {
+		previewService.Unfurl(models.Message{Id: 1, Text: "no links here, http:// neither"})
+	})
+
+	t.Run("Cached and fetched", func(t *testing.T) {
+		done := make(chan struct{})
+		cached := &models.Preview{URL: "https://cached.example/a", Title: "Cached", FetchedAt: time.Now()}
+
+		previews.EXPECT().Find("https://cached.example/a").Return(cached, nil)
+		previews.EXPECT().Find("http://new.example/b?c=d").Return(&models.Preview{
+			URL:       "http://new.example/b?c=d",
+			Title:     "Stale",
+			FetchedAt: time.Now().Add(-48 * time.Hour),
+		}, nil)
+		unfurler.EXPECT().Unfurl(gomock.Any(), "http://new.example/b?c=d").Return(&providers.LinkMeta{
+			Title: "Fresh",
+			Image: "http://new.example/image.png",
+		}, nil)
+		previews.EXPECT().Save(gomock.Any()).DoAndReturn(func(preview *models.Preview) error {
+			require.False(t, preview.Failed)
+			require.Equal(t, "Fresh", preview.Title)
+			return nil
+		})
+		previews.EXPECT().Link(int64(2), []string{"https://cached.example/a", "http://new.example/b?c=d"}).Return(nil)
+		notifier.EXPECT().MessageUpdated(gomock.Any()).Do(func(message models.Message) {
+			require.Equal(t, int64(2), message.Id)
+			require.Len(t, message.Previews, 2)
+			require.Equal(t, "Cached", message.Previews[0].Title)
+			require.Equal(t, "Fresh", message.Previews[1].Title)
+			close(done)
+		})
+
+		previewService.Unfurl(models.Message{
+			Id:   2,
+			Text: "see https://cached.example/a, (https://cached.example/a) and http://new.example/b?c=d. also https://third.example",
+		})
+		wait(t, done)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		done := make(chan struct{})
+
+		previews.EXPECT().Find("https://broken.example").Return(nil, nil)
+		unfurler.EXPECT().Unfurl(gomock.Any(), "https://broken.example").Return(nil, providers.ErrForbiddenAddress)
+		previews.EXPECT().Save(gomock.Any()).DoAndReturn(func(preview *models.Preview) error {
+			require.True(t, preview.Failed)
+			close(done)
+			return nil
+		})
+
+		previewService.Unfurl(models.Message{Id: 3, Text: "https://broken.example"})
+		wait(t, done)
+	})
+
+	t.Run("Attach", func(t *testing.T) {
+		previews.EXPECT().ForMessages([]int64{1, 2}).Return(map[int64][]models.Preview{
+			2: {{URL: "https://cached.example/a", Title: "Cached"}},
+		}, nil)
+
+		messages := []models.Message{{Id: 1}, {Id: 2}}
+		require.NoError(t, previewService.Attach(messages))
+		require.Nil(t, messages[0].Previews)
+		require.Len(t, messages[1].Previews, 1)
+
+		previews.EXPECT().ForMessages([]int64{1}).Return(nil, errors.New("error"))
+		require.Error(t, previewService.Attach([]models.Message{{Id: 1}}))
+	})
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Reaction interface {
+		Add(user models.User, messageID int64, emoji string) (*models.Message, error)
+		Remove(user models.User, messageID int64, emoji string) (*models.Message, error)
+	}
+
+	ReactionOptions struct {
+		fx.In
+
+		Logger       *zap.SugaredLogger
+		MessageRepo  repositories.Message
+		ReactionRepo repositories.Reaction
+		Notifier     Notifier
+	}
+
+	reactionService struct {
+		logger       *zap.SugaredLogger
+		messageRepo  repositories.Message
+		reactionRepo repositories.Reaction
+		notifier     Notifier
+	}
+)
+
+const maxEmojiLength = 32
+
+var (
+	ErrMessageNotFound = errors.New("message not found")
+	ErrMalformedEmoji  = errors.New("malformed emoji")
+)
+
+func NewReaction(opts ReactionOptions) Reaction {
+	return &reactionService{
+		logger:       opts.Logger.Named("reaction_service"),
+		messageRepo:  opts.MessageRepo,
+		reactionRepo: opts.ReactionRepo,
+		notifier:     opts.Notifier,
+	}
+}
+
+func (r *reactionService) Add(user models.User, messageID int64, emoji string) (*models.Message, error) {
+	message, reaction, err := r.prepare(user, messageID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.reactionRepo.Create(reaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.summarize(user, message); err != nil {
+		return nil, err
+	}
+
+	if created {
+		r.notifier.ReactionAdded(*message, *reaction)
+	}
+
+	return message, nil
+}
+
+func (r *reactionService) Remove(user models.User, messageID int64, emoji string) (*models.Message, error) {
+	message, reaction, err := r.prepare(user, messageID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := r.reactionRepo.Delete(reaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.summarize(user, message); err != nil {
+		return nil, err
+	}
+
+	if deleted {
+		r.notifier.ReactionRemoved(*message, *reaction)
+	}
+
+	return message, nil
+}
+
+func (r *reactionService) prepare(user models.User, messageID int64, emoji string) (*models.Message, *models.Reaction, error) {
+	if !validEmoji(emoji) {
+		return nil, nil, ErrMalformedEmoji
+	}
+
+	message, err := r.messageRepo.Find(messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if message == nil || !message.VisibleTo(user) {
+		return nil, nil, ErrMessageNotFound
+	}
+
+	return message, &models.Reaction{
+		MessageId: message.Id,
+		UserId:    user.ID,
+		User:      &user,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (r *reactionService) summarize(user models.User, message *models.Message) error {
+	summaries, err := r.reactionRepo.Summaries(user, []int64{message.Id})
+	if err != nil {
+		return err
+	}
+
+	message.Reactions = summaries[message.Id]
+	return nil
+}
+
+// validEmoji accepts any short printable token, either unicode emoji or :shortcode:
+func validEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > maxEmojiLength || !utf8.ValidString(emoji) {
+		return false
+	}
+
+	return strings.IndexFunc(emoji, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) < 0
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReactionService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	reactions := mock_repositories.NewMockReaction(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	reactionService := services.NewReaction(services.ReactionOptions{
+		Logger:       zap.NewNop().Sugar(),
+		MessageRepo:  messages,
+		ReactionRepo: reactions,
+		Notifier:     notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+	summary := map[int64][]models.ReactionSummary{
+		10: {{Emoji: "👍", Count: 1, Me: true}},
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		t.Run("Errors", func(t *testing.T) {
+			t.Run("Malformed emoji", func(t *testing.T) {
+				for _, emoji := range []string{"", "two words", "\x00", "very-long-emoji-name-that-is-not-emoji"} {
+					message, err := reactionService.Add(user, 10, emoji)
+					require.Nil(t, message)
+					require.Equal(t, services.ErrMalformedEmoji, err)
+				}
+			})
+
+			t.Run("Unknown message", func(t *testing.T) {
+				messages.EXPECT().Find(int64(10)).Return(nil, nil)
+
+				message, err := reactionService.Add(user, 10, "👍")
+				require.Nil(t, message)
+				require.Equal(t, services.ErrMessageNotFound, err)
+			})
+
+			t.Run("Foreign private message", func(t *testing.T) {
+				messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2, ReceiverId: 3}, nil)
+
+				message, err := reactionService.Add(user, 10, "👍")
+				require.Nil(t, message)
+				require.Equal(t, services.ErrMessageNotFound, err)
+			})
+
+			t.Run("Database error", func(t *testing.T) {
+				messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2}, nil)
+				reactions.EXPECT().Create(gomock.Any()).Return(false, errors.New("database error"))
+
+				message, err := reactionService.Add(user, 10, "👍")
+				require.Nil(t, message)
+				require.Error(t, err)
+			})
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2}, nil)
+			reactions.EXPECT().Create(gomock.Any()).Return(true, nil)
+			reactions.EXPECT().Summaries(user, []int64{10}).Return(summary, nil)
+			notifier.EXPECT().ReactionAdded(gomock.Any(), gomock.Any())
+
+			message, err := reactionService.Add(user, 10, "👍")
+			require.NoError(t, err)
+			require.Equal(t, summary[10], message.Reactions)
+		})
+
+		t.Run("Duplicate is not broadcasted", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2}, nil)
+			reactions.EXPECT().Create(gomock.Any()).Return(false, nil)
+			reactions.EXPECT().Summaries(user, []int64{10}).Return(summary, nil)
+
+			message, err := reactionService.Add(user, 10, "👍")
+			require.NoError(t, err)
+			require.Equal(t, summary[10], message.Reactions)
+		})
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 1, ReceiverId: 2}, nil)
+			reactions.EXPECT().Delete(gomock.Any()).Return(true, nil)
+			reactions.EXPECT().Summaries(user, []int64{10}).Return(map[int64][]models.ReactionSummary{}, nil)
+			notifier.EXPECT().ReactionRemoved(gomock.Any(), gomock.Any())
+
+			message, err := reactionService.Remove(user, 10, "👍")
+			require.NoError(t, err)
+			require.Empty(t, message.Reactions)
+		})
+
+		t.Run("Missing reaction is not broadcasted", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 1, ReceiverId: 2}, nil)
+			reactions.EXPECT().Delete(gomock.Any()).Return(false, nil)
+			reactions.EXPECT().Summaries(user, []int64{10}).Return(map[int64][]models.ReactionSummary{}, nil)
+
+			_, err := reactionService.Remove(user, 10, "👍")
+			require.NoError(t, err)
+		})
+	})
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Read interface {
+		MarkRead(user models.User, messageID int64) (*models.ReadMarker, error)
+		State(user models.User) ([]models.ReadState, error)
+		ConversationState(user models.User, conversation string) (*models.ReadState, error)
+	}
+
+	ReadOptions struct {
+		fx.In
+
+		Logger         *zap.SugaredLogger
+		MessageRepo    repositories.Message
+		ReadMarkerRepo repositories.ReadMarker
+		Notifier       Notifier
+	}
+
+	readService struct {
+		logger         *zap.SugaredLogger
+		messageRepo    repositories.Message
+		readMarkerRepo repositories.ReadMarker
+		notifier       Notifier
+	}
+)
+
+var ErrConversationNotFound = errors.New("conversation not found")
+
+func NewRead(opts ReadOptions) Read {
+	return &readService{
+		logger:         opts.Logger.Named("read_service"),
+		messageRepo:    opts.MessageRepo,
+		readMarkerRepo: opts.ReadMarkerRepo,
+		notifier:       opts.Notifier,
+	}
+}
+
+// MarkRead moves read marker of conversation up to given message and sends
+// read receipt to the other participant of private conversation
+func (r *readService) MarkRead(user models.User, messageID int64) (*models.ReadMarker, error) {
+	message, err := r.messageRepo.Find(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil || !message.VisibleTo(user) {
+		return nil, ErrMessageNotFound
+	}
+
+	marker := &models.ReadMarker{
+		UserId:       user.ID,
+		Conversation: message.Conversation(),
+		MessageId:    message.Id,
+		UpdatedAt:    time.Now(),
+	}
+
+	moved, err := r.readMarkerRepo.Mark(marker)
+	if err != nil {
+		return nil, err
+	}
+
+	if moved && message.Receiver != nil {
+		r.notifier.Read(*message, user)
+	}
+
+	return marker, nil
+}
+
+// State returns read state of every conversation user has read or has unread messages in
+func (r *readService) State(user models.User) ([]models.ReadState, error) {
+	markers, err := r.readMarkerRepo.ForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := r.readMarkerRepo.Unread(user)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]*models.ReadState)
+	for _, marker := range markers {
+		states[marker.Conversation] = &models.ReadState{
+			Conversation: marker.Conversation,
+			LastReadId:   marker.MessageId,
+		}
+	}
+
+	for conversation, count := range unread {
+		if _, ok := states[conversation]; !ok {
+			states[conversation] = &models.ReadState{Conversation: conversation}
+		}
+		states[conversation].Unread = count
+	}
+
+	result := make([]models.ReadState, 0, len(states))
+	for _, state := range states {
+		result = append(result, *state)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Conversation < result[j].Conversation
+	})
+
+	// What partners have read is loaded at once for every private conversation
+	var private []string
+	for _, state := range result {
+		if state.Conversation != models.PublicConversation {
+			private = append(private, state.Conversation)
+		}
+	}
+
+	partners, err := r.readMarkerRepo.Partners(user, private)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, state := range result {
+		if state.Conversation == models.PublicConversation {
+			continue
+		}
+
+		// In conversation with themselves user is their own partner
+		if models.ConversationPartner(state.Conversation, user) == user.ID {
+			result[i].PartnerReadId = state.LastReadId
+		} else {
+			result[i].PartnerReadId = partners[state.Conversation]
+		}
+	}
+
+	return result, nil
+}
+
+func (r *readService) ConversationState(user models.User, conversation string) (*models.ReadState, error) {
+	if !models.ConversationVisibleTo(conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	state := &models.ReadState{Conversation: conversation}
+
+	marker, err := r.readMarkerRepo.Find(user.ID, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	if marker != nil {
+		state.LastReadId = marker.MessageId
+	}
+
+	state.Unread, err = r.readMarkerRepo.UnreadIn(user, conversation, state.LastReadId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.partnerState(user, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// partnerState fills what the other participant of private conversation has read
+func (r *readService) partnerState(user models.User, state *models.ReadState) error {
+	if state.Conversation == models.PublicConversation {
+		return nil
+	}
+
+	marker, err := r.readMarkerRepo.Find(models.ConversationPartner(state.Conversation, user), state.Conversation)
+	if err != nil {
+		return err
+	}
+
+	if marker != nil {
+		state.PartnerReadId = marker.MessageId
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReadService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	markers := mock_repositories.NewMockReadMarker(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	readService := services.NewRead(services.ReadOptions{
+		Logger:         zap.NewNop().Sugar(),
+		MessageRepo:    messages,
+		ReadMarkerRepo: markers,
+		Notifier:       notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+	partner := &models.User{ID: 2, Email: "partner@example.com"}
+
+	t.Run("MarkRead", func(t *testing.T) {
+		t.Run("Foreign message", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2, ReceiverId: 3}, nil)
+
+			marker, err := readService.MarkRead(user, 10)
+			require.Nil(t, marker)
+			require.Equal(t, services.ErrMessageNotFound, err)
+		})
+
+		t.Run("Public message has no receipts", func(t *testing.T) {
+			messages.EXPECT().Find(int64(10)).Return(&models.Message{Id: 10, UserId: 2, User: partner}, nil)
+			markers.EXPECT().Mark(gomock.Any()).Return(true, nil)
+
+			marker, err := readService.MarkRead(user, 10)
+			require.NoError(t, err)
+			require.Equal(t, models.PublicConversation, marker.Conversation)
+			require.Equal(t, int64(10), marker.MessageId)
+		})
+
+		t.Run("Private message sends receipt", func(t *testing.T) {
+			message := &models.Message{Id: 11, UserId: 2, User: partner, ReceiverId: 1, Receiver: &user}
+			messages.EXPECT().Find(int64(11)).Return(message, nil)
+			markers.EXPECT().Mark(gomock.Any()).Return(true, nil)
+			notifier.EXPECT().Read(*message, user)
+
+			marker, err := readService.MarkRead(user, 11)
+			require.NoError(t, err)
+			require.Equal(t, "dm:1:2", marker.Conversation)
+		})
+
+		t.Run("Stale marker sends nothing", func(t *testing.T) {
+			message := &models.Message{Id: 9, UserId: 2, User: partner, ReceiverId: 1, Receiver: &user}
+			messages.EXPECT().Find(int64(9)).Return(message, nil)
+			markers.EXPECT().Mark(gomock.Any()).Return(false, nil)
+
+			_, err := readService.MarkRead(user, 9)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("State", func(t *testing.T) {
+		markers.EXPECT().ForUser(user).Return([]models.ReadMarker{
+			{UserId: 1, Conversation: "public", MessageId: 20},
+			{UserId: 1, Conversation: "dm:1:2", MessageId: 11},
+			{UserId: 1, Conversation: "dm:1:1", MessageId: 7},
+		}, nil)
+		markers.EXPECT().Unread(user).Return(map[string]int{"public": 2, "dm:1:3": 1}, nil)
+		markers.EXPECT().Partners(user, []string{"dm:1:1", "dm:1:2", "dm:1:3"}).Return(map[string]int64{"dm:1:2": 12}, nil)
+
+		states, err := readService.State(user)
+		require.NoError(t, err)
+		require.Equal(t, []models.ReadState{
+			{Conversation: "dm:1:1", LastReadId: 7, PartnerReadId: 7},
+			{Conversation: "dm:1:2", LastReadId: 11, PartnerReadId: 12},
+			{Conversation: "dm:1:3", Unread: 1},
+			{Conversation: "public", LastReadId: 20, Unread: 2},
+		}, states)
+	})
+
+	t.Run("ConversationState", func(t *testing.T) {
+		t.Run("Foreign conversation", func(t *testing.T) {
+			for _, conversation := range []string{"dm:2:3", "dm:2", "private", "", "dm:1:02", "dm:+1:2", "dm:01:2"} {
+				state, err := readService.ConversationState(user, conversation)
+				require.Nil(t, state)
+				require.Equal(t, services.ErrConversationNotFound, err)
+			}
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			markers.EXPECT().Find(int64(1), "dm:1:2").Return(&models.ReadMarker{MessageId: 11}, nil)
+			markers.EXPECT().UnreadIn(user, "dm:1:2", int64(11)).Return(4, nil)
+			markers.EXPECT().Find(int64(2), "dm:1:2").Return(nil, nil)
+
+			state, err := readService.ConversationState(user, "dm:1:2")
+			require.NoError(t, err)
+			require.Equal(t, &models.ReadState{Conversation: "dm:1:2", LastReadId: 11, Unread: 4}, state)
+		})
+	})
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Retention interface {
+		// Prune deletes messages outside of retention policies, archived ones included,
+		// and returns number of deleted messages per scope, dry run only counts them
+		Prune(dryRun bool) (map[string]int, error)
+	}
+
+	RetentionOptions struct {
+		fx.In
+
+		Logger         *zap.SugaredLogger
+		Config         *viper.Viper
+		Lc             fx.Lifecycle
+		MessageRepo    repositories.Message
+		AttachmentRepo repositories.Attachment
+		Store          providers.BlobStore
+		ArchiveService Archive
+	}
+
+	retentionService struct {
+		logger         *zap.SugaredLogger
+		messageRepo    repositories.Message
+		attachmentRepo repositories.Attachment
+		store          providers.BlobStore
+		archive        Archive
+		policies       []models.RetentionPolicy
+		batch          int
+	}
+)
+
+// retentionMetrics are published at /debug/vars, dry runs are not counted
+var retentionMetrics = expvar.NewMap("retention")
+
+// NewRetention creates pruner of messages outside of retention policies running in background,
+// policies are disabled by default so nothing is deleted until they are configured
+func NewRetention(opts RetentionOptions) Retention {
+	opts.Config.SetDefault("retention.interval", time.Hour)
+	opts.Config.SetDefault("retention.batch", 500)
+
+	r := &retentionService{
+		logger:         opts.Logger.Named("retention_service"),
+		messageRepo:    opts.MessageRepo,
+		attachmentRepo: opts.AttachmentRepo,
+		store:          opts.Store,
+		archive:        opts.ArchiveService,
+		batch:          opts.Config.GetInt("retention.batch"),
+	}
+
+	for _, scope := range []string{models.RetentionPublic, models.RetentionPrivate} {
+		r.policies = append(r.policies, models.RetentionPolicy{
+			Scope:    scope,
+			MaxAge:   opts.Config.GetDuration("retention." + scope + ".max_age"),
+			MaxCount: opts.Config.GetInt("retention." + scope + ".max_count"),
+		})
+	}
+
+	startWorker(opts.Lc, opts.Config.GetDuration("retention.interval"), func() bool {
+		if _, err := r.Prune(false); err != nil {
+			r.logger.Errorf("error pruning messages: %v", err)
+		}
+
+		return false
+	})
+
+	return r
+}
+
+func (r *retentionService) Prune(dryRun bool) (map[string]int, error) {
+	now := time.Now()
+	counts := make(map[string]int, len(r.policies))
+
+	var err error
+	for _, policy := range r.policies {
+		if !policy.Enabled() {
+			continue
+		}
+
+		if dryRun {
+			counts[policy.Scope], err = r.messageRepo.CountPrunable(policy, now)
+		} else {
+			counts[policy.Scope], err = r.prune(policy, now)
+		}
+
+		if err != nil {
+			break
+		}
+
+		// Archived messages are older than those in the table, which are pruned first
+		// so count limit sees how many of them are left
+		var archived int
+		archived, err = r.archive.Prune(policy, now, dryRun)
+		counts[policy.Scope] += archived
+
+		if err != nil {
+			break
+		}
+	}
+
+	r.report(counts, dryRun, err)
+	return counts, err
+}
+
+// prune deletes messages outside of policy in batches along with blobs of their attachments,
+// messages deleted by another instance meanwhile are not counted
+func (r *retentionService) prune(policy models.RetentionPolicy, now time.Time) (int, error) {
+	total := 0
+	for {
+		ids, err := r.messageRepo.Prunable(policy, now, r.batch)
+		if err != nil {
+			return total, err
+		}
+
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		attachments, err := r.attachmentRepo.ForMessages(ids)
+		if err != nil {
+			return total, err
+		}
+
+		deleted, err := r.messageRepo.Delete(ids)
+		if err != nil {
+			return total, err
+		}
+		total += len(deleted)
+
+		for _, id := range deleted {
+			for _, attachment := range attachments[id] {
+				if err := r.store.Delete(context.Background(), attachment.Key); err != nil {
+					r.logger.Errorf("error deleting blob %s of pruned message: %v", attachment.Key, err)
+				}
+			}
+		}
+
+		if len(ids) < r.batch {
+			return total, nil
+		}
+	}
+}
+
+// report logs counts of the run and adds deleted messages to metrics,
+// failed runs still report what they deleted before the failure
+func (r *retentionService) report(counts map[string]int, dryRun bool, err error) {
+	if dryRun {
+		r.logger.Infof("messages to prune: public=%d private=%d",
+			counts[models.RetentionPublic], counts[models.RetentionPrivate])
+		return
+	}
+
+	r.logger.Infof("pruned messages: public=%d private=%d",
+		counts[models.RetentionPublic], counts[models.RetentionPrivate])
+
+	retentionMetrics.Add("runs", 1)
+	if err != nil {
+		retentionMetrics.Add("errors", 1)
+	}
+
+	for scope, count := range counts {
+		retentionMetrics.Add("deleted_"+scope, int64(count))
+	}
+}
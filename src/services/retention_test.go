@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_providers "github.com/playneta/go-sessions/src/providers/mocks"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRetentionService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	attachments := mock_repositories.NewMockAttachment(ctrl)
+	store := mock_providers.NewMockBlobStore(ctrl)
+	archive := mock_services.NewMockArchive(ctrl)
+
+	config := viper.New()
+	config.Set("retention.batch", 2)
+	config.Set("retention.public.max_age", "720h")
+	config.Set("retention.private.max_count", 100)
+
+	retentionService := services.NewRetention(services.RetentionOptions{
+		Logger:         zap.NewNop().Sugar(),
+		Config:         config,
+		MessageRepo:    messages,
+		AttachmentRepo: attachments,
+		Store:          store,
+		ArchiveService: archive,
+	})
+
+	public := models.RetentionPolicy{Scope: models.RetentionPublic, MaxAge: 720 * time.Hour}
+	private := models.RetentionPolicy{Scope: models.RetentionPrivate, MaxCount: 100}
+
+	t.Run("Disabled", func(t *testing.T) {
+		disabled := services.NewRetention(services.RetentionOptions{
+			Logger:         zap.NewNop().Sugar(),
+			Config:         viper.New(),
+			MessageRepo:    messages,
+			AttachmentRepo: attachments,
+			Store:          store,
+		})
+
+		counts, err := disabled.Prune(false)
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		messages.EXPECT().CountPrunable(public, gomock.Any()).Return(3, nil)
+		messages.EXPECT().CountPrunable(private, gomock.Any()).Return(0, nil)
+		archive.EXPECT().Prune(public, gomock.Any(), true).Return(4, nil)
+		archive.EXPECT().Prune(private, gomock.Any(), true).Return(0, nil)
+
+		counts, err := retentionService.Prune(true)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{models.RetentionPublic: 7, models.RetentionPrivate: 0}, counts)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		messages.EXPECT().Prunable(public, gomock.Any(), 2).Return(nil, errors.New("db error"))
+
+		_, err := retentionService.Prune(false)
+		require.Error(t, err)
+	})
+
+	t.Run("Batches", func(t *testing.T) {
+		gomock.InOrder(
+			messages.EXPECT().Prunable(public, gomock.Any(), 2).Return([]int64{1, 2}, nil),
+			messages.EXPECT().Prunable(public, gomock.Any(), 2).Return([]int64{3}, nil),
+		)
+		attachments.EXPECT().ForMessages([]int64{1, 2}).Return(map[int64][]models.Attachment{
+			2: {{Id: 5, MessageId: 2, Key: "attachments/1/a"}},
+		}, nil)
+		attachments.EXPECT().ForMessages([]int64{3}).Return(map[int64][]models.Attachment{}, nil)
+		messages.EXPECT().Delete([]int64{1, 2}).Return([]int64{1, 2}, nil)
+		store.EXPECT().Delete(context.Background(), "attachments/1/a").Return(nil)
+
+		// message 3 was pruned by another instance in the meantime
+		messages.EXPECT().Delete([]int64{3}).Return([]int64{}, nil)
+		messages.EXPECT().Prunable(private, gomock.Any(), 2).Return([]int64{}, nil)
+
+		// archived messages are pruned after those in the table
+		archive.EXPECT().Prune(public, gomock.Any(), false).Return(5, nil)
+		archive.EXPECT().Prune(private, gomock.Any(), false).Return(1, nil)
+
+		counts, err := retentionService.Prune(false)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{models.RetentionPublic: 7, models.RetentionPrivate: 1}, counts)
+	})
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Schedule interface {
+		Create(user models.User, req ScheduleRequest) (*models.ScheduledMessage, error)
+		List(user models.User) ([]models.ScheduledMessage, error)
+		Update(user models.User, id int64, req ScheduleRequest) (*models.ScheduledMessage, error)
+		Cancel(user models.User, id int64) error
+		// Dispatch delivers messages which are due and returns number of processed ones
+		Dispatch() (int, error)
+	}
+
+	ScheduleOptions struct {
+		fx.In
+
+		Logger        *zap.SugaredLogger
+		Config        *viper.Viper
+		Lc            fx.Lifecycle
+		AccountRepo   repositories.User
+		ScheduledRepo repositories.ScheduledMessage
+
+		AccountService Account
+		PreviewService Preview
+		Notifier       Notifier
+	}
+
+	// ScheduleRequest describes message to be sent at given time
+	ScheduleRequest struct {
+		To     string
+		Text   string
+		Format string
+		SendAt time.Time
+	}
+
+	scheduleService struct {
+		logger        *zap.SugaredLogger
+		accountRepo   repositories.User
+		scheduledRepo repositories.ScheduledMessage
+		accounts      Account
+		previews      Preview
+		notifier      Notifier
+
+		interval   time.Duration
+		lease      time.Duration
+		batch      int
+		maxPending int
+		maxAhead   time.Duration
+		maxLength  int
+	}
+)
+
+var (
+	ErrScheduledNotFound = errors.New("scheduled message not found or already sent")
+	ErrTooManyScheduled  = errors.New("too many scheduled messages")
+	ErrSendAtInPast      = errors.New("send time must be in the future")
+	ErrSendAtTooFar      = errors.New("send time is too far in the future")
+	ErrReceiverNotFound  = errors.New("receiver not found")
+	ErrEmptyText         = errors.New("empty message text")
+)
+
+func NewSchedule(opts ScheduleOptions) Schedule {
+	opts.Config.SetDefault("schedule.interval", 5*time.Second)
+	opts.Config.SetDefault("schedule.lease", time.Minute)
+	opts.Config.SetDefault("schedule.batch", 50)
+	opts.Config.SetDefault("schedule.max_pending", 100)
+	opts.Config.SetDefault("schedule.max_ahead", 365*24*time.Hour)
+	opts.Config.SetDefault("messages.max_length", 16384)
+
+	s := &scheduleService{
+		logger:        opts.Logger.Named("schedule_service"),
+		accountRepo:   opts.AccountRepo,
+		scheduledRepo: opts.ScheduledRepo,
+		accounts:      opts.AccountService,
+		previews:      opts.PreviewService,
+		notifier:      opts.Notifier,
+		interval:      opts.Config.GetDuration("schedule.interval"),
+		lease:         opts.Config.GetDuration("schedule.lease"),
+		batch:         opts.Config.GetInt("schedule.batch"),
+		maxPending:    opts.Config.GetInt("schedule.max_pending"),
+		maxAhead:      opts.Config.GetDuration("schedule.max_ahead"),
+		maxLength:     opts.Config.GetInt("messages.max_length"),
+	}
+
+	startWorker(opts.Lc, s.interval, func() bool {
+		n, err := s.Dispatch()
+		if err != nil {
+			s.logger.Errorf("error dispatching scheduled messages: %v", err)
+			return false
+		}
+
+		// full batch means there could be more due messages
+		return n >= s.batch
+	})
+
+	return s
+}
+
+func (s *scheduleService) Create(user models.User, req ScheduleRequest) (*models.ScheduledMessage, error) {
+	message := &models.ScheduledMessage{
+		UserId:    user.ID,
+		Status:    models.ScheduledPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.fill(message, req); err != nil {
+		return nil, err
+	}
+
+	count, err := s.scheduledRepo.CountPending(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if count >= s.maxPending {
+		return nil, ErrTooManyScheduled
+	}
+
+	if err := s.scheduledRepo.Create(message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (s *scheduleService) List(user models.User) ([]models.ScheduledMessage, error) {
+	return s.scheduledRepo.Pending(user)
+}
+
+func (s *scheduleService) Update(user models.User, id int64, req ScheduleRequest) (*models.ScheduledMessage, error) {
+	message, err := s.find(user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fill(message, req); err != nil {
+		return nil, err
+	}
+	message.UpdatedAt = time.Now()
+
+	updated, err := s.scheduledRepo.Update(message, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if !updated {
+		return nil, ErrScheduledNotFound
+	}
+
+	return message, nil
+}
+
+func (s *scheduleService) Cancel(user models.User, id int64) error {
+	message, err := s.find(user, id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.scheduledRepo.Delete(message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrScheduledNotFound
+	}
+
+	return nil
+}
+
+func (s *scheduleService) find(user models.User, id int64) (*models.ScheduledMessage, error) {
+	message, err := s.scheduledRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil || message.UserId != user.ID || message.Status != models.ScheduledPending {
+		return nil, ErrScheduledNotFound
+	}
+
+	return message, nil
+}
+
+// fill validates request and copies it into scheduled message
+func (s *scheduleService) fill(message *models.ScheduledMessage, req ScheduleRequest) error {
+	if req.Text == "" {
+		return ErrEmptyText
+	}
+
+	// Checked on schedule too, otherwise message would only fail when it is due
+	if len(req.Text) > s.maxLength {
+		return ErrMessageTooLong
+	}
+
+	format, err := messageFormat(req.Text, req.Format)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !req.SendAt.After(now) {
+		return ErrSendAtInPast
+	}
+
+	if req.SendAt.Sub(now) > s.maxAhead {
+		return ErrSendAtTooFar
+	}
+
+	message.ReceiverId, message.Receiver = 0, nil
+	if req.To != "" {
+		receiver, err := s.accountRepo.FindByEmail(req.To)
+		if err != nil {
+			return err
+		}
+
+		if receiver == nil {
+			return ErrReceiverNotFound
+		}
+
+		message.ReceiverId, message.Receiver = receiver.ID, receiver
+	}
+
+	message.Text = req.Text
+	message.Format = format
+	message.SendAt = req.SendAt
+	return nil
+}
+
+func (s *scheduleService) Dispatch() (int, error) {
+	messages, err := s.scheduledRepo.Claim(time.Now(), s.lease, s.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range messages {
+		s.deliver(&messages[i])
+	}
+
+	return len(messages), nil
+}
+
+// deliver stores scheduled message and broadcasts it, unlike websocket sends it leaves
+// draft and typing state of the author alone since they belong to their live session,
+// failures are stored on scheduled message and never retried
+func (s *scheduleService) deliver(scheduled *models.ScheduledMessage) {
+	// Client id stores message claimed again after lost lease only once
+	req := MessageRequest{
+		ClientMsgId: fmt.Sprintf("scheduled:%d", scheduled.Id),
+		Text:        scheduled.Text,
+		Format:      scheduled.Format,
+	}
+
+	if scheduled.Receiver != nil {
+		req.To = scheduled.Receiver.Email
+	}
+
+	message, err := s.accounts.CreateMessage(*scheduled.User, req)
+
+	// Message was stored and broadcast by earlier delivery
+	duplicate := err == ErrDuplicateMessage
+	if duplicate {
+		err = nil
+	}
+
+	scheduled.UpdatedAt = time.Now()
+	if err != nil {
+		s.logger.Errorf("error sending scheduled message %d: %v", scheduled.Id, err)
+		scheduled.Status = models.ScheduledFailed
+		scheduled.Error = err.Error()
+	} else {
+		scheduled.Status = models.ScheduledSent
+		scheduled.MessageId = message.Id
+	}
+
+	if err := s.scheduledRepo.Finish(scheduled); err != nil {
+		s.logger.Errorf("error finishing scheduled message %d: %v", scheduled.Id, err)
+	}
+
+	if message != nil && !duplicate {
+		s.notifier.Message(*message)
+		s.previews.Unfurl(*message)
+	}
+}
@@ -0,0 +1,193 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	mock_services "github.com/playneta/go-sessions/src/services/mocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScheduleService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accounts := mock_repositories.NewMockUser(ctrl)
+	scheduled := mock_repositories.NewMockScheduledMessage(ctrl)
+	accountService := mock_services.NewMockAccount(ctrl)
+	previewService := mock_services.NewMockPreview(ctrl)
+	notifier := mock_services.NewMockNotifier(ctrl)
+
+	config := viper.New()
+	config.Set("schedule.max_pending", 2)
+	config.Set("schedule.batch", 10)
+
+	scheduleService := services.NewSchedule(services.ScheduleOptions{
+		Logger:         zap.NewNop().Sugar(),
+		Config:         config,
+		AccountRepo:    accounts,
+		ScheduledRepo:  scheduled,
+		AccountService: accountService,
+		PreviewService: previewService,
+		Notifier:       notifier,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+	friend := &models.User{ID: 2, Email: "friend@example.com"}
+	later := time.Now().Add(time.Hour)
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("In the past", func(t *testing.T) {
+			message, err := scheduleService.Create(user, services.ScheduleRequest{Text: "text", SendAt: time.Now().Add(-time.Minute)})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrSendAtInPast, err)
+		})
+
+		t.Run("Too far", func(t *testing.T) {
+			message, err := scheduleService.Create(user, services.ScheduleRequest{Text: "text", SendAt: time.Now().Add(400 * 24 * time.Hour)})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrSendAtTooFar, err)
+		})
+
+		t.Run("Too long", func(t *testing.T) {
+			message, err := scheduleService.Create(user, services.ScheduleRequest{Text: strings.Repeat("a", 16385), SendAt: later})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrMessageTooLong, err)
+		})
+
+		t.Run("Unknown receiver", func(t *testing.T) {
+			accounts.EXPECT().FindByEmail("unknown@example.com").Return(nil, nil)
+
+			message, err := scheduleService.Create(user, services.ScheduleRequest{To: "unknown@example.com", Text: "text", SendAt: later})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrReceiverNotFound, err)
+		})
+
+		t.Run("Too many", func(t *testing.T) {
+			scheduled.EXPECT().CountPending(user).Return(2, nil)
+
+			message, err := scheduleService.Create(user, services.ScheduleRequest{Text: "text", SendAt: later})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrTooManyScheduled, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			accounts.EXPECT().FindByEmail("friend@example.com").Return(friend, nil)
+			scheduled.EXPECT().CountPending(user).Return(1, nil)
+			scheduled.EXPECT().Create(gomock.Any()).Return(nil)
+
+			message, err := scheduleService.Create(user, services.ScheduleRequest{To: "friend@example.com", Text: "text", SendAt: later})
+			require.NoError(t, err)
+			require.Equal(t, friend.ID, message.ReceiverId)
+			require.Equal(t, models.FormatMarkdown, message.Format)
+			require.Equal(t, models.ScheduledPending, message.Status)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Foreign", func(t *testing.T) {
+			scheduled.EXPECT().Find(int64(5)).Return(&models.ScheduledMessage{Id: 5, UserId: 2, Status: models.ScheduledPending}, nil)
+
+			message, err := scheduleService.Update(user, 5, services.ScheduleRequest{Text: "text", SendAt: later})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrScheduledNotFound, err)
+		})
+
+		t.Run("Being delivered", func(t *testing.T) {
+			scheduled.EXPECT().Find(int64(5)).Return(&models.ScheduledMessage{Id: 5, UserId: 1, Status: models.ScheduledPending}, nil)
+			scheduled.EXPECT().Update(gomock.Any(), gomock.Any()).Return(false, nil)
+
+			message, err := scheduleService.Update(user, 5, services.ScheduleRequest{Text: "text", SendAt: later})
+			require.Nil(t, message)
+			require.Equal(t, services.ErrScheduledNotFound, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			scheduled.EXPECT().Find(int64(5)).Return(&models.ScheduledMessage{Id: 5, UserId: 1, ReceiverId: 2, Status: models.ScheduledPending}, nil)
+			scheduled.EXPECT().Update(gomock.Any(), gomock.Any()).Return(true, nil)
+
+			message, err := scheduleService.Update(user, 5, services.ScheduleRequest{Text: "new text", Format: models.FormatPlain, SendAt: later})
+			require.NoError(t, err)
+			require.Equal(t, "new text", message.Text)
+			require.Zero(t, message.ReceiverId)
+		})
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		scheduled.EXPECT().Find(int64(6)).Return(&models.ScheduledMessage{Id: 6, UserId: 1, Status: models.ScheduledSent}, nil)
+		require.Equal(t, services.ErrScheduledNotFound, scheduleService.Cancel(user, 6))
+
+		scheduled.EXPECT().Find(int64(7)).Return(&models.ScheduledMessage{Id: 7, UserId: 1, Status: models.ScheduledPending}, nil)
+		scheduled.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(true, nil)
+		require.NoError(t, scheduleService.Cancel(user, 7))
+	})
+
+	t.Run("Dispatch", func(t *testing.T) {
+		sent := &models.Message{Id: 100, UserId: 1, ReceiverId: 2, Text: "hello"}
+
+		scheduled.EXPECT().Claim(gomock.Any(), time.Minute, 10).Return([]models.ScheduledMessage{
+			{Id: 1, UserId: 1, User: &user, ReceiverId: 2, Receiver: friend, Text: "hello", Format: models.FormatMarkdown},
+			{Id: 2, UserId: 1, User: &user, Text: "@all hi", Format: models.FormatPlain},
+		}, nil)
+		accountService.EXPECT().CreateMessage(user, services.MessageRequest{
+			ClientMsgId: "scheduled:1",
+			To:          "friend@example.com",
+			Text:        "hello",
+			Format:      models.FormatMarkdown,
+		}).Return(sent, nil)
+		accountService.EXPECT().CreateMessage(user, services.MessageRequest{
+			ClientMsgId: "scheduled:2",
+			Text:        "@all hi",
+			Format:      models.FormatPlain,
+		}).Return(nil, services.ErrMentionNotAllowed)
+		scheduled.EXPECT().Finish(gomock.Any()).Do(func(message *models.ScheduledMessage) {
+			require.Equal(t, models.ScheduledSent, message.Status)
+			require.Equal(t, int64(100), message.MessageId)
+		})
+		scheduled.EXPECT().Finish(gomock.Any()).Do(func(message *models.ScheduledMessage) {
+			require.Equal(t, models.ScheduledFailed, message.Status)
+			require.Equal(t, services.ErrMentionNotAllowed.Error(), message.Error)
+		})
+		notifier.EXPECT().Message(*sent)
+		previewService.EXPECT().Unfurl(*sent)
+
+		n, err := scheduleService.Dispatch()
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		scheduled.EXPECT().Claim(gomock.Any(), time.Minute, 10).Return(nil, errors.New("error"))
+		_, err = scheduleService.Dispatch()
+		require.Error(t, err)
+	})
+
+	t.Run("Delivered before lease was lost", func(t *testing.T) {
+		sent := &models.Message{Id: 101, UserId: 1, Text: "again"}
+
+		scheduled.EXPECT().Claim(gomock.Any(), time.Minute, 10).Return([]models.ScheduledMessage{
+			{Id: 3, UserId: 1, User: &user, Text: "again", Format: models.FormatPlain},
+		}, nil)
+		accountService.EXPECT().CreateMessage(user, services.MessageRequest{
+			ClientMsgId: "scheduled:3",
+			Text:        "again",
+			Format:      models.FormatPlain,
+		}).Return(sent, services.ErrDuplicateMessage)
+
+		// message is not broadcast twice
+		scheduled.EXPECT().Finish(gomock.Any()).Do(func(message *models.ScheduledMessage) {
+			require.Equal(t, models.ScheduledSent, message.Status)
+			require.Equal(t, int64(101), message.MessageId)
+		})
+
+		n, err := scheduleService.Dispatch()
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Search interface {
+		Search(user models.User, req SearchRequest) (*models.SearchPage, error)
+	}
+
+	SearchOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		AccountRepo repositories.User
+		MessageRepo repositories.Message
+	}
+
+	// SearchRequest describes search query with optional filters,
+	// sender is email of message author
+	SearchRequest struct {
+		Query        string
+		Sender       string
+		Conversation string
+		Since        time.Time
+		Until        time.Time
+		Limit        int
+		Offset       int
+	}
+
+	searchService struct {
+		logger      *zap.SugaredLogger
+		accountRepo repositories.User
+		messageRepo repositories.Message
+	}
+)
+
+const maxSearchQuery = 256
+
+var ErrMalformedQuery = errors.New("search query must be between 1 and 256 characters")
+
+func NewSearch(opts SearchOptions) Search {
+	return &searchService{
+		logger:      opts.Logger.Named("search_service"),
+		accountRepo: opts.AccountRepo,
+		messageRepo: opts.MessageRepo,
+	}
+}
+
+// Search finds messages visible to user, conversations user is not part of are reported as not found
+func (s *searchService) Search(user models.User, req SearchRequest) (*models.SearchPage, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" || utf8.RuneCountInString(req.Query) > maxSearchQuery || !utf8.ValidString(req.Query) {
+		return nil, ErrMalformedQuery
+	}
+
+	if req.Conversation != "" && !models.ConversationVisibleTo(req.Conversation, user) {
+		return nil, ErrConversationNotFound
+	}
+
+	query := models.SearchQuery{
+		Query:        req.Query,
+		Conversation: req.Conversation,
+		Since:        req.Since,
+		Until:        req.Until,
+		Limit:        req.Limit + 1,
+		Offset:       req.Offset,
+	}
+
+	page := &models.SearchPage{
+		Results: []models.SearchResult{},
+	}
+
+	if req.Sender != "" {
+		sender, err := s.accountRepo.FindByEmail(req.Sender)
+		if err != nil {
+			return nil, err
+		}
+
+		if sender == nil {
+			return page, nil
+		}
+
+		query.SenderId = sender.ID
+	}
+
+	results, err := s.messageRepo.Search(user, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > req.Limit {
+		results = results[:req.Limit]
+		page.NextOffset = req.Offset + req.Limit
+	}
+
+	page.Results = results
+	return page, nil
+}
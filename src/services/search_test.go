@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSearchService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accounts := mock_repositories.NewMockUser(ctrl)
+	messages := mock_repositories.NewMockMessage(ctrl)
+
+	searchService := services.NewSearch(services.SearchOptions{
+		Logger:      zap.NewNop().Sugar(),
+		AccountRepo: accounts,
+		MessageRepo: messages,
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	t.Run("Empty query", func(t *testing.T) {
+		page, err := searchService.Search(user, services.SearchRequest{Query: "  ", Limit: 10})
+		require.Nil(t, page)
+		require.Equal(t, services.ErrMalformedQuery, err)
+	})
+
+	t.Run("Foreign conversation", func(t *testing.T) {
+		page, err := searchService.Search(user, services.SearchRequest{Query: "hello", Conversation: "dm:2:3", Limit: 10})
+		require.Nil(t, page)
+		require.Equal(t, services.ErrConversationNotFound, err)
+	})
+
+	t.Run("Unknown sender", func(t *testing.T) {
+		accounts.EXPECT().FindByEmail("unknown@example.com").Return(nil, nil)
+
+		page, err := searchService.Search(user, services.SearchRequest{Query: "hello", Sender: "unknown@example.com", Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, page.Results)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		accounts.EXPECT().FindByEmail("friend@example.com").Return(&models.User{ID: 2}, nil)
+		messages.EXPECT().Search(user, models.SearchQuery{
+			Query:        "hello",
+			SenderId:     2,
+			Conversation: "dm:1:2",
+			Limit:        3,
+			Offset:       4,
+		}).Return([]models.SearchResult{{Snippet: "a"}, {Snippet: "b"}, {Snippet: "c"}}, nil)
+
+		page, err := searchService.Search(user, services.SearchRequest{
+			Query:        " hello ",
+			Sender:       "friend@example.com",
+			Conversation: "dm:1:2",
+			Limit:        2,
+			Offset:       4,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Results, 2)
+		require.Equal(t, 6, page.NextOffset)
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		messages.EXPECT().Search(user, models.SearchQuery{Query: "hello", Limit: 3}).
+			Return([]models.SearchResult{{Snippet: "a"}}, nil)
+
+		page, err := searchService.Search(user, services.SearchRequest{Query: "hello", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Results, 1)
+		require.Zero(t, page.NextOffset)
+	})
+}
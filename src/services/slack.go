@@ -0,0 +1,358 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/repositories"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	SlackImport interface {
+		// Import creates users and public messages from Slack export, only listed
+		// channels are imported when there are any, it is safe to run it again
+		Import(archive *zip.Reader, channels []string) (*SlackImportReport, error)
+	}
+
+	SlackImportOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		Config      *viper.Viper
+		AccountRepo repositories.User
+		MessageRepo repositories.Message
+	}
+
+	// SlackImportReport counts what import created, messages imported by
+	// previous runs are counted as duplicates
+	SlackImportReport struct {
+		Users      int
+		Channels   int
+		Messages   int
+		Duplicates int
+		Skipped    int
+	}
+
+	slackImportService struct {
+		logger      *zap.SugaredLogger
+		accountRepo repositories.User
+		messageRepo repositories.Message
+		batch       int
+	}
+
+	slackUser struct {
+		Id      string `json:"id"`
+		Name    string `json:"name"`
+		Profile struct {
+			Email       string `json:"email"`
+			DisplayName string `json:"display_name"`
+		} `json:"profile"`
+	}
+
+	slackChannel struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	slackMessage struct {
+		Type    string `json:"type"`
+		Subtype string `json:"subtype"`
+		User    string `json:"user"`
+		Text    string `json:"text"`
+		Ts      string `json:"ts"`
+	}
+)
+
+// unusablePassword never matches a hash so imported users can not sign in until reset
+const unusablePassword = "!"
+
+var (
+	ErrMalformedSlackExport = errors.New("slack export must contain users.json and channels.json")
+
+	// slackEntityRe matches mentions and links Slack keeps in angle brackets
+	slackEntityRe = regexp.MustCompile(`<([^<>]+)>`)
+
+	// slackUnescaper reverts the only entities Slack escapes in message text
+	slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+	// slackSubtypes are imported message subtypes, joins, topic changes and bot messages are not
+	slackSubtypes = map[string]bool{
+		"":                 true,
+		"me_message":       true,
+		"thread_broadcast": true,
+		"file_share":       true,
+	}
+)
+
+func NewSlackImport(opts SlackImportOptions) SlackImport {
+	opts.Config.SetDefault("import.batch", 500)
+
+	return &slackImportService{
+		logger:      opts.Logger.Named("slack_import_service"),
+		accountRepo: opts.AccountRepo,
+		messageRepo: opts.MessageRepo,
+		batch:       opts.Config.GetInt("import.batch"),
+	}
+}
+
+func (s *slackImportService) Import(archive *zip.Reader, channels []string) (*SlackImportReport, error) {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	if files["users.json"] == nil || files["channels.json"] == nil {
+		return nil, ErrMalformedSlackExport
+	}
+
+	var (
+		slackUsers    []slackUser
+		slackChannels []slackChannel
+	)
+
+	if err := readSlackFile(files["users.json"], &slackUsers); err != nil {
+		return nil, err
+	}
+
+	if err := readSlackFile(files["channels.json"], &slackChannels); err != nil {
+		return nil, err
+	}
+
+	report := &SlackImportReport{}
+
+	users := make(map[string]*models.User, len(slackUsers))
+	for _, slackUser := range slackUsers {
+		user, created, err := s.user(slackUser)
+		if err != nil {
+			return report, err
+		}
+
+		users[slackUser.Id] = user
+		if created {
+			report.Users++
+		}
+	}
+	s.logger.Infof("imported users: %d new of %d", report.Users, len(slackUsers))
+
+	selected := make(map[string]bool, len(channels))
+	for _, name := range channels {
+		selected[strings.TrimPrefix(name, "#")] = true
+	}
+
+	for _, channel := range slackChannels {
+		if len(selected) > 0 && !selected[channel.Name] {
+			continue
+		}
+
+		if err := s.channel(files, channel, users, report); err != nil {
+			return report, err
+		}
+		report.Channels++
+	}
+
+	s.logger.Infof("import finished: %d users, %d channels, %d messages, %d duplicates, %d skipped",
+		report.Users, report.Channels, report.Messages, report.Duplicates, report.Skipped)
+
+	return report, nil
+}
+
+// user finds user by email of Slack profile or creates one pending password reset, Slack
+// names become nicknames when they are free, bots without email get a placeholder one
+func (s *slackImportService) user(slackUser slackUser) (*models.User, bool, error) {
+	user := &models.User{
+		Email:         strings.ToLower(slackUser.Profile.Email),
+		Password:      unusablePassword,
+		PasswordReset: true,
+	}
+
+	if user.Email == "" {
+		user.Email = strings.ToLower(slackUser.Id) + "@slack.invalid"
+	}
+
+	nickname := slackUser.Profile.DisplayName
+	if !nicknameRegex.MatchString(nickname) {
+		nickname = slackUser.Name
+	}
+
+	if nicknameRegex.MatchString(nickname) {
+		owner, err := s.accountRepo.FindByNickname(nickname)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if owner == nil {
+			user.Nickname = nickname
+		}
+	}
+
+	created, err := s.accountRepo.Import(user)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, created, nil
+}
+
+// channel imports messages of Slack channel into public conversation day by day, oldest first
+func (s *slackImportService) channel(files map[string]*zip.File, channel slackChannel,
+	users map[string]*models.User, report *SlackImportReport) error {
+	var days []string
+	for name := range files {
+		if path.Dir(name) == channel.Name && path.Ext(name) == ".json" {
+			days = append(days, name)
+		}
+	}
+	sort.Strings(days)
+
+	imported := 0
+	for _, day := range days {
+		var slackMessages []slackMessage
+		if err := readSlackFile(files[day], &slackMessages); err != nil {
+			return err
+		}
+
+		messages := make([]models.Message, 0, len(slackMessages))
+		for _, slackMessage := range slackMessages {
+			message, ok := slackMessage.message(channel, users)
+			if !ok {
+				report.Skipped++
+				continue
+			}
+
+			messages = append(messages, message)
+		}
+
+		for start := 0; start < len(messages); start += s.batch {
+			end := start + s.batch
+			if end > len(messages) {
+				end = len(messages)
+			}
+
+			n, err := s.messageRepo.Import(messages[start:end])
+			if err != nil {
+				return err
+			}
+
+			imported += n
+			report.Messages += n
+			report.Duplicates += end - start - n
+		}
+	}
+
+	s.logger.Infof("imported #%s: %d new messages over %d days", channel.Name, imported, len(days))
+	return nil
+}
+
+// message converts Slack message into public one, client id built from channel and
+// Slack timestamp makes repeated imports skip it
+func (m slackMessage) message(channel slackChannel, users map[string]*models.User) (models.Message, bool) {
+	author := users[m.User]
+	if m.Type != "message" || !slackSubtypes[m.Subtype] || author == nil {
+		return models.Message{}, false
+	}
+
+	createdAt, err := slackTime(m.Ts)
+	if err != nil {
+		return models.Message{}, false
+	}
+
+	text := slackText(m.Text, users)
+	if text == "" {
+		return models.Message{}, false
+	}
+
+	message := models.Message{
+		ClientMsgId: "slack:" + channel.Id + ":" + m.Ts,
+		UserId:      author.ID,
+		Type:        models.MessageText,
+		Text:        text,
+		Format:      models.FormatPlain,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+
+	if m.Subtype == "me_message" {
+		message.Type = models.MessageAction
+	}
+
+	return message, true
+}
+
+// slackTime parses Slack timestamp, seconds with microseconds after the dot
+func slackTime(ts string) (time.Time, error) {
+	parts := strings.SplitN(ts, ".", 2)
+
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var usec int64
+	if len(parts) == 2 {
+		if usec, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)), nil
+}
+
+// slackText turns Slack markup into plain text, user mentions become
+// mentions by email and links are shown along with their labels
+func slackText(text string, users map[string]*models.User) string {
+	text = slackEntityRe.ReplaceAllStringFunc(text, func(entity string) string {
+		value, label := entity[1:len(entity)-1], ""
+		if i := strings.Index(value, "|"); i >= 0 {
+			value, label = value[:i], value[i+1:]
+		}
+
+		switch {
+		case strings.HasPrefix(value, "@"):
+			if user := users[value[1:]]; user != nil {
+				return "@" + user.Email
+			}
+		case strings.HasPrefix(value, "#"):
+			if label != "" {
+				return "#" + label
+			}
+		case value == "!here":
+			return "@here"
+		case value == "!channel", value == "!everyone":
+			return "@all"
+		case strings.HasPrefix(value, "!"):
+		case label != "" && label != value:
+			return label + " (" + value + ")"
+		default:
+			return value
+		}
+
+		if label != "" {
+			return label
+		}
+
+		return value
+	})
+
+	return strings.TrimSpace(slackUnescaper.Replace(text))
+}
+
+func readSlackFile(file *zip.File, v interface{}) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// slackExport builds zip archive with given files in memory
+func slackExport(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestSlackImportService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accounts := mock_repositories.NewMockUser(ctrl)
+	messages := mock_repositories.NewMockMessage(ctrl)
+
+	config := viper.New()
+	config.Set("import.batch", 2)
+
+	slackService := services.NewSlackImport(services.SlackImportOptions{
+		Logger:      zap.NewNop().Sugar(),
+		Config:      config,
+		AccountRepo: accounts,
+		MessageRepo: messages,
+	})
+
+	export := map[string]string{
+		"users.json": `[
+			{"id": "U1", "name": "alice", "profile": {"email": "alice@example.com", "display_name": "Alice Smith"}},
+			{"id": "U2", "name": "bob", "profile": {"email": "Bob@Example.com", "display_name": "bobby"}},
+			{"id": "B1", "name": "deploy bot", "profile": {}}
+		]`,
+		"channels.json": `[{"id": "C1", "name": "general"}, {"id": "C2", "name": "random"}]`,
+		"general/2019-10-02.json": `[
+			{"type": "message", "user": "U2", "text": "see <https://example.com|docs> &amp; <https://example.com>", "ts": "1570000100.000200"}
+		]`,
+		"general/2019-10-01.json": `[
+			{"type": "message", "user": "U1", "text": "hi <@U2>, <!here>", "ts": "1570000000.000100"},
+			{"type": "message", "subtype": "channel_join", "user": "U2", "text": "<@U2> has joined the channel", "ts": "1570000001.000000"},
+			{"type": "message", "subtype": "me_message", "user": "U2", "text": "waves", "ts": "1570000002.000000"},
+			{"type": "message", "user": "U9", "text": "unknown author", "ts": "1570000003.000000"}
+		]`,
+		"random/2019-10-01.json": `[
+			{"type": "message", "user": "B1", "text": "deployed", "ts": "1570000004.000000"}
+		]`,
+	}
+
+	t.Run("Malformed export", func(t *testing.T) {
+		report, err := slackService.Import(slackExport(t, map[string]string{"users.json": "[]"}), nil)
+		require.Nil(t, report)
+		require.Equal(t, services.ErrMalformedSlackExport, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		accounts.EXPECT().FindByNickname("alice").Return(nil, errors.New("db error"))
+
+		_, err := slackService.Import(slackExport(t, export), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// alice is already imported, bob's nickname belongs to someone else and their email is lowercased
+		accounts.EXPECT().FindByNickname("alice").Return(&models.User{ID: 1, Email: "alice@example.com"}, nil)
+		accounts.EXPECT().FindByNickname("bobby").Return(&models.User{ID: 7, Email: "robert@example.com"}, nil)
+		accounts.EXPECT().Import(gomock.Any()).DoAndReturn(func(user *models.User) (bool, error) {
+			require.Equal(t, "alice@example.com", user.Email)
+			user.ID = 1
+			return false, nil
+		})
+		accounts.EXPECT().Import(gomock.Any()).DoAndReturn(func(user *models.User) (bool, error) {
+			require.Equal(t, "bob@example.com", user.Email)
+			require.Empty(t, user.Nickname)
+			require.True(t, user.PasswordReset)
+			user.ID = 2
+			return true, nil
+		})
+		accounts.EXPECT().Import(gomock.Any()).DoAndReturn(func(user *models.User) (bool, error) {
+			require.Equal(t, "b1@slack.invalid", user.Email)
+			require.Empty(t, user.Nickname)
+			user.ID = 3
+			return true, nil
+		})
+
+		gomock.InOrder(
+			messages.EXPECT().Import(gomock.Any()).DoAndReturn(func(batch []models.Message) (int, error) {
+				require.Len(t, batch, 2)
+				require.Equal(t, "slack:C1:1570000000.000100", batch[0].ClientMsgId)
+				require.Equal(t, int64(1), batch[0].UserId)
+				require.Equal(t, "hi @bob@example.com, @here", batch[0].Text)
+				require.Equal(t, models.FormatPlain, batch[0].Format)
+				require.Equal(t, time.Unix(1570000000, 100000), batch[0].CreatedAt)
+				require.Equal(t, models.MessageAction, batch[1].Type)
+				require.Zero(t, batch[1].ReceiverId)
+				return 1, nil
+			}),
+			messages.EXPECT().Import(gomock.Any()).DoAndReturn(func(batch []models.Message) (int, error) {
+				require.Len(t, batch, 1)
+				require.Equal(t, "see docs (https://example.com) & https://example.com", batch[0].Text)
+				return 1, nil
+			}),
+		)
+
+		report, err := slackService.Import(slackExport(t, export), []string{"#general"})
+		require.NoError(t, err)
+		require.Equal(t, &services.SlackImportReport{
+			Users:      2,
+			Channels:   1,
+			Messages:   2,
+			Duplicates: 1,
+			Skipped:    2,
+		}, report)
+	})
+}
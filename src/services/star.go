@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	"github.com/playneta/go-sessions/src/repositories"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type (
+	Star interface {
+		Star(user models.User, messageID int64) (*models.Star, error)
+		Unstar(user models.User, messageID int64) error
+		Saved(user models.User, cursor int64, limit int) (*models.SavedPage, error)
+	}
+
+	StarOptions struct {
+		fx.In
+
+		Logger      *zap.SugaredLogger
+		MessageRepo repositories.Message
+		StarRepo    repositories.Star
+		Renderer    providers.Renderer
+	}
+
+	starService struct {
+		logger      *zap.SugaredLogger
+		messageRepo repositories.Message
+		starRepo    repositories.Star
+		renderer    providers.Renderer
+	}
+)
+
+var ErrStarNotFound = errors.New("message is not starred")
+
+func NewStar(opts StarOptions) Star {
+	return &starService{
+		logger:      opts.Logger.Named("star_service"),
+		messageRepo: opts.MessageRepo,
+		starRepo:    opts.StarRepo,
+		renderer:    opts.Renderer,
+	}
+}
+
+// Star saves message for user, starring it again is not an error
+func (s *starService) Star(user models.User, messageID int64) (*models.Star, error) {
+	message, err := s.messageRepo.Find(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == nil || !message.VisibleTo(user) {
+		return nil, ErrMessageNotFound
+	}
+
+	star := &models.Star{
+		UserId:       user.ID,
+		MessageId:    message.Id,
+		Message:      message,
+		Conversation: message.Conversation(),
+		CreatedAt:    time.Now(),
+	}
+
+	if _, err := s.starRepo.Create(star); err != nil {
+		return nil, err
+	}
+
+	message.Starred = true
+	render(s.renderer, message)
+	return star, nil
+}
+
+func (s *starService) Unstar(user models.User, messageID int64) error {
+	deleted, err := s.starRepo.Delete(user, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrStarNotFound
+	}
+
+	return nil
+}
+
+// Saved returns page of messages starred by user together with their conversations,
+// stars of deleted messages are removed by database
+func (s *starService) Saved(user models.User, cursor int64, limit int) (*models.SavedPage, error) {
+	stars, err := s.starRepo.ForUser(user, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.SavedPage{
+		Stars: []models.Star{},
+	}
+
+	if len(stars) > limit {
+		stars = stars[:limit]
+		page.NextCursor = stars[limit-1].Id
+	}
+
+	for i := range stars {
+		if stars[i].Message == nil {
+			continue
+		}
+
+		stars[i].Conversation = stars[i].Message.Conversation()
+		stars[i].Message.Starred = true
+		render(s.renderer, stars[i].Message)
+	}
+
+	page.Stars = append(page.Stars, stars...)
+	return page, nil
+}
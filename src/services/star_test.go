@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+	mock_repositories "github.com/playneta/go-sessions/src/repositories/mocks"
+	"github.com/playneta/go-sessions/src/services"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStarService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messages := mock_repositories.NewMockMessage(ctrl)
+	stars := mock_repositories.NewMockStar(ctrl)
+
+	starService := services.NewStar(services.StarOptions{
+		Logger:      zap.NewNop().Sugar(),
+		MessageRepo: messages,
+		StarRepo:    stars,
+		Renderer:    providers.NewMarkdownRenderer(),
+	})
+
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	t.Run("Star", func(t *testing.T) {
+		t.Run("Foreign message", func(t *testing.T) {
+			messages.EXPECT().Find(int64(12)).Return(&models.Message{Id: 12, UserId: 2, ReceiverId: 3}, nil)
+
+			star, err := starService.Star(user, 12)
+			require.Nil(t, star)
+			require.Equal(t, services.ErrMessageNotFound, err)
+		})
+
+		t.Run("Missing message", func(t *testing.T) {
+			messages.EXPECT().Find(int64(13)).Return(nil, nil)
+
+			star, err := starService.Star(user, 13)
+			require.Nil(t, star)
+			require.Equal(t, services.ErrMessageNotFound, err)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			messages.EXPECT().Find(int64(11)).Return(&models.Message{Id: 11, UserId: 2, ReceiverId: 1, Text: "*hi*"}, nil)
+			stars.EXPECT().Create(gomock.Any()).Return(true, nil)
+
+			star, err := starService.Star(user, 11)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), star.UserId)
+			require.Equal(t, "dm:1:2", star.Conversation)
+			require.True(t, star.Message.Starred)
+			require.Equal(t, "<p><em>hi</em></p>", star.Message.HTML)
+		})
+	})
+
+	t.Run("Unstar", func(t *testing.T) {
+		t.Run("Not starred", func(t *testing.T) {
+			stars.EXPECT().Delete(user, int64(10)).Return(false, nil)
+			require.Equal(t, services.ErrStarNotFound, starService.Unstar(user, 10))
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			stars.EXPECT().Delete(user, int64(10)).Return(true, nil)
+			require.NoError(t, starService.Unstar(user, 10))
+		})
+	})
+
+	t.Run("Saved", func(t *testing.T) {
+		t.Run("Failure", func(t *testing.T) {
+			stars.EXPECT().ForUser(user, int64(0), 3).Return(nil, errors.New("database error"))
+
+			page, err := starService.Saved(user, 0, 2)
+			require.Nil(t, page)
+			require.Error(t, err)
+		})
+
+		t.Run("Next page", func(t *testing.T) {
+			stars.EXPECT().ForUser(user, int64(0), 3).Return([]models.Star{
+				{Id: 9, MessageId: 30, Message: &models.Message{Id: 30, UserId: 2}},
+				{Id: 8, MessageId: 20, Message: &models.Message{Id: 20, UserId: 1, ReceiverId: 2}},
+				{Id: 7, MessageId: 10, Message: &models.Message{Id: 10, UserId: 2}},
+			}, nil)
+
+			page, err := starService.Saved(user, 0, 2)
+			require.NoError(t, err)
+			require.Len(t, page.Stars, 2)
+			require.Equal(t, int64(8), page.NextCursor)
+			require.Equal(t, models.PublicConversation, page.Stars[0].Conversation)
+			require.Equal(t, "dm:1:2", page.Stars[1].Conversation)
+			require.True(t, page.Stars[1].Message.Starred)
+		})
+
+		t.Run("Last page", func(t *testing.T) {
+			stars.EXPECT().ForUser(user, int64(8), 3).Return([]models.Star{
+				{Id: 7, MessageId: 10, Message: &models.Message{Id: 10, UserId: 2}},
+			}, nil)
+
+			page, err := starService.Saved(user, 8, 2)
+			require.NoError(t, err)
+			require.Len(t, page.Stars, 1)
+			require.Zero(t, page.NextCursor)
+		})
+	})
+}
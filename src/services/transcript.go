@@ -0,0 +1,217 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/providers"
+)
+
+type (
+	// transcript writes messages of export in one of formats as they are read
+	transcript interface {
+		begin() error
+		write(message models.Message) error
+		end() error
+	}
+
+	// exportedMessage is a line of ndjson transcript
+	exportedMessage struct {
+		Id           int64     `json:"id"`
+		Conversation string    `json:"conversation"`
+		From         string    `json:"from"`
+		To           string    `json:"to,omitempty"`
+		Type         string    `json:"type"`
+		Text         string    `json:"text"`
+		Format       string    `json:"format"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	ndjsonTranscript struct {
+		encoder *json.Encoder
+	}
+
+	csvTranscript struct {
+		writer *csv.Writer
+	}
+
+	textTranscript struct {
+		w io.Writer
+	}
+
+	htmlTranscript struct {
+		w        io.Writer
+		renderer providers.Renderer
+	}
+)
+
+// transcriptTime is how message time is shown in text and html transcripts
+const transcriptTime = "2006-01-02 15:04:05 MST"
+
+var csvHeader = []string{"id", "conversation", "from", "to", "type", "text", "format", "created_at"}
+
+var htmlTranscriptTemplates = template.Must(template.New("transcript").Parse(`{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Transcript</title>
+<style>
+body { font-family: sans-serif; max-width: 48em; margin: 2em auto; color: #222; }
+.message { margin: 0 0 1em; }
+.meta { color: #777; font-size: 0.85em; }
+.action { font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Transcript</h1>
+{{end}}{{define "message"}}<div class="message{{if .Action}} action{{end}}">
+<div class="meta"><time datetime="{{.DateTime}}">{{.Time}}</time> {{.Conversation}}</div>
+<strong>{{.From}}</strong> {{.HTML}}
+</div>
+{{end}}{{define "footer"}}</body>
+</html>
+{{end}}`))
+
+func newTranscript(format string, w io.Writer, renderer providers.Renderer) transcript {
+	switch format {
+	case ExportCSV:
+		return &csvTranscript{writer: csv.NewWriter(w)}
+	case ExportText:
+		return &textTranscript{w: w}
+	case ExportHTML:
+		return &htmlTranscript{w: w, renderer: renderer}
+	default:
+		return &ndjsonTranscript{encoder: json.NewEncoder(w)}
+	}
+}
+
+func (t *ndjsonTranscript) begin() error {
+	return nil
+}
+
+func (t *ndjsonTranscript) write(message models.Message) error {
+	exported := exportedMessage{
+		Id:           message.Id,
+		Conversation: message.Conversation(),
+		From:         senderEmail(message),
+		Type:         message.Type,
+		Text:         message.Text,
+		Format:       message.Format,
+		CreatedAt:    message.CreatedAt.UTC(),
+	}
+
+	if message.Receiver != nil {
+		exported.To = message.Receiver.Email
+	}
+
+	return t.encoder.Encode(exported)
+}
+
+func (t *ndjsonTranscript) end() error {
+	return nil
+}
+
+func (t *csvTranscript) begin() error {
+	return t.writer.Write(csvHeader)
+}
+
+func (t *csvTranscript) write(message models.Message) error {
+	to := ""
+	if message.Receiver != nil {
+		to = message.Receiver.Email
+	}
+
+	return t.writer.Write([]string{
+		strconv.FormatInt(message.Id, 10),
+		message.Conversation(),
+		csvCell(senderEmail(message)),
+		csvCell(to),
+		message.Type,
+		csvCell(message.Text),
+		message.Format,
+		message.CreatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (t *csvTranscript) end() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+func (t *textTranscript) begin() error {
+	return nil
+}
+
+// write puts message on its own line, continuation lines of multiline text are indented
+func (t *textTranscript) write(message models.Message) error {
+	text := strings.Replace(message.Text, "\n", "\n    ", -1)
+
+	line := fmt.Sprintf("[%s] %s %s: %s\n", message.CreatedAt.UTC().Format(transcriptTime),
+		message.Conversation(), senderName(message), text)
+	if message.Type == models.MessageAction {
+		line = fmt.Sprintf("[%s] %s * %s %s\n", message.CreatedAt.UTC().Format(transcriptTime),
+			message.Conversation(), senderName(message), text)
+	}
+
+	_, err := io.WriteString(t.w, line)
+	return err
+}
+
+func (t *textTranscript) end() error {
+	return nil
+}
+
+func (t *htmlTranscript) begin() error {
+	return htmlTranscriptTemplates.ExecuteTemplate(t.w, "header", nil)
+}
+
+// write renders message the same way clients show it, rendered text is already safe
+func (t *htmlTranscript) write(message models.Message) error {
+	return htmlTranscriptTemplates.ExecuteTemplate(t.w, "message", map[string]interface{}{
+		"Action":       message.Type == models.MessageAction,
+		"DateTime":     message.CreatedAt.UTC().Format(time.RFC3339),
+		"Time":         message.CreatedAt.UTC().Format(transcriptTime),
+		"Conversation": message.Conversation(),
+		"From":         senderName(message),
+		"HTML":         template.HTML(renderText(t.renderer, message.Text, message.Format)),
+	})
+}
+
+func (t *htmlTranscript) end() error {
+	return htmlTranscriptTemplates.ExecuteTemplate(t.w, "footer", nil)
+}
+
+// senderEmail returns email of who sent message, authors of archived messages
+// may be gone from users table so they are shown by their id
+func senderEmail(message models.Message) string {
+	if message.User == nil {
+		return fmt.Sprintf("user %d", message.UserId)
+	}
+
+	return message.User.Email
+}
+
+// senderName returns name of who sent message, see senderEmail
+func senderName(message models.Message) string {
+	if message.User == nil {
+		return fmt.Sprintf("user %d", message.UserId)
+	}
+
+	return message.User.Name()
+}
+
+// csvCell keeps spreadsheets from evaluating user text as formula
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
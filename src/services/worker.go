@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// startWorker runs job every interval while application is running, job
+// reporting there is more work to do is run again without waiting
+func startWorker(lc fx.Lifecycle, interval time.Duration, job func() bool) {
+	if lc == nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					if job() {
+						select {
+						case <-stop:
+							return
+						default:
+							continue
+						}
+					}
+
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
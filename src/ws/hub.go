@@ -0,0 +1,390 @@
+package ws
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/playneta/go-sessions/src/commands"
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/playneta/go-sessions/src/services"
+	"go.uber.org/zap"
+)
+
+type (
+	// Hub keeps track of connected users and delivers events to them,
+	// user may be connected from several devices at once
+	Hub struct {
+		logger *zap.SugaredLogger
+
+		users map[string][]*User
+		mu    sync.RWMutex
+
+		// delivered is called once private message reached a device of receiver
+		delivered func(receiver models.User, message models.Message)
+	}
+
+	// User is a single connection, profile of user may be replaced by another
+	// device at any moment so it is only accessed through Model
+	User struct {
+		Conn *websocket.Conn
+
+		model *models.User
+		mu    sync.RWMutex
+		wmu   sync.Mutex
+
+		// typing frames counted in current window, only read loop of connection touches them
+		typingWindow time.Time
+		typingFrames int
+	}
+)
+
+// NewHub creates empty hub of connected users
+func NewHub(logger *zap.SugaredLogger) *Hub {
+	return &Hub{
+		logger:    logger.Named("hub"),
+		users:     make(map[string][]*User),
+		delivered: func(models.User, models.Message) {},
+	}
+}
+
+// NewNotifier exposes hub as services.Notifier
+func NewNotifier(hub *Hub) services.Notifier {
+	return hub
+}
+
+// NewPresence exposes hub as commands.Presence
+func NewPresence(hub *Hub) commands.Presence {
+	return hub
+}
+
+// NewUser creates connection of user
+func NewUser(model models.User, conn *websocket.Conn) *User {
+	return &User{model: &model, Conn: conn}
+}
+
+// Model returns current profile of user
+func (u *User) Model() models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return *u.model
+}
+
+func (u *User) setModel(model models.User) {
+	u.mu.Lock()
+	u.model = &model
+	u.mu.Unlock()
+}
+
+// Send writes event to user connection, gorilla connections
+// do not support concurrent writers so they are serialized here
+func (u *User) Send(event Event) error {
+	u.wmu.Lock()
+	defer u.wmu.Unlock()
+
+	return u.Conn.WriteJSON(event)
+}
+
+// OnDelivered sets callback for private messages pushed to a connected receiver,
+// it must be set before users connect
+func (h *Hub) OnDelivered(fn func(receiver models.User, message models.Message)) {
+	h.delivered = fn
+}
+
+// Join registers user connection in hub
+func (h *Hub) Join(user *User) {
+	h.mu.Lock()
+	h.users[user.Model().Email] = append(h.users[user.Model().Email], user)
+	h.mu.Unlock()
+}
+
+// Leave removes user connection from hub, other devices of user stay connected
+func (h *Hub) Leave(user *User) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	devices := h.users[user.Model().Email]
+	for i, device := range devices {
+		if device != user {
+			continue
+		}
+
+		devices = append(devices[:i:i], devices[i+1:]...)
+		break
+	}
+
+	if len(devices) == 0 {
+		delete(h.users, user.Model().Email)
+		return
+	}
+
+	h.users[user.Model().Email] = devices
+}
+
+// Get returns connections of user by email
+func (h *Hub) Get(email string) ([]*User, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	devices, ok := h.users[email]
+	return devices, ok
+}
+
+// Online returns connected users ordered by email
+func (h *Hub) Online() []models.User {
+	h.mu.RLock()
+	users := make([]models.User, 0, len(h.users))
+	for _, devices := range h.users {
+		users = append(users, devices[0].Model())
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Email < users[j].Email
+	})
+
+	return users
+}
+
+// Update replaces profile of user on every device, concurrent updates
+// are serialized so devices never end up with different profiles
+func (h *Hub) Update(user *User, model models.User) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, device := range h.users[user.Model().Email] {
+		device.setModel(model)
+	}
+	user.setModel(model)
+}
+
+// Broadcast sends event to every connected user
+func (h *Hub) Broadcast(event Event) {
+	h.broadcast(event, "")
+}
+
+func (h *Hub) broadcast(event Event, skip string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for email, devices := range h.users {
+		if email == skip {
+			continue
+		}
+
+		h.send(devices, event)
+	}
+}
+
+// SendTo sends event to every device of connected user by email
+func (h *Hub) SendTo(email string, event Event) bool {
+	devices, ok := h.Get(email)
+	if !ok {
+		return false
+	}
+
+	h.send(devices, event)
+	return true
+}
+
+// SendToID sends event to every device of connected user by id
+func (h *Hub) SendToID(id int64, event Event) bool {
+	h.mu.RLock()
+	var found []*User
+	for _, devices := range h.users {
+		if devices[0].Model().ID == id {
+			found = devices
+			break
+		}
+	}
+	h.mu.RUnlock()
+
+	if found == nil {
+		return false
+	}
+
+	h.send(found, event)
+	return true
+}
+
+func (h *Hub) send(devices []*User, event Event) {
+	for _, device := range devices {
+		if err := device.Send(event); err != nil {
+			h.logger.Errorf("error sending %s event: %v", event.Type, err)
+		}
+	}
+}
+
+// Publish sends event to everyone who can see the message
+func (h *Hub) Publish(message models.Message, event Event) {
+	if message.Receiver == nil {
+		h.Broadcast(event)
+		return
+	}
+
+	h.SendTo(message.User.Email, event)
+	if message.Receiver.Email != message.User.Email {
+		h.SendTo(message.Receiver.Email, event)
+	}
+}
+
+// Message delivers new message, private messages for offline receivers
+// stay queued until they connect
+func (h *Hub) Message(message models.Message) {
+	event := NewMessageEvent(message)
+	if message.Receiver == nil {
+		h.Broadcast(event)
+	} else {
+		h.SendTo(message.User.Email, event)
+		if message.Receiver.Email != message.User.Email && h.SendTo(message.Receiver.Email, event) {
+			h.delivered(*message.Receiver, message)
+		}
+	}
+
+	h.mention(message)
+}
+
+// MessageUpdated sends message again to everyone who can see it,
+// clients replace previously received message with the same id
+func (h *Hub) MessageUpdated(message models.Message) {
+	event := NewMessageEvent(message)
+	event.Type = "message_updated"
+
+	h.Publish(message, event)
+}
+
+func (h *Hub) MessageExpired(message models.Message) {
+	h.Publish(message, Event{
+		Type: "message_expired",
+		Data: MessageExpiredEvent{
+			MessageId:    message.Id,
+			Conversation: message.Conversation(),
+		},
+	})
+}
+
+// mention sends dedicated mention event to every mentioned user,
+// they receive it even if they already got the message itself
+func (h *Hub) mention(message models.Message) {
+	event := NewMessageEvent(message)
+	event.Type = "mention"
+
+	everyone := false
+	for _, mention := range message.Mentions {
+		switch mention.Kind {
+		case models.MentionHere, models.MentionAll:
+			if !everyone {
+				h.broadcast(event, message.User.Email)
+				everyone = true
+			}
+		case models.MentionUser:
+			if mention.User != nil {
+				h.SendTo(mention.User.Email, event)
+			}
+		}
+	}
+}
+
+func (h *Hub) ReactionAdded(message models.Message, reaction models.Reaction) {
+	h.Publish(message, NewReactionEvent("reaction_added", message, reaction))
+}
+
+func (h *Hub) ReactionRemoved(message models.Message, reaction models.Reaction) {
+	h.Publish(message, NewReactionEvent("reaction_removed", message, reaction))
+}
+
+func (h *Hub) Delivered(message models.Message) {
+	if message.User == nil || message.DeliveredAt == nil {
+		return
+	}
+
+	h.SendTo(message.User.Email, Event{
+		Type: "delivered",
+		Data: DeliveredEvent{
+			MessageId:    message.Id,
+			Conversation: message.Conversation(),
+			DeliveredAt:  *message.DeliveredAt,
+		},
+	})
+}
+
+func (h *Hub) Read(message models.Message, reader models.User) {
+	partner := message.User
+	if message.UserId == reader.ID {
+		partner = message.Receiver
+	}
+
+	if partner == nil || partner.ID == reader.ID {
+		return
+	}
+
+	h.SendTo(partner.Email, Event{
+		Type: "read",
+		Data: ReadEvent{
+			MessageId:    message.Id,
+			Conversation: message.Conversation(),
+			User:         reader.Email,
+		},
+	})
+}
+
+// PollUpdated broadcasts tallies of poll, me flags belong to the voter
+// who caused the update so they are not sent to everyone
+func (h *Hub) PollUpdated(message models.Message) {
+	if message.Poll == nil {
+		return
+	}
+
+	poll := *message.Poll
+	poll.Options = make([]models.PollOption, len(message.Poll.Options))
+	for i, option := range message.Poll.Options {
+		option.Me = false
+		poll.Options[i] = option
+	}
+
+	h.Publish(message, Event{
+		Type: "poll_updated",
+		Data: PollUpdatedEvent{
+			MessageId:    message.Id,
+			Conversation: message.Conversation(),
+			Poll:         &poll,
+		},
+	})
+}
+
+func (h *Hub) Pinned(message models.Message, pin models.Pin) {
+	data := PinEvent{
+		MessageId:    message.Id,
+		Conversation: pin.Conversation,
+	}
+
+	if pin.User != nil {
+		data.User = pin.User.Email
+	}
+
+	h.Publish(message, Event{Type: "pinned", Data: data})
+}
+
+func (h *Hub) Unpinned(message models.Message, user models.User) {
+	h.Publish(message, Event{
+		Type: "unpinned",
+		Data: PinEvent{
+			MessageId:    message.Id,
+			Conversation: message.Conversation(),
+			User:         user.Email,
+		},
+	})
+}
+
+func (h *Hub) DraftUpdated(user models.User, draft models.Draft) {
+	h.SendToID(user.ID, Event{
+		Type: "draft_updated",
+		Data: DraftUpdatedEvent{
+			Conversation: draft.Conversation,
+			Text:         draft.Text,
+			UpdatedAt:    draft.UpdatedAt,
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package ws
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHubUpdate(t *testing.T) {
+	hub := NewHub(zap.NewNop().Sugar())
+
+	model := models.User{ID: 1, Email: "user@example.com"}
+	phone, laptop := NewUser(model, nil), NewUser(model, nil)
+	hub.Join(phone)
+	hub.Join(laptop)
+
+	// both devices rename user while reading profile of each other,
+	// run with -race to catch unsynchronized access
+	var wg sync.WaitGroup
+	for i, device := range []*User{phone, laptop} {
+		wg.Add(1)
+		go func(i int, device *User) {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				renamed := device.Model()
+				renamed.Nickname = fmt.Sprintf("device%d-%d", i, n)
+				hub.Update(device, renamed)
+
+				assert.Equal(t, model.Email, phone.Model().Email)
+				assert.Equal(t, model.Email, laptop.Model().Email)
+				assert.Len(t, hub.Online(), 1)
+			}
+		}(i, device)
+	}
+	wg.Wait()
+
+	require.Equal(t, phone.Model(), laptop.Model())
+	require.Contains(t, []string{"device0-99", "device1-99"}, phone.Model().Nickname)
+}
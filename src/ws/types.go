@@ -1,24 +1,140 @@
 package ws
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/playneta/go-sessions/src/models"
 )
 
 type MessageEvent struct {
-	From     string    `json:"from"`
-	To       string    `json:"to"`
-	Text     string    `json:"text"`
-	DateTime time.Time `json:"date_time"`
+	Id           int64                    `json:"id"`
+	ClientMsgId  string                   `json:"client_msg_id,omitempty"`
+	Conversation string                   `json:"conversation"`
+	From         string                   `json:"from"`
+	To           string                   `json:"to"`
+	Type         string                   `json:"type"`
+	Text         string                   `json:"text"`
+	Format       string                   `json:"format"`
+	HTML         string                   `json:"html"`
+	Attachments  []models.Attachment      `json:"attachments"`
+	Mentions     []string                 `json:"mentions"`
+	Reactions    []models.ReactionSummary `json:"reactions"`
+	Previews     []models.Preview         `json:"previews"`
+	Poll         *models.Poll             `json:"poll,omitempty"`
+	RefId        int64                    `json:"ref_id,omitempty"`
+	Ref          *models.MessageRef       `json:"ref,omitempty"`
+	Status       string                   `json:"status,omitempty"`
+	DeliveredAt  *time.Time               `json:"delivered_at,omitempty"`
+	ExpiresAt    *time.Time               `json:"expires_at,omitempty"`
+	Starred      bool                     `json:"starred"`
+	DateTime     time.Time                `json:"date_time"`
+
+	// AttachmentIds references uploaded attachments in messages sent by client
+	AttachmentIds []int64 `json:"attachment_ids,omitempty"`
+	// TTL in seconds makes message sent by client self-destructing
+	TTL int64 `json:"ttl,omitempty"`
+	// RefKind tells whether message sent by client forwards or quotes RefId
+	RefKind string `json:"ref_kind,omitempty"`
 }
 
 type MessageJoin struct {
 	User string `json:"user"`
 }
 
+// MessageError reports failure of event sent by client, client message id
+// is set when failed event was a message carrying it
 type MessageError struct {
-	Error string `json:"error"`
+	ClientMsgId string `json:"client_msg_id,omitempty"`
+	Error       string `json:"error"`
+}
+
+// AckEvent confirms message sent by client was stored, Id is omitted for
+// slash commands which did not send any message
+type AckEvent struct {
+	ClientMsgId string    `json:"client_msg_id"`
+	Id          int64     `json:"id,omitempty"`
+	DateTime    time.Time `json:"date_time"`
+}
+
+// SystemEvent is a private notice to a single user, like a reply to slash command,
+// To is the receiver of conversation it belongs to, empty for public one
+type SystemEvent struct {
+	To       string    `json:"to"`
+	Text     string    `json:"text"`
+	DateTime time.Time `json:"date_time"`
+}
+
+// HistoryTruncatedEvent tells reconnected client that not everything it missed
+// was replayed, messages after LastId and before Before have to be paged over REST
+type HistoryTruncatedEvent struct {
+	LastId int64 `json:"last_id"`
+	Before int64 `json:"before"`
+}
+
+type ReactionEvent struct {
+	MessageId int64  `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	User      string `json:"user"`
+	Count     int    `json:"count"`
+}
+
+type ReadEvent struct {
+	MessageId    int64  `json:"message_id"`
+	Conversation string `json:"conversation"`
+	User         string `json:"user"`
+}
+
+// DeliveredEvent tells author of private message it reached a device of receiver
+type DeliveredEvent struct {
+	MessageId    int64     `json:"message_id"`
+	Conversation string    `json:"conversation"`
+	DeliveredAt  time.Time `json:"delivered_at"`
+}
+
+type TypingEvent struct {
+	Conversation string `json:"conversation"`
+	User         string `json:"user"`
+}
+
+type MessageExpiredEvent struct {
+	MessageId    int64  `json:"message_id"`
+	Conversation string `json:"conversation"`
+}
+
+// PollEvent is sent by client to post a poll
+type PollEvent struct {
+	To        string     `json:"to"`
+	Text      string     `json:"text"`
+	Format    string     `json:"format"`
+	Options   []string   `json:"options"`
+	Multiple  bool       `json:"multiple"`
+	Anonymous bool       `json:"anonymous"`
+	ClosesAt  *time.Time `json:"closes_at"`
+}
+
+type VoteEvent struct {
+	MessageId int64   `json:"message_id"`
+	Options   []int64 `json:"options"`
+}
+
+type PollUpdatedEvent struct {
+	MessageId    int64        `json:"message_id"`
+	Conversation string       `json:"conversation"`
+	Poll         *models.Poll `json:"poll"`
+}
+
+type PinEvent struct {
+	MessageId    int64  `json:"message_id"`
+	Conversation string `json:"conversation"`
+	User         string `json:"user"`
+}
+
+// DraftUpdatedEvent carries draft saved on another device of user, empty text means it was cleared
+type DraftUpdatedEvent struct {
+	Conversation string    `json:"conversation"`
+	Text         string    `json:"text"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type Event struct {
@@ -26,19 +142,72 @@ type Event struct {
 	Data interface{} `json:"data"`
 }
 
+// IncomingEvent is a frame received from client, frames without type
+// are plain MessageEvent sent by older clients
+type IncomingEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
 func NewMessageEvent(message models.Message) Event {
 	data := MessageEvent{
-		From:     message.User.Email,
-		Text:     message.Text,
-		DateTime: message.CreatedAt,
+		Id:           message.Id,
+		ClientMsgId:  message.ClientMsgId,
+		Conversation: message.Conversation(),
+		From:         message.User.Email,
+		Type:         message.Type,
+		Text:         message.Text,
+		Format:       message.Format,
+		HTML:         message.HTML,
+		Attachments:  message.Attachments,
+		Reactions:    message.Reactions,
+		Previews:     message.Previews,
+		ExpiresAt:    message.ExpiresAt,
+		Poll:         message.Poll,
+		RefId:        message.RefId,
+		Ref:          message.Ref,
+		Status:       message.Status,
+		DeliveredAt:  message.DeliveredAt,
+		Starred:      message.Starred,
+		DateTime:     message.CreatedAt,
 	}
 
 	if message.Receiver != nil {
 		data.To = message.Receiver.Email
 	}
 
+	for _, mention := range message.Mentions {
+		if mention.Kind != models.MentionUser {
+			data.Mentions = append(data.Mentions, mention.Kind)
+		} else if mention.User != nil {
+			data.Mentions = append(data.Mentions, mention.User.Email)
+		}
+	}
+
 	return Event{
 		Type: "message",
 		Data: data,
 	}
 }
+
+func NewReactionEvent(eventType string, message models.Message, reaction models.Reaction) Event {
+	data := ReactionEvent{
+		MessageId: message.Id,
+		Emoji:     reaction.Emoji,
+	}
+
+	if reaction.User != nil {
+		data.User = reaction.User.Email
+	}
+
+	for _, summary := range message.Reactions {
+		if summary.Emoji == reaction.Emoji {
+			data.Count = summary.Count
+		}
+	}
+
+	return Event{
+		Type: eventType,
+		Data: data,
+	}
+}
@@ -0,0 +1,31 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageEvent(t *testing.T) {
+	message := models.Message{
+		Id:      1,
+		User:    &models.User{ID: 1, Email: "user@example.com"},
+		Text:    "text",
+		Starred: true,
+	}
+
+	event := NewMessageEvent(message)
+	require.True(t, event.Data.(MessageEvent).Starred)
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"starred":true`)
+
+	// false is sent as well so clients can clear the flag
+	message.Starred = false
+	data, err = json.Marshal(NewMessageEvent(message))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"starred":false`)
+}
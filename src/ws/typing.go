@@ -0,0 +1,162 @@
+package ws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+)
+
+// typingFrames is how many typing frames connection may send per throttle,
+// enough for start and stop, the rest is dropped before it reaches anyone
+const typingFrames = 2
+
+type (
+	// typing keeps who is typing where, state is never persisted and
+	// expires if client does not send typing_stop in time
+	typing struct {
+		hub      typingHub
+		clock    clock
+		ttl      time.Duration
+		throttle time.Duration
+
+		states map[typingKey]*typingState
+		mu     sync.Mutex
+	}
+
+	typingKey struct {
+		user         string
+		conversation string
+	}
+
+	typingState struct {
+		timer     timer
+		prolonged time.Time
+	}
+
+	// typingHub is part of hub typing events are relayed through
+	typingHub interface {
+		broadcast(event Event, skip string)
+		SendToID(id int64, event Event) bool
+	}
+
+	// clock schedules expiry of typing states, tests replace it to move time by hand
+	clock interface {
+		Now() time.Time
+		AfterFunc(d time.Duration, f func()) timer
+	}
+
+	timer interface {
+		Reset(d time.Duration) bool
+		Stop() bool
+	}
+
+	realClock struct{}
+)
+
+func newTyping(hub typingHub, clock clock, ttl, throttle time.Duration) *typing {
+	return &typing{
+		hub:      hub,
+		clock:    clock,
+		ttl:      ttl,
+		throttle: throttle,
+		states:   make(map[typingKey]*typingState),
+	}
+}
+
+// allow reports whether typing frame of connection may be handled, frames are
+// counted per throttle window so client toggling typing can not flood others
+func (t *typing) allow(conn *User) bool {
+	now := t.clock.Now()
+	if now.Sub(conn.typingWindow) >= t.throttle {
+		conn.typingWindow, conn.typingFrames = now, 0
+	}
+
+	conn.typingFrames++
+	return conn.typingFrames <= typingFrames
+}
+
+// start relays typing_start unless user is already typing and prolongs the state,
+// clients send it on every key press so state is prolonged at most once per throttle
+func (t *typing) start(user models.User, conversation string) {
+	key := typingKey{user: user.Email, conversation: conversation}
+	now := t.clock.Now()
+
+	t.mu.Lock()
+	state, ok := t.states[key]
+	if !ok {
+		t.states[key] = &typingState{
+			timer: t.clock.AfterFunc(t.ttl, func() {
+				t.stop(user, conversation)
+			}),
+			prolonged: now,
+		}
+	} else if now.Sub(state.prolonged) >= t.throttle {
+		state.timer.Reset(t.ttl)
+		state.prolonged = now
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		t.relay("typing_start", user, conversation)
+	}
+}
+
+// stop relays typing_stop if user was typing
+func (t *typing) stop(user models.User, conversation string) {
+	key := typingKey{user: user.Email, conversation: conversation}
+
+	t.mu.Lock()
+	state, ok := t.states[key]
+	if ok {
+		state.timer.Stop()
+		delete(t.states, key)
+	}
+	t.mu.Unlock()
+
+	if ok {
+		t.relay("typing_stop", user, conversation)
+	}
+}
+
+// leave stops every typing state of disconnected user
+func (t *typing) leave(user models.User) {
+	var conversations []string
+
+	t.mu.Lock()
+	for key := range t.states {
+		if key.user == user.Email {
+			conversations = append(conversations, key.conversation)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, conversation := range conversations {
+		t.stop(user, conversation)
+	}
+}
+
+func (t *typing) relay(eventType string, user models.User, conversation string) {
+	event := Event{
+		Type: eventType,
+		Data: TypingEvent{
+			Conversation: conversation,
+			User:         user.Email,
+		},
+	}
+
+	if conversation == models.PublicConversation {
+		t.hub.broadcast(event, user.Email)
+		return
+	}
+
+	t.hub.SendToID(models.ConversationPartner(conversation, user), event)
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
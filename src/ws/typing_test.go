@@ -0,0 +1,215 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/playneta/go-sessions/src/models"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeClock fires timers only when test moves time forward
+type fakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *fakeClock
+	at     time.Time
+	f      func()
+	active bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) timer {
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f, active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.at.After(c.now) {
+			t.active = false
+			t.f()
+		}
+	}
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	active := t.active
+	t.at, t.active = t.clock.now.Add(d), true
+	return active
+}
+
+func (t *fakeTimer) Stop() bool {
+	active := t.active
+	t.active = false
+	return active
+}
+
+// fakeHub records relayed typing events
+type fakeHub struct {
+	events []string
+}
+
+func (h *fakeHub) broadcast(event Event, skip string) {
+	h.record(event, "everyone except "+skip)
+}
+
+func (h *fakeHub) SendToID(id int64, event Event) bool {
+	h.record(event, fmt.Sprintf("user %d", id))
+	return true
+}
+
+func (h *fakeHub) record(event Event, to string) {
+	data := event.Data.(TypingEvent)
+	h.events = append(h.events, fmt.Sprintf("%s %s by %s to %s", event.Type, data.Conversation, data.User, to))
+}
+
+func TestTyping(t *testing.T) {
+	alice := models.User{ID: 1, Email: "alice@example.com"}
+
+	setup := func() (*typing, *fakeHub, *fakeClock) {
+		hub := &fakeHub{}
+		clock := &fakeClock{now: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)}
+		return newTyping(hub, clock, 5*time.Second, time.Second), hub, clock
+	}
+
+	t.Run("Start", func(t *testing.T) {
+		typing, hub, _ := setup()
+
+		typing.start(alice, models.PublicConversation)
+		typing.start(alice, models.PublicConversation)
+		typing.start(alice, "dm:1:2")
+
+		require.Equal(t, []string{
+			"typing_start public by alice@example.com to everyone except alice@example.com",
+			"typing_start dm:1:2 by alice@example.com to user 2",
+		}, hub.events)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		typing, hub, clock := setup()
+
+		typing.start(alice, "dm:1:2")
+		typing.stop(alice, "dm:1:2")
+		typing.stop(alice, "dm:1:2")
+
+		require.Equal(t, []string{
+			"typing_start dm:1:2 by alice@example.com to user 2",
+			"typing_stop dm:1:2 by alice@example.com to user 2",
+		}, hub.events)
+
+		// stopped state does not expire later
+		clock.advance(time.Minute)
+		require.Len(t, hub.events, 2)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		typing, hub, clock := setup()
+
+		typing.start(alice, models.PublicConversation)
+		clock.advance(5*time.Second - time.Millisecond)
+		require.Len(t, hub.events, 1)
+
+		clock.advance(time.Millisecond)
+		require.Equal(t, "typing_stop public by alice@example.com to everyone except alice@example.com", hub.events[1])
+
+		// typing again starts new state
+		typing.start(alice, models.PublicConversation)
+		require.Len(t, hub.events, 3)
+	})
+
+	t.Run("Throttle", func(t *testing.T) {
+		typing, hub, clock := setup()
+
+		// start within throttle does not prolong the state
+		typing.start(alice, models.PublicConversation)
+		clock.advance(500 * time.Millisecond)
+		typing.start(alice, models.PublicConversation)
+		clock.advance(4500 * time.Millisecond)
+		require.Len(t, hub.events, 2)
+
+		// start after throttle prolongs it
+		typing.start(alice, models.PublicConversation)
+		clock.advance(2 * time.Second)
+		typing.start(alice, models.PublicConversation)
+		clock.advance(4 * time.Second)
+		require.Len(t, hub.events, 3)
+
+		clock.advance(time.Second)
+		require.Len(t, hub.events, 4)
+	})
+
+	t.Run("Leave", func(t *testing.T) {
+		typing, hub, clock := setup()
+		bob := models.User{ID: 2, Email: "bob@example.com"}
+
+		typing.start(alice, models.PublicConversation)
+		typing.start(alice, "dm:1:2")
+		typing.start(bob, models.PublicConversation)
+		hub.events = nil
+
+		typing.leave(alice)
+		require.ElementsMatch(t, []string{
+			"typing_stop public by alice@example.com to everyone except alice@example.com",
+			"typing_stop dm:1:2 by alice@example.com to user 2",
+		}, hub.events)
+
+		// only bob is still typing
+		clock.advance(5 * time.Second)
+		require.Equal(t, "typing_stop public by bob@example.com to everyone except bob@example.com", hub.events[2])
+		require.Len(t, hub.events, 3)
+	})
+	t.Run("Toggling", func(t *testing.T) {
+		typing, hub, clock := setup()
+		socket := &Websocket{hub: NewHub(zap.NewNop().Sugar()), typing: typing}
+		conn := NewUser(alice, nil)
+
+		start := json.RawMessage(`{"conversation":"public"}`)
+		for i := 0; i < 10; i++ {
+			require.NoError(t, socket.handleTypingStart(conn, start))
+			require.NoError(t, socket.handleTypingStop(conn, start))
+		}
+
+		// only first start and stop within throttle are relayed
+		require.Equal(t, []string{
+			"typing_start public by alice@example.com to everyone except alice@example.com",
+			"typing_stop public by alice@example.com to everyone except alice@example.com",
+		}, hub.events)
+
+		clock.advance(time.Second)
+		require.NoError(t, socket.handleTypingStart(conn, start))
+		require.Len(t, hub.events, 3)
+
+		// other connection has its own limit
+		require.NoError(t, socket.handleTypingStop(NewUser(alice, nil), start))
+		require.Len(t, hub.events, 4)
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		typing, hub, _ := setup()
+		socket := &Websocket{hub: NewHub(zap.NewNop().Sugar()), typing: typing}
+		phone, laptop := NewUser(alice, nil), NewUser(alice, nil)
+		socket.hub.Join(phone)
+		socket.hub.Join(laptop)
+
+		typing.start(alice, models.PublicConversation)
+
+		// laptop is still connected and may be the one typing
+		socket.disconnect(phone)
+		require.Len(t, hub.events, 1)
+
+		socket.disconnect(laptop)
+		require.Equal(t, "typing_stop public by alice@example.com to everyone except alice@example.com", hub.events[1])
+	})
+}
@@ -2,10 +2,14 @@ package ws
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/playneta/go-sessions/src/commands"
 	"github.com/playneta/go-sessions/src/models"
 	"github.com/playneta/go-sessions/src/repositories"
 	"github.com/playneta/go-sessions/src/services"
@@ -16,28 +20,44 @@ import (
 
 type (
 	Websocket struct {
-		logger         *zap.SugaredLogger
-		config         *viper.Viper
-		userRepo       repositories.User
-		accountService services.Account
+		logger          *zap.SugaredLogger
+		config          *viper.Viper
+		userRepo        repositories.User
+		accountService  services.Account
+		reactionService services.Reaction
+		readService     services.Read
+		pinService      services.Pin
+		pollService     services.Poll
+		previewService  services.Preview
+		deliveryService services.Delivery
+		draftService    services.Draft
+		commands        *commands.Registry
+		hub             *Hub
+		handlers        map[string]handler
 
-		hub map[string]User
-		hmu sync.RWMutex
+		typing *typing
 	}
 
-	User struct {
-		Model *models.User
-		Conn  *websocket.Conn
-	}
+	// handler processes incoming event of a particular type
+	handler func(user *User, data json.RawMessage) error
 
 	Options struct {
 		fx.In
 
-		Logger         *zap.SugaredLogger
-		Config         *viper.Viper
-		Lc             fx.Lifecycle
-		UserRepo       repositories.User
-		AccountService services.Account
+		Logger          *zap.SugaredLogger
+		Config          *viper.Viper
+		Lc              fx.Lifecycle
+		UserRepo        repositories.User
+		AccountService  services.Account
+		ReactionService services.Reaction
+		ReadService     services.Read
+		PinService      services.Pin
+		PollService     services.Poll
+		PreviewService  services.Preview
+		DeliveryService services.Delivery
+		DraftService    services.Draft
+		Commands        *commands.Registry
+		Hub             *Hub
 	}
 )
 
@@ -50,12 +70,42 @@ var (
 )
 
 func New(opts Options) {
+	opts.Config.SetDefault("ws.read_limit", 64*1024)
+	opts.Config.SetDefault("ws.typing.ttl", 5*time.Second)
+	opts.Config.SetDefault("ws.typing.throttle", time.Second)
+
 	socket := &Websocket{
-		logger:         opts.Logger,
-		config:         opts.Config,
-		userRepo:       opts.UserRepo,
-		accountService: opts.AccountService,
-		hub:            make(map[string]User),
+		logger:          opts.Logger,
+		config:          opts.Config,
+		userRepo:        opts.UserRepo,
+		accountService:  opts.AccountService,
+		reactionService: opts.ReactionService,
+		readService:     opts.ReadService,
+		pinService:      opts.PinService,
+		pollService:     opts.PollService,
+		previewService:  opts.PreviewService,
+		deliveryService: opts.DeliveryService,
+		draftService:    opts.DraftService,
+		commands:        opts.Commands,
+		hub:             opts.Hub,
+		typing:          newTyping(opts.Hub, realClock{}, opts.Config.GetDuration("ws.typing.ttl"), opts.Config.GetDuration("ws.typing.throttle")),
+	}
+
+	opts.Hub.OnDelivered(func(receiver models.User, message models.Message) {
+		socket.delivered(receiver, []models.Message{message})
+	})
+
+	socket.handlers = map[string]handler{
+		"message":         socket.handleMessage,
+		"reaction_add":    socket.handleReactionAdd,
+		"reaction_remove": socket.handleReactionRemove,
+		"read":            socket.handleRead,
+		"typing_start":    socket.handleTypingStart,
+		"typing_stop":     socket.handleTypingStop,
+		"pin":             socket.handlePin,
+		"unpin":           socket.handleUnpin,
+		"poll":            socket.handlePoll,
+		"vote":            socket.handleVote,
 	}
 
 	opts.Lc.Append(fx.Hook{
@@ -85,6 +135,8 @@ func (s *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer c.Close()
+	// Frames above the limit close the connection instead of being buffered
+	c.SetReadLimit(s.config.GetInt64("ws.read_limit"))
 
 	// Authentication
 	token := r.URL.Query().Get("token")
@@ -95,86 +147,380 @@ func (s *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.hmu.Lock()
-	s.hub[user.Email] = User{
-		Model: user,
-		Conn:  c,
-	}
-	s.hmu.Unlock()
+	conn := NewUser(*user, c)
+	s.hub.Join(conn)
+	defer s.disconnect(conn)
 
-	// Sending history to user
+	// Sending history to user, user is already in hub so nothing posted
+	// meanwhile is lost, clients drop messages they already have by id
 	s.logger.Info("sending history to user")
-	messages, err := s.accountService.History(*user)
-	if err != nil {
+	if err := s.sendHistory(conn, r.URL.Query().Get("last_id")); err != nil {
 		s.logger.Errorf("error getting history: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	for _, message := range messages {
-		if err := c.WriteJSON(NewMessageEvent(message)); err != nil {
-			s.logger.Errorf("error sending history: %v", err)
-		}
-	}
 
 	// Sending all message of user join
-	for _, user := range s.hub {
-		if err := user.Conn.WriteJSON(Event{
-			Type: "join",
-			Data: MessageJoin{
-				User: user.Model.Email,
-			},
-		}); err != nil {
-			s.logger.Error("error sending join message: %v", err)
-			continue
-		}
-	}
+	s.hub.Broadcast(Event{
+		Type: "join",
+		Data: MessageJoin{
+			User: user.Email,
+		},
+	})
 
 	// Message handling
 	s.logger.Info("start listening for incoming messages")
 	for {
-		var msg MessageEvent
-		if err := c.ReadJSON(&msg); err != nil {
+		var frame json.RawMessage
+		if err := c.ReadJSON(&frame); err != nil {
 			s.logger.Errorf("error reading message: %v", err)
 			return
 		}
 
-		s.logger.Infof("got incoming message: %v", msg)
+		var event IncomingEvent
+		if err := json.Unmarshal(frame, &event); err != nil {
+			s.logger.Errorf("error decoding event: %v", err)
+			continue
+		}
+
+		if event.Type == "" {
+			event.Type, event.Data = "message", frame
+		}
 
-		// Creating message and saving it
-		message, err := s.accountService.CreateMessage(*user, msg.To, msg.Text)
-		if err != nil {
-			s.logger.Errorf("error saving message: %v", err)
+		handle, ok := s.handlers[event.Type]
+		if !ok {
+			s.logger.Errorf("unknown event type: %s", event.Type)
 			continue
 		}
 
-		if message.Receiver != nil {
-			s.hmu.RLock()
-			userTo, ok := s.hub[message.Receiver.Email]
-			s.hmu.RUnlock()
-
-			if !ok {
-				s.logger.Errorf("unknown user to send message: %s", msg.To)
-				continue
-			}
-
-			if err := c.WriteJSON(NewMessageEvent(*message)); err != nil {
-				s.logger.Errorf("error sending message: %v", err)
-			}
-
-			if err := userTo.Conn.WriteJSON(NewMessageEvent(*message)); err != nil {
-				s.logger.Errorf("error sending message: %v", err)
-			}
-		} else {
-			s.hmu.RLock()
-			for _, user := range s.hub {
-				if err := user.Conn.WriteJSON(NewMessageEvent(*message)); err != nil {
-					s.logger.Error("error sending message: %v", err)
-					continue
-				}
-			}
-			s.hmu.RUnlock()
+		if err := handle(conn, event.Data); err != nil {
+			s.logger.Errorf("error handling %s event: %v", event.Type, err)
 		}
 	}
 }
 
-// func (s *Websocket) handleMessage()
+// disconnect removes connection from hub, typing state belongs to user
+// so it is stopped only once their last device is gone
+func (s *Websocket) disconnect(conn *User) {
+	s.hub.Leave(conn)
+
+	user := conn.Model()
+	if _, ok := s.hub.Get(user.Email); !ok {
+		s.typing.leave(user)
+	}
+}
+
+// sendHistory pushes private messages queued while user was offline followed
+// by history, both may contain the same message so it is sent only once
+func (s *Websocket) sendHistory(user *User, lastID string) error {
+	queued, err := s.accountService.Queued(user.Model())
+	if err != nil {
+		return err
+	}
+
+	history, err := s.history(user, lastID)
+	if err != nil {
+		return err
+	}
+
+	sent := make(map[int64]bool, len(queued))
+	for _, message := range queued {
+		sent[message.Id] = true
+	}
+
+	messages := queued
+	for _, message := range history {
+		if !sent[message.Id] {
+			messages = append(messages, message)
+		}
+	}
+
+	s.sendMessages(user, messages)
+	s.delivered(user.Model(), messages)
+	return nil
+}
+
+// history returns recent history on first connect, reconnecting clients pass id
+// of the last message they saw and get exactly what they missed instead
+func (s *Websocket) history(user *User, lastID string) ([]models.Message, error) {
+	id, err := strconv.ParseInt(lastID, 10, 64)
+	if err != nil || id <= 0 {
+		return s.accountService.History(user.Model())
+	}
+
+	page, err := s.accountService.CatchUp(user.Model(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gap is too large to replay, client pages it over REST
+	if page.Before > 0 {
+		if err := user.Send(Event{
+			Type: "history_truncated",
+			Data: HistoryTruncatedEvent{
+				LastId: id,
+				Before: page.Before,
+			},
+		}); err != nil {
+			s.logger.Errorf("error sending history: %v", err)
+		}
+	}
+
+	return page.Messages, nil
+}
+
+func (s *Websocket) sendMessages(user *User, messages []models.Message) {
+	for _, message := range messages {
+		if err := user.Send(NewMessageEvent(message)); err != nil {
+			s.logger.Errorf("error sending history: %v", err)
+		}
+	}
+}
+
+// delivered records private messages pushed to receiver, failures only delay
+// delivered events since messages stay queued for the next connect
+func (s *Websocket) delivered(receiver models.User, messages []models.Message) {
+	if err := s.deliveryService.Delivered(receiver, messages); err != nil {
+		s.logger.Errorf("error marking messages delivered: %v", err)
+	}
+}
+
+func (s *Websocket) handleMessage(user *User, data json.RawMessage) error {
+	var msg MessageEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	s.logger.Infof("got incoming message: %v", msg)
+
+	var (
+		message *models.Message
+		err     error
+	)
+
+	if name, args, ok := commands.Parse(msg.Text); ok {
+		message, err = s.handleCommand(user, msg.ClientMsgId, msg.To, name, args)
+	} else {
+		// Double slash escapes command, message is sent with a single one
+		if strings.HasPrefix(msg.Text, "//") {
+			msg.Text = msg.Text[1:]
+		}
+
+		message, err = s.send(user, services.MessageRequest{
+			ClientMsgId: msg.ClientMsgId,
+			To:          msg.To,
+			Text:        msg.Text,
+			Format:      msg.Format,
+			TTL:         time.Duration(msg.TTL) * time.Second,
+			Attachments: msg.AttachmentIds,
+			RefId:       msg.RefId,
+			RefKind:     msg.RefKind,
+		})
+	}
+
+	return s.acknowledge(user, msg.ClientMsgId, message, err)
+}
+
+// acknowledge tells sender whether message was stored, retried sends are
+// acknowledged with the message stored before, failures are reported
+// to every sender while acks only to those who set client message id
+func (s *Websocket) acknowledge(user *User, clientMsgID string, message *models.Message, err error) error {
+	if err != nil && err != services.ErrDuplicateMessage {
+		if sendErr := user.Send(Event{
+			Type: "error",
+			Data: MessageError{
+				ClientMsgId: clientMsgID,
+				Error:       err.Error(),
+			},
+		}); sendErr != nil {
+			s.logger.Errorf("error sending error event: %v", sendErr)
+		}
+
+		return err
+	}
+
+	if clientMsgID == "" {
+		return nil
+	}
+
+	ack := AckEvent{
+		ClientMsgId: clientMsgID,
+		DateTime:    time.Now(),
+	}
+
+	if message != nil {
+		ack.Id = message.Id
+		ack.DateTime = message.CreatedAt
+	}
+
+	return user.Send(Event{Type: "ack", Data: ack})
+}
+
+// handleCommand runs slash command and returns message it sent, if any
+func (s *Websocket) handleCommand(user *User, clientMsgID, to, name, args string) (*models.Message, error) {
+	result, err := s.commands.Run(name, commands.Call{
+		User: user.Model(),
+		To:   to,
+		Args: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.User != nil {
+		s.hub.Update(user, *result.User)
+	}
+
+	if result.Reply != "" {
+		if err := user.Send(Event{
+			Type: "system",
+			Data: SystemEvent{
+				To:       to,
+				Text:     result.Reply,
+				DateTime: time.Now(),
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Message == nil {
+		return nil, nil
+	}
+
+	req := *result.Message
+	req.ClientMsgId = clientMsgID
+	return s.send(user, req)
+}
+
+// send creates message on behalf of user and delivers it,
+// retried sends are not delivered again
+func (s *Websocket) send(user *User, req services.MessageRequest) (*models.Message, error) {
+	message, err := s.accountService.CreateMessage(user.Model(), req)
+	if err != nil {
+		return message, err
+	}
+
+	s.typing.stop(user.Model(), message.Conversation())
+	if err := s.draftService.Delete(user.Model(), message.Conversation()); err != nil && err != services.ErrDraftNotFound {
+		s.logger.Errorf("error clearing draft: %v", err)
+	}
+
+	s.hub.Message(*message)
+	s.previewService.Unfurl(*message)
+	return message, nil
+}
+
+func (s *Websocket) handleReactionAdd(user *User, data json.RawMessage) error {
+	var msg ReactionEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	_, err := s.reactionService.Add(user.Model(), msg.MessageId, msg.Emoji)
+	return err
+}
+
+func (s *Websocket) handleReactionRemove(user *User, data json.RawMessage) error {
+	var msg ReactionEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	_, err := s.reactionService.Remove(user.Model(), msg.MessageId, msg.Emoji)
+	return err
+}
+
+func (s *Websocket) handleRead(user *User, data json.RawMessage) error {
+	var msg ReadEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	_, err := s.readService.MarkRead(user.Model(), msg.MessageId)
+	return err
+}
+
+func (s *Websocket) handleTypingStart(user *User, data json.RawMessage) error {
+	if !s.typing.allow(user) {
+		return nil
+	}
+
+	var msg TypingEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	if !models.ConversationVisibleTo(msg.Conversation, user.Model()) {
+		return services.ErrConversationNotFound
+	}
+
+	s.typing.start(user.Model(), msg.Conversation)
+	return nil
+}
+
+func (s *Websocket) handleTypingStop(user *User, data json.RawMessage) error {
+	if !s.typing.allow(user) {
+		return nil
+	}
+
+	var msg TypingEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	s.typing.stop(user.Model(), msg.Conversation)
+	return nil
+}
+
+func (s *Websocket) handlePin(user *User, data json.RawMessage) error {
+	var msg PinEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	_, err := s.pinService.Pin(user.Model(), msg.MessageId)
+	return err
+}
+
+func (s *Websocket) handleUnpin(user *User, data json.RawMessage) error {
+	var msg PinEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	return s.pinService.Unpin(user.Model(), msg.MessageId)
+}
+
+func (s *Websocket) handlePoll(user *User, data json.RawMessage) error {
+	var msg PollEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	message, err := s.pollService.Create(user.Model(), services.PollRequest{
+		To:        msg.To,
+		Text:      msg.Text,
+		Format:    msg.Format,
+		Options:   msg.Options,
+		Multiple:  msg.Multiple,
+		Anonymous: msg.Anonymous,
+		ClosesAt:  msg.ClosesAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.typing.stop(user.Model(), message.Conversation())
+	return nil
+}
+
+func (s *Websocket) handleVote(user *User, data json.RawMessage) error {
+	var msg VoteEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	_, err := s.pollService.Vote(user.Model(), msg.MessageId, msg.Options)
+	return err
+}